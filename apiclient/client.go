@@ -0,0 +1,117 @@
+package apiclient
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// NewHTTPClient returns an *http.Client that authenticates every request to
+// cfg.URL with an access token obtained from cfg's own credentials.
+//
+// Each call builds an independent client: its own token, its own transport,
+// nothing shared with any other. That is the whole point -- two provider
+// blocks configured for two tenants get two of these and cannot see each
+// other's credentials.
+func NewHTTPClient(cfg Config) (*http.Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	apiURL, _ := url.Parse(cfg.URL)
+
+	// Retries sit beneath authentication, so a re-sent request carries the
+	// same token as the first and the token request is retried like any
+	// other. The rate limiter sits beneath retries, so every attempt waits
+	// its turn and every response's budget headers are seen.
+	// Logging sits at the bottom, so each attempt is logged as it was sent,
+	// token exchange included, and its latency is the API's alone. Beneath it
+	// all is the transport that carries the proxy and TLS settings, so the
+	// token request goes the same way as every other.
+	transport, err := newTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var base http.RoundTripper = transport
+	if cfg.LogContext != nil {
+		base = newLoggingTransport(cfg, base)
+	}
+	limited := &rateLimitTransport{limiter: newRateLimiter(cfg.MaxRequestsPerSecond), next: base}
+	retry := newRetryTransport(limited, cfg)
+
+	return &http.Client{
+		Timeout: cfg.Timeout,
+		Transport: &authTransport{
+			host:   apiURL.Host,
+			next:   retry,
+			tokens: newTokenSource(cfg, retry),
+		},
+	}, nil
+}
+
+// newBaseTransport is the transport underneath everything else. A fresh one
+// rather than http.DefaultTransport, which is process-wide, so nothing one
+// provider instance does to its transport reaches another.
+func newBaseTransport() *http.Transport {
+	return http.DefaultTransport.(*http.Transport).Clone()
+}
+
+// authTransport attaches the access token to requests bound for the API host.
+type authTransport struct {
+	host   string
+	tokens *tokenSource
+	next   http.RoundTripper
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Only the configured host gets the token. Anything else this client is
+	// asked to fetch -- a metadata document on another domain, say -- has no
+	// business seeing it.
+	if req.URL.Host != t.host {
+		return t.next.RoundTrip(req)
+	}
+
+	tok, err := t.tokens.Token(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.next.RoundTrip(withToken(req, tok))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// A 401 for a token that was valid by its own expiry means it was
+	// revoked, or was taken from another process's cache and the credentials
+	// have since been rotated. Once, with a fresh one, unless the body cannot
+	// be sent again.
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, nil
+	}
+	t.tokens.Invalidate(tok)
+	fresh, err := t.tokens.Token(req.Context())
+	if err != nil || fresh == tok {
+		return resp, nil
+	}
+
+	retry := withToken(req, fresh)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return t.next.RoundTrip(retry)
+}
+
+// withToken returns a copy of req carrying tok. A RoundTripper must not modify
+// the request it was given.
+func withToken(req *http.Request, tok string) *http.Request {
+	out := req.Clone(req.Context())
+	out.Header.Set("Authorization", "Bearer "+tok)
+	return out
+}
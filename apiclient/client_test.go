@@ -0,0 +1,269 @@
+package apiclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeTenant is a local stand-in for one OneLogin account: a token endpoint
+// that only honours its own credentials, and an API that only honours the
+// token it issued.
+type fakeTenant struct {
+	*httptest.Server
+
+	name, clientID, clientSecret, token string
+
+	tokenCalls atomic.Int32
+	apiCalls   atomic.Int32
+	// foreign counts requests that arrived with somebody else's credentials
+	// or token. Any at all is the bug.
+	foreign atomic.Int32
+}
+
+func newFakeTenant(t *testing.T, name string) *fakeTenant {
+	t.Helper()
+
+	ft := newFakeTenantHandler(name)
+	ft.Server = httptest.NewServer(ft)
+	t.Cleanup(ft.Close)
+	t.Cleanup(forgetTokens)
+	return ft
+}
+
+// forgetTokens empties the process-wide token caches. A later test's server
+// can be given a port an earlier one used, and with the same credentials
+// would otherwise start out with its token.
+func forgetTokens() {
+	tokenCaches.Lock()
+	defer tokenCaches.Unlock()
+	tokenCaches.m = map[string]*tokenCache{}
+}
+
+// newFakeTenantHandler is a fakeTenant without a server, for a test that
+// needs to start one of its own.
+func newFakeTenantHandler(name string) *fakeTenant {
+	return &fakeTenant{
+		name:         name,
+		clientID:     name + "-id",
+		clientSecret: name + "-secret",
+		token:        name + "-token",
+	}
+}
+
+func (ft *fakeTenant) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == tokenPath {
+		ft.tokenCalls.Add(1)
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != ft.clientID || secret != ft.clientSecret {
+			ft.foreign.Add(1)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": ft.token,
+			"expires_in":   36000,
+			"token_type":   "bearer",
+		})
+		return
+	}
+
+	ft.apiCalls.Add(1)
+	if r.Header.Get("Authorization") != "Bearer "+ft.token {
+		ft.foreign.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"tenant": ft.name})
+}
+
+func (ft *fakeTenant) config() Config {
+	return Config{ClientID: ft.clientID, ClientSecret: ft.clientSecret, URL: ft.URL, Timeout: 5 * time.Second}
+}
+
+// TestTenantsDoNotCross covers aliased provider blocks for two accounts in one
+// plugin process. Configuration used to go through ONELOGIN_* environment
+// variables, so whichever block configured last supplied the credentials for
+// both.
+func TestTenantsDoNotCross(t *testing.T) {
+	us, eu := newFakeTenant(t, "us"), newFakeTenant(t, "eu")
+
+	// Built concurrently, the way Terraform configures provider instances.
+	clients := make([]*http.Client, 2)
+	var build sync.WaitGroup
+	for i, ft := range []*fakeTenant{us, eu} {
+		build.Add(1)
+		go func(i int, ft *fakeTenant) {
+			defer build.Done()
+			c, err := NewHTTPClient(ft.config())
+			if err != nil {
+				t.Errorf("building client for %s: %v", ft.URL, err)
+				return
+			}
+			clients[i] = c
+		}(i, ft)
+	}
+	build.Wait()
+	if t.Failed() {
+		t.FailNow()
+	}
+
+	const perTenant = 25
+	var calls sync.WaitGroup
+	for i, ft := range []*fakeTenant{us, eu} {
+		for n := 0; n < perTenant; n++ {
+			calls.Add(1)
+			go func(c *http.Client, ft *fakeTenant) {
+				defer calls.Done()
+				resp, err := c.Get(ft.URL + "/api/2/roles/1")
+				if err != nil {
+					t.Errorf("request to %s: %v", ft.URL, err)
+					return
+				}
+				resp.Body.Close()
+				if resp.StatusCode != http.StatusOK {
+					t.Errorf("request to %s: status %d", ft.URL, resp.StatusCode)
+				}
+			}(clients[i], ft)
+		}
+	}
+	calls.Wait()
+
+	for _, ft := range []*fakeTenant{us, eu} {
+		if n := ft.foreign.Load(); n != 0 {
+			t.Fatalf("%s received %d requests carrying another tenant's credentials", ft.clientID, n)
+		}
+		if n := ft.apiCalls.Load(); n != perTenant {
+			t.Fatalf("%s: expected %d API calls, got %d", ft.clientID, perTenant, n)
+		}
+		// Parallel requests share the one token rather than each fetching
+		// their own.
+		if n := ft.tokenCalls.Load(); n != 1 {
+			t.Fatalf("%s: expected a single token request, got %d", ft.clientID, n)
+		}
+	}
+}
+
+// TestNewHTTPClientLeavesEnvironmentAlone pins the other half of the fix:
+// configuring a client must not write anything another instance could read.
+func TestNewHTTPClientLeavesEnvironmentAlone(t *testing.T) {
+	ft := newFakeTenant(t, "solo")
+	before := os.Environ()
+
+	if _, err := NewHTTPClient(ft.config()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	after := os.Environ()
+	if fmt.Sprint(before) != fmt.Sprint(after) {
+		t.Fatal("expected building a client to leave the process environment unchanged")
+	}
+}
+
+// TestTokenStaysOnTheAPIHost keeps the bearer token away from any other host
+// the same client is used to reach.
+func TestTokenStaysOnTheAPIHost(t *testing.T) {
+	ft := newFakeTenant(t, "home")
+
+	var leaked atomic.Bool
+	elsewhere := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			leaked.Store(true)
+		}
+	}))
+	defer elsewhere.Close()
+
+	c, err := NewHTTPClient(ft.config())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := c.Get(elsewhere.URL + "/metadata.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if leaked.Load() {
+		t.Fatal("expected no Authorization header on a request to another host")
+	}
+	if n := ft.tokenCalls.Load(); n != 0 {
+		t.Fatalf("expected no token to be fetched for another host, got %d fetches", n)
+	}
+}
+
+func TestTokenRefreshedBeforeExpiry(t *testing.T) {
+	ft := newFakeTenant(t, "clock")
+
+	now := time.Now()
+	cfg := ft.config()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	src := newTokenSource(cfg, newBaseTransport())
+	src.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if _, err := src.Token(t.Context()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if n := ft.tokenCalls.Load(); n != 1 {
+		t.Fatalf("expected one fetch while the token is fresh, got %d", n)
+	}
+
+	// Inside the margin, not past the expiry: the token is refreshed early
+	// rather than sent and refused.
+	now = now.Add(36000*time.Second - tokenExpiryMargin/2)
+	if _, err := src.Token(t.Context()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := ft.tokenCalls.Load(); n != 2 {
+		t.Fatalf("expected a refresh inside the expiry margin, got %d fetches", n)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		cfg     Config
+		wantURL string
+		wantErr bool
+	}{
+		"missing credentials": {cfg: Config{URL: "https://api.us.onelogin.com"}, wantErr: true},
+		"missing url":         {cfg: Config{ClientID: "id", ClientSecret: "secret"}, wantErr: true},
+		"unsupported scheme":  {cfg: Config{ClientID: "id", ClientSecret: "secret", URL: "ftp://api.us.onelogin.com"}, wantErr: true},
+		"trailing slash": {
+			cfg:     Config{ClientID: "id", ClientSecret: "secret", URL: "https://api.us.onelogin.com/"},
+			wantURL: "https://api.us.onelogin.com",
+		},
+		// A bare host has always been accepted for url.
+		"bare host": {
+			cfg:     Config{ClientID: "id", ClientSecret: "secret", URL: "chicken.onelogin.com"},
+			wantURL: "https://chicken.onelogin.com",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.cfg.URL != tc.wantURL {
+				t.Fatalf("expected url %q, got %q", tc.wantURL, tc.cfg.URL)
+			}
+			if tc.cfg.Timeout != DefaultTimeout {
+				t.Fatalf("expected the default timeout, got %v", tc.cfg.Timeout)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+// Package apiclient builds the HTTP client one provider instance uses to talk
+// to one OneLogin tenant.
+//
+// Everything a request needs -- credentials, base URL, timeout, the access
+// token -- is held by the client itself rather than read from the process
+// environment. Terraform runs every aliased provider block in the same plugin
+// process, so anything kept in the environment is shared between them, and two
+// blocks pointing at different tenants overwrite each other's credentials.
+// The one thing clients share is an access token, and only between clients
+// built for the same credentials and URL.
+package apiclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout is used when a Config leaves Timeout unset. It matches the
+// provider's own default for its timeout argument.
+const DefaultTimeout = 180 * time.Second
+
+var (
+	errClientCredentials = errors.New("client_id or client_secret missing")
+	errURLRequired       = errors.New("a OneLogin API URL is required")
+)
+
+// Config is everything needed to reach one tenant.
+type Config struct {
+	ClientID     string
+	ClientSecret string
+
+	// URL is the API base URL, e.g. https://api.us.onelogin.com. Paths such as
+	// /api/2/roles and /auth/oauth2/v2/token are resolved against it.
+	URL string
+
+	// Timeout bounds a single call through the client, including reading its
+	// body and any retries it takes.
+	Timeout time.Duration
+
+	// MaxRetries is how many times a transient failure is re-sent before it
+	// is returned. Zero means never.
+	MaxRetries int
+
+	// MinBackoff and MaxBackoff bound the wait between attempts. A Retry-After
+	// header from the API takes precedence over both.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// MaxRequestsPerSecond caps how fast requests are sent, across everything
+	// the client does. Zero leaves only the API's own rate-limit headers to
+	// pace it.
+	MaxRequestsPerSecond float64
+
+	// ProxyURL, when set, is the proxy every request is sent through, HTTPS
+	// ones by CONNECT. When empty, HTTPS_PROXY, HTTP_PROXY and NO_PROXY apply
+	// as they do for any Go program.
+	ProxyURL string
+
+	// CACertPEM and CACertFile add certificates, PEM-encoded, to the system
+	// roots the API's certificate is verified against: a TLS-inspecting
+	// proxy's, or a private endpoint's. Either or both may be set.
+	CACertPEM  string
+	CACertFile string
+
+	// ClientCertPEM and ClientKeyPEM, or ClientCertFile and ClientKeyFile, are
+	// the certificate and key presented to a server that asks for one. A
+	// certificate and its key are given together, the same way.
+	ClientCertPEM  string
+	ClientKeyPEM   string
+	ClientCertFile string
+	ClientKeyFile  string
+
+	// InsecureSkipVerify accepts any certificate the server presents. It is
+	// for a lab with a self-signed endpoint, never for a real tenant: the
+	// client secret and every token are sent to whoever answers.
+	InsecureSkipVerify bool
+
+	// TokenCacheDir, when set, is a directory the access token is kept in
+	// between runs, so that separate plugin processes for the same client and
+	// API host reuse one token rather than each fetching their own. The file
+	// is readable by its owner only. Within a process the token is shared
+	// between clients with the same credentials whether or not this is set.
+	TokenCacheDir string
+
+	// LogContext, when set, turns on logging of every request and response,
+	// redacted, to the LogSubsystem subsystem of the logger it carries. It is
+	// held for the life of the client, so it should be one whose logger
+	// outlives the call that set it up -- the provider's configure context.
+	LogContext context.Context
+}
+
+// Validate reports the first thing wrong with c, and normalises the URL so a
+// trailing slash does not end up doubled in front of every path.
+func (c *Config) Validate() error {
+	if c.ClientID == "" || c.ClientSecret == "" {
+		return errClientCredentials
+	}
+	if c.URL == "" {
+		return errURLRequired
+	}
+
+	// A bare host has always been accepted for the provider's url, so it
+	// keeps meaning what it meant: https.
+	if !strings.Contains(c.URL, "://") {
+		c.URL = "https://" + c.URL
+	}
+
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return fmt.Errorf("invalid OneLogin API URL %q: %w", c.URL, err)
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return fmt.Errorf("invalid OneLogin API URL %q: expected an http or https URL", c.URL)
+	}
+	c.URL = strings.TrimRight(c.URL, "/")
+
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultTimeout
+	}
+
+	if c.MaxRetries < 0 {
+		return fmt.Errorf("max_retries must be 0 or greater, got %d", c.MaxRetries)
+	}
+	if c.MinBackoff <= 0 {
+		c.MinBackoff = DefaultMinBackoff
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = DefaultMaxBackoff
+	}
+	if c.MaxRequestsPerSecond < 0 {
+		return fmt.Errorf("max_requests_per_second must be 0 or greater, got %v", c.MaxRequestsPerSecond)
+	}
+	if c.MaxBackoff < c.MinBackoff {
+		return fmt.Errorf("max_backoff (%s) must not be less than min_backoff (%s)", c.MaxBackoff, c.MinBackoff)
+	}
+
+	if c.ProxyURL != "" {
+		if _, err := parseProxyURL(c.ProxyURL); err != nil {
+			return err
+		}
+	}
+	if c.ClientCertPEM != "" && c.ClientCertFile != "" {
+		return errors.New("only one of client_cert_pem and client_cert_file may be set")
+	}
+	if c.ClientKeyPEM != "" && c.ClientKeyFile != "" {
+		return errors.New("only one of client_key_pem and client_key_file may be set")
+	}
+	hasCert := c.ClientCertPEM != "" || c.ClientCertFile != ""
+	hasKey := c.ClientKeyPEM != "" || c.ClientKeyFile != ""
+	if hasCert != hasKey {
+		return errors.New("a client certificate and its key must be given together")
+	}
+	return nil
+}
@@ -0,0 +1,253 @@
+package apiclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// LogSubsystem is the tflog subsystem API traffic is written to. Its level can
+// be set apart from the rest of the provider's with
+// TF_LOG_PROVIDER_ONELOGIN_API.
+const LogSubsystem = "api"
+
+// maxLoggedBody is how much of a body is logged. Listing every user in a large
+// tenant produces megabytes nobody reads.
+const maxLoggedBody = 64 << 10
+
+const redacted = "***"
+
+// sensitiveKeys are JSON keys whose values are never logged, wherever they
+// appear in a body. Compared in lower case.
+var sensitiveKeys = map[string]bool{
+	"access_token":          true,
+	"refresh_token":         true,
+	"id_token":              true,
+	"client_secret":         true,
+	"password":              true,
+	"password_confirmation": true,
+	"salt":                  true,
+	"secret":                true,
+}
+
+// certificateKeys hold a certificate either as a string or as an object with
+// the PEM in its value.
+var certificateKeys = map[string]bool{
+	"certificate":      true,
+	"x509_certificate": true,
+	"x509cert":         true,
+}
+
+// sensitiveHeaders are logged with only their scheme, if they have one.
+var sensitiveHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// pemBlock matches a PEM-encoded certificate or key anywhere in a string, as a
+// backstop for one under a key not listed above.
+var pemBlock = regexp.MustCompile(`-----BEGIN [A-Z ]+-----[^-]*-----END [A-Z ]+-----`)
+
+// loggingTransport writes every exchange with the API -- method, path, status,
+// latency, headers and bodies -- to the api log subsystem, with credentials,
+// tokens, passwords, smart hook variable values and certificates masked.
+//
+// It logs through the context it was built with rather than the request's.
+// Most SDK calls make their requests with context.Background(), which carries
+// no logger, and tflog quietly drops anything written to it.
+type loggingTransport struct {
+	ctx  context.Context
+	next http.RoundTripper
+	now  func() time.Time
+}
+
+func newLoggingTransport(cfg Config, next http.RoundTripper) *loggingTransport {
+	ctx := tflog.NewSubsystem(cfg.LogContext, LogSubsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER_ONELOGIN", LogSubsystem))
+	// Whatever the structural masking below might miss, the secret itself
+	// never reaches the log.
+	if cfg.ClientSecret != "" {
+		ctx = tflog.SubsystemMaskAllFieldValuesStrings(ctx, LogSubsystem, cfg.ClientSecret)
+	}
+	return &loggingTransport{ctx: ctx, next: next, now: time.Now}
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, out, err := captureRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]interface{}{
+		"method":          req.Method,
+		"path":            req.URL.RequestURI(),
+		"request_headers": redactHeaders(req.Header),
+		"request_body":    redactBody(req.URL.Path, reqBody),
+	}
+
+	start := t.now()
+	resp, err := t.next.RoundTrip(out)
+	fields["duration_ms"] = t.now().Sub(start).Milliseconds()
+
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.SubsystemDebug(t.ctx, LogSubsystem, "OneLogin API request failed", fields)
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	// Whatever was read is handed on either way, so a failed read surfaces
+	// to the caller exactly as it would have without logging.
+	resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(respBody), errReader{err}))
+
+	fields["status"] = resp.StatusCode
+	fields["response_headers"] = redactHeaders(resp.Header)
+	fields["response_body"] = redactBody(req.URL.Path, respBody)
+	tflog.SubsystemDebug(t.ctx, LogSubsystem, "OneLogin API request", fields)
+
+	return resp, nil
+}
+
+// captureRequestBody returns a copy of req's body and a request to send in its
+// place whose body has not been consumed. A RoundTripper must not modify the
+// request it was given.
+func captureRequestBody(req *http.Request) ([]byte, *http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, req, nil
+	}
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, nil, err
+		}
+		defer body.Close()
+		raw, err := io.ReadAll(body)
+		return raw, req, err
+	}
+
+	raw, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(raw))
+	return raw, out, nil
+}
+
+// errReader returns err once the body it trails has been read, or io.EOF.
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return 0, io.EOF
+}
+
+func redactHeaders(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for k, v := range h {
+		out[k] = strings.Join(v, ", ")
+	}
+	for _, k := range sensitiveHeaders {
+		v := h.Get(k)
+		if v == "" {
+			continue
+		}
+		if scheme, _, ok := strings.Cut(v, " "); ok && (scheme == "Bearer" || scheme == "Basic") {
+			out[http.CanonicalHeaderKey(k)] = scheme + " " + redacted
+		} else {
+			out[http.CanonicalHeaderKey(k)] = redacted
+		}
+	}
+	return out
+}
+
+// redactBody returns body ready to be logged. JSON is masked key by key, so
+// what was sent stays readable around what was not; anything else only has
+// PEM blocks taken out.
+func redactBody(path string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return truncateBody(pemBlock.ReplaceAllString(string(body), redacted))
+	}
+
+	// A smart hook environment variable is a name and a value, and the value
+	// is the secret the hook needs: an API key, a password.
+	envVars := strings.Contains(path, "/hooks/envs")
+
+	out, err := json.Marshal(redactValue(v, envVars))
+	if err != nil {
+		return fmt.Sprintf("<unloggable body: %v>", err)
+	}
+	return truncateBody(string(out))
+}
+
+func redactValue(v interface{}, envVars bool) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			key := strings.ToLower(k)
+			switch {
+			case val == nil:
+				out[k] = nil
+			case sensitiveKeys[key], envVars && key == "value":
+				out[k] = redacted
+			case certificateKeys[key]:
+				out[k] = redactCertificate(val)
+			default:
+				out[k] = redactValue(val, envVars)
+			}
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, val := range v {
+			out[i] = redactValue(val, envVars)
+		}
+		return out
+	case string:
+		return pemBlock.ReplaceAllString(v, redacted)
+	default:
+		return v
+	}
+}
+
+// redactCertificate masks a certificate given either as a string or as an
+// object like {"id": 1, "name": "...", "value": "-----BEGIN..."}, keeping the
+// object's other fields.
+func redactCertificate(v interface{}) interface{} {
+	cert, ok := v.(map[string]interface{})
+	if !ok {
+		return redacted
+	}
+	out := make(map[string]interface{}, len(cert))
+	for k, val := range cert {
+		out[k] = val
+	}
+	if _, ok := out["value"]; ok {
+		out["value"] = redacted
+	}
+	return redactValue(out, false)
+}
+
+func truncateBody(s string) string {
+	if len(s) <= maxLoggedBody {
+		return s
+	}
+	return fmt.Sprintf("%s... (%d bytes more)", s[:maxLoggedBody], len(s)-maxLoggedBody)
+}
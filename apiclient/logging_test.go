@@ -0,0 +1,171 @@
+package apiclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+const testCertificate = "-----BEGIN CERTIFICATE-----\nMIIC4jCCAcqgAwIBAgIQ\n-----END CERTIFICATE-----"
+
+// TestLoggingMasksSecrets sends the kinds of thing that must never reach a log
+// through a logging client, and checks the log shows the exchange without
+// them.
+func TestLoggingMasksSecrets(t *testing.T) {
+	var received []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case tokenPath:
+			json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "the-access-token", "refresh_token": "the-refresh-token", "expires_in": 36000})
+		case "/api/2/hooks/envs":
+			received, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"5a1c","name":"API_KEY","value":"the-env-value"}`))
+		default:
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"name": "SAML", "sso": map[string]interface{}{"certificate": map[string]interface{}{"id": 7, "name": "Std", "value": testCertificate}},
+			})
+		}
+	}))
+	defer srv.Close()
+
+	var logs bytes.Buffer
+	hc, err := NewHTTPClient(Config{
+		ClientID:     "the-client-id",
+		ClientSecret: "the-client-secret",
+		URL:          srv.URL,
+		Timeout:      5 * time.Second,
+		LogContext:   tflogtest.RootLogger(context.Background(), &logs),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resp, err := hc.Post(srv.URL+"/api/2/hooks/envs", "application/json", strings.NewReader(`{"name":"API_KEY","value":"the-env-value"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	// Logging must not cost the caller or the API anything.
+	if !strings.Contains(string(body), "the-env-value") {
+		t.Fatalf("expected the caller to still get the whole response, got %s", body)
+	}
+	if !strings.Contains(string(received), "the-env-value") {
+		t.Fatalf("expected the API to still get the whole request, got %s", received)
+	}
+
+	resp, err = hc.Post(srv.URL+"/api/2/users", "application/json", strings.NewReader(`{"username":"jdoe","password":"hunter2","password_confirmation":"hunter2"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	out := logs.String()
+	entries, err := tflogtest.MultilineJSONDecode(&logs)
+	if err != nil {
+		t.Fatalf("decoding logs: %v", err)
+	}
+
+	for _, secret := range []string{"the-client-secret", "the-access-token", "the-refresh-token", "the-env-value", "hunter2", "MIIC4jCCAcqgAwIBAgIQ"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("expected %q to be masked, got:\n%s", secret, out)
+		}
+	}
+
+	var exchanges []map[string]interface{}
+	for _, e := range entries {
+		if e["@module"] == "provider."+LogSubsystem {
+			exchanges = append(exchanges, e)
+		}
+	}
+	if len(exchanges) != 3 {
+		t.Fatalf("expected the token request and two API requests logged, got %d:\n%s", len(exchanges), out)
+	}
+
+	users := exchanges[2]
+	if users["method"] != "POST" || users["path"] != "/api/2/users" || users["status"] != float64(http.StatusUnprocessableEntity) {
+		t.Fatalf("expected the method, path and status logged, got %v", users)
+	}
+	if _, ok := users["duration_ms"]; !ok {
+		t.Fatalf("expected the latency logged, got %v", users)
+	}
+	if !strings.Contains(users["request_body"].(string), `"username":"jdoe"`) {
+		t.Fatalf("expected what is not secret to be left readable, got %v", users["request_body"])
+	}
+	if got := users["request_headers"].(map[string]interface{})["Authorization"]; got != "Bearer ***" {
+		t.Fatalf("expected the bearer token masked, got %v", got)
+	}
+	if !strings.Contains(users["response_body"].(string), `"name":"Std"`) {
+		t.Fatalf("expected the certificate's other fields left readable, got %v", users["response_body"])
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	tests := map[string]struct {
+		path, body, want string
+	}{
+		"keeps large IDs exact": {
+			path: "/api/2/users",
+			body: `{"id":123456789012345678}`,
+			want: `{"id":123456789012345678}`,
+		},
+		"masks a value only on environment variables": {
+			path: "/api/2/apps/1",
+			body: `{"parameters":{"email":{"value":"x"}}}`,
+			want: `{"parameters":{"email":{"value":"x"}}}`,
+		},
+		"masks a certificate given as a string": {
+			path: "/api/2/apps/1",
+			body: `{"certificate":"MIIC"}`,
+			want: `{"certificate":"***"}`,
+		},
+		"masks PEM anywhere in a body that is not JSON": {
+			path: "/saml/metadata",
+			body: "<X509Certificate>" + testCertificate + "</X509Certificate>",
+			want: "<X509Certificate>***</X509Certificate>",
+		},
+		"leaves null secrets alone": {
+			path: "/api/2/users",
+			body: `{"password":null}`,
+			want: `{"password":null}`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := redactBody(tt.path, []byte(tt.body)); got != tt.want {
+				t.Fatalf("want %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRedactBodyTruncates(t *testing.T) {
+	got := redactBody("/x", []byte(strings.Repeat("a", maxLoggedBody+10)))
+	if !strings.HasSuffix(got, "(10 bytes more)") {
+		t.Fatalf("expected a long body cut short, got %d bytes ending %q", len(got), got[len(got)-20:])
+	}
+}
+
+func TestLoggingOffByDefault(t *testing.T) {
+	hc, err := NewHTTPClient(Config{ClientID: "id", ClientSecret: "secret", URL: "https://api.us.onelogin.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rl := hc.Transport.(*authTransport).next.(*retryTransport).next.(*rateLimitTransport)
+	if _, ok := rl.next.(*loggingTransport); ok {
+		t.Fatal("expected no logging without a LogContext")
+	}
+}
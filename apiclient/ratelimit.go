@@ -0,0 +1,188 @@
+package apiclient
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// pacingThreshold is the share of the window's budget below which requests
+// start being spread over what is left of the window. Above it there is room
+// to spare and pacing would only slow a plan down for nothing.
+const pacingThreshold = 0.5
+
+// rateLimiter is a token bucket shared by every request one client makes.
+//
+// It has two sources for its rate. The configured cap, if any, always applies.
+// On top of that it reads the X-RateLimit-* headers OneLogin returns: once less
+// than half the window's budget remains, requests are spaced so what is left
+// lasts until the window resets, and once nothing remains they wait for the
+// reset outright. The aim is never to see the 429 at all -- Terraform's
+// parallelism will otherwise spend a large tenant's whole budget in the first
+// few seconds of a plan, and everything after that is a retry.
+type rateLimiter struct {
+	mu sync.Mutex
+
+	// maxRate is the configured cap in requests per second; 0 is none.
+	maxRate float64
+	// budgetRate is the rate the headers last allowed; 0 is none.
+	budgetRate float64
+
+	tokens float64
+	last   time.Time
+	// exhaustedUntil is when the API said the spent budget comes back.
+	exhaustedUntil time.Time
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func newRateLimiter(maxRate float64) *rateLimiter {
+	return &rateLimiter{
+		maxRate: maxRate,
+		tokens:  burstFor(maxRate),
+		now:     time.Now,
+		sleep:   sleepContext,
+	}
+}
+
+// rate is the one currently in force: the lower of the cap and the budget,
+// ignoring whichever is unset. 0 means requests are not being limited.
+func (l *rateLimiter) rate() float64 {
+	switch {
+	case l.maxRate == 0:
+		return l.budgetRate
+	case l.budgetRate == 0:
+		return l.maxRate
+	case l.budgetRate < l.maxRate:
+		return l.budgetRate
+	}
+	return l.maxRate
+}
+
+// burstFor allows a second's worth of requests at once, and never less than
+// one, so a low rate still lets something through.
+func burstFor(rate float64) float64 {
+	if rate < 1 {
+		return 1
+	}
+	return rate
+}
+
+// Wait blocks until a request may be sent, or ctx ends.
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	for {
+		d := l.reserve()
+		if d <= 0 {
+			return nil
+		}
+		tflog.Debug(ctx, "[RATE LIMIT] Waiting before sending request", map[string]interface{}{
+			"wait": d.String(),
+		})
+		if err := l.sleep(ctx, d); err != nil {
+			return err
+		}
+	}
+}
+
+// reserve takes a token if one is available and returns 0, or returns how long
+// to wait before asking again.
+func (l *rateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Before(l.exhaustedUntil) {
+		return l.exhaustedUntil.Sub(now)
+	}
+
+	rate := l.rate()
+	if rate == 0 {
+		return 0
+	}
+
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * rate
+	}
+	l.last = now
+	if burst := burstFor(rate); l.tokens > burst {
+		l.tokens = burst
+	}
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / rate * float64(time.Second))
+}
+
+// Observe adjusts the rate from a response's X-RateLimit-* headers. A response
+// without them leaves everything as it was.
+func (l *rateLimiter) Observe(ctx context.Context, h http.Header) {
+	limit, okLimit := headerInt(h, "X-RateLimit-Limit")
+	remaining, okRemaining := headerInt(h, "X-RateLimit-Remaining")
+	reset, okReset := headerInt(h, "X-RateLimit-Reset")
+	if !okRemaining || !okReset {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// X-RateLimit-Reset is the number of seconds until the window resets.
+	window := time.Duration(reset) * time.Second
+
+	switch {
+	case remaining <= 0:
+		until := l.now().Add(window)
+		if until.After(l.exhaustedUntil) {
+			l.exhaustedUntil = until
+			tflog.Warn(ctx, "[RATE LIMIT] OneLogin rate limit budget spent, holding requests until it resets", map[string]interface{}{
+				"limit": limit,
+				"reset": window.String(),
+			})
+		}
+	case okLimit && float64(remaining) >= float64(limit)*pacingThreshold:
+		l.budgetRate = 0
+	default:
+		if reset < 1 {
+			reset = 1
+		}
+		l.budgetRate = float64(remaining) / float64(reset)
+	}
+}
+
+func headerInt(h http.Header, key string) (int, bool) {
+	v := h.Get(key)
+	if v == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// rateLimitTransport makes every request wait its turn, and feeds every
+// response's headers back to the limiter.
+type rateLimitTransport struct {
+	limiter *rateLimiter
+	next    http.RoundTripper
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if resp != nil {
+		t.limiter.Observe(req.Context(), resp.Header)
+	}
+	return resp, err
+}
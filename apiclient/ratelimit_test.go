@@ -0,0 +1,191 @@
+package apiclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock drives a rateLimiter without real time passing: sleeping advances
+// the clock, and every sleep is recorded.
+type fakeClock struct {
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func (c *fakeClock) install(l *rateLimiter) *rateLimiter {
+	c.now = time.Unix(1700000000, 0)
+	l.now = func() time.Time { return c.now }
+	l.sleep = func(ctx context.Context, d time.Duration) error {
+		c.sleeps = append(c.sleeps, d)
+		c.now = c.now.Add(d)
+		return nil
+	}
+	return l
+}
+
+func (c *fakeClock) slept() time.Duration {
+	var total time.Duration
+	for _, d := range c.sleeps {
+		total += d
+	}
+	return total
+}
+
+func rateHeaders(limit, remaining, reset int) http.Header {
+	h := http.Header{}
+	h.Set("X-RateLimit-Limit", strconv.Itoa(limit))
+	h.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	h.Set("X-RateLimit-Reset", strconv.Itoa(reset))
+	return h
+}
+
+func TestRateLimiterUnlimitedByDefault(t *testing.T) {
+	clock := &fakeClock{}
+	l := clock.install(newRateLimiter(0))
+
+	for i := 0; i < 100; i++ {
+		if err := l.Wait(t.Context()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(clock.sleeps) != 0 {
+		t.Fatalf("expected no waiting without a cap or headers, got %v", clock.sleeps)
+	}
+}
+
+func TestRateLimiterCap(t *testing.T) {
+	clock := &fakeClock{}
+	l := clock.install(newRateLimiter(5))
+
+	// A second's burst, then one every 200ms.
+	for i := 0; i < 15; i++ {
+		if err := l.Wait(t.Context()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if got := clock.slept(); got < 1900*time.Millisecond || got > 2100*time.Millisecond {
+		t.Fatalf("expected about 2s of waiting for 15 requests at 5/s with a burst of 5, got %s", got)
+	}
+}
+
+// TestRateLimiterPacesTheRemainingBudget: with most of the window's budget
+// spent, what is left is spread over the rest of the window rather than used
+// up at once and followed by a wall of 429s.
+func TestRateLimiterPacesTheRemainingBudget(t *testing.T) {
+	clock := &fakeClock{}
+	l := clock.install(newRateLimiter(0))
+
+	// Plenty left: no pacing.
+	l.Observe(t.Context(), rateHeaders(5000, 4000, 3000))
+	for i := 0; i < 10; i++ {
+		l.Wait(t.Context())
+	}
+	if len(clock.sleeps) != 0 {
+		t.Fatalf("expected no pacing with most of the budget left, got %v", clock.sleeps)
+	}
+
+	// 100 left for 50 seconds: two a second.
+	l.Observe(t.Context(), rateHeaders(5000, 100, 50))
+	for i := 0; i < 11; i++ {
+		l.Wait(t.Context())
+	}
+	if got := clock.slept(); got < 4900*time.Millisecond || got > 5100*time.Millisecond {
+		t.Fatalf("expected about 5s of pacing for 11 requests at 2/s, got %s", got)
+	}
+}
+
+func TestRateLimiterCapStillAppliesUnderBudget(t *testing.T) {
+	l := newRateLimiter(1)
+	l.Observe(t.Context(), rateHeaders(5000, 100, 10)) // budget allows 10/s
+
+	if got := l.rate(); got != 1 {
+		t.Fatalf("expected the lower configured cap to win, got %v", got)
+	}
+
+	l.Observe(t.Context(), rateHeaders(5000, 5, 10)) // budget allows 0.5/s
+	if got := l.rate(); got != 0.5 {
+		t.Fatalf("expected the lower budget rate to win, got %v", got)
+	}
+}
+
+func TestRateLimiterHoldsUntilResetWhenSpent(t *testing.T) {
+	clock := &fakeClock{}
+	l := clock.install(newRateLimiter(0))
+
+	l.Observe(t.Context(), rateHeaders(5000, 0, 30))
+	if err := l.Wait(t.Context()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(clock.sleeps) == 0 || clock.sleeps[0] != 30*time.Second {
+		t.Fatalf("expected to wait out the 30s reset, got %v", clock.sleeps)
+	}
+}
+
+func TestRateLimiterIgnoresResponsesWithoutHeaders(t *testing.T) {
+	l := newRateLimiter(0)
+	l.Observe(t.Context(), http.Header{"X-RateLimit-Remaining": {"not-a-number"}, "X-RateLimit-Reset": {"10"}})
+
+	if got := l.rate(); got != 0 {
+		t.Fatalf("expected no rate from unusable headers, got %v", got)
+	}
+}
+
+func TestRateLimiterWaitEndsWithContext(t *testing.T) {
+	l := newRateLimiter(0)
+	l.Observe(context.Background(), rateHeaders(100, 0, 3600))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); err == nil {
+		t.Fatal("expected the wait to end with the context")
+	}
+}
+
+// TestClientHoldsRequestsWhenBudgetIsSpent runs the limiter inside a whole
+// client against a server that reports its budget gone: the next request is
+// held rather than sent into a 429.
+func TestClientHoldsRequestsWhenBudgetIsSpent(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == tokenPath {
+			w.Write([]byte(`{"access_token":"t","expires_in":36000}`))
+			return
+		}
+		if calls.Add(1) == 1 {
+			for k, v := range rateHeaders(10, 0, 1) {
+				w.Header()[k] = v
+			}
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewHTTPClient(Config{ClientID: "id", ClientSecret: "secret", URL: srv.URL, Timeout: 10 * time.Second})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	get := func() time.Duration {
+		t.Helper()
+		start := time.Now()
+		resp, err := c.Get(srv.URL + "/api/2/apps")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+		return time.Since(start)
+	}
+
+	get() // reports the budget spent, with a second to go
+	if held := get(); held < 900*time.Millisecond {
+		t.Fatalf("expected the next request to be held until the reset, it went out after %s", held)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Fatalf("expected 2 requests to arrive, got %d", n)
+	}
+}
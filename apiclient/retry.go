@@ -0,0 +1,189 @@
+package apiclient
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// DefaultMinBackoff and DefaultMaxBackoff bound the wait between attempts
+	// when a Config leaves them unset.
+	DefaultMinBackoff = time.Second
+	DefaultMaxBackoff = 30 * time.Second
+)
+
+// retryTransport re-sends requests that failed for reasons that are worth
+// waiting out: a 429, a 5xx from a gateway or an overloaded backend, or a
+// connection that dropped before any response arrived.
+//
+// Which requests are re-sent matters more than how. A PUT or DELETE sent twice
+// lands in the same place, so those are retried on anything transient. A POST
+// that reached the API and failed with a 502 may still have created the
+// object, and sending it again would create a second one -- so a POST is
+// retried only on a 429, which the API returns before doing anything.
+type retryTransport struct {
+	next       http.RoundTripper
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	// sleep waits for d or until ctx is done. A field so tests can watch the
+	// waits rather than sit through them.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func newRetryTransport(next http.RoundTripper, cfg Config) *retryTransport {
+	return &retryTransport{
+		next:       next,
+		maxRetries: cfg.MaxRetries,
+		minBackoff: cfg.MinBackoff,
+		maxBackoff: cfg.MaxBackoff,
+		sleep:      sleepContext,
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 0; ; attempt++ {
+		// Every attempt gets a fresh body. The first send drains the one the
+		// request arrived with.
+		out := req
+		if attempt > 0 && req.Body != nil && req.Body != http.NoBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			out = req.Clone(ctx)
+			out.Body = body
+		}
+
+		resp, err := t.next.RoundTrip(out)
+
+		if attempt >= t.maxRetries || !t.shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt)
+		if after, ok := retryAfter(resp); ok {
+			wait = after
+		}
+
+		fields := map[string]interface{}{
+			"method":  req.Method,
+			"path":    req.URL.Path,
+			"attempt": attempt + 1,
+			"wait":    wait.String(),
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+		} else {
+			fields["status"] = resp.StatusCode
+		}
+		tflog.Warn(ctx, "[RETRY] Transient OneLogin API failure, retrying", fields)
+
+		// The response is being thrown away, but its connection is only
+		// reused if the body is read to the end first.
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := t.sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// shouldRetry decides whether a result is worth another attempt.
+func (t *retryTransport) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	// A body that cannot be produced again cannot be re-sent.
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	// Cancelled or out of time: whoever asked has stopped waiting.
+	if req.Context().Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		return isIdempotent(req.Method)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return true
+	case resp.StatusCode == http.StatusNotImplemented:
+		// Not transient: the endpoint will not exist next time either.
+		return false
+	case resp.StatusCode >= 500:
+		return isIdempotent(req.Method)
+	}
+	return false
+}
+
+// backoff is the wait before retry number attempt+1: exponential from
+// minBackoff, capped at maxBackoff, with the upper half jittered so parallel
+// resources that failed together do not all come back together.
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	d := t.minBackoff
+	for i := 0; i < attempt && d < t.maxBackoff; i++ {
+		d *= 2
+	}
+	if d > t.maxBackoff {
+		d = t.maxBackoff
+	}
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// retryAfter reads a Retry-After header, in either of the forms HTTP allows:
+// a number of seconds or a date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if when, err := http.ParseTime(v); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,262 @@
+package apiclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyServer fails the first n requests with status, then succeeds. Every
+// request body it receives is recorded, so a test can check what a retry
+// actually re-sent.
+type flakyServer struct {
+	*httptest.Server
+	calls  atomic.Int32
+	bodies []string
+}
+
+func newFlakyServer(t *testing.T, failures int, status int, header http.Header) *flakyServer {
+	t.Helper()
+
+	fs := &flakyServer{}
+	fs.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		fs.bodies = append(fs.bodies, string(body))
+
+		if int(fs.calls.Add(1)) <= failures {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(fs.Close)
+	return fs
+}
+
+// recordingRetry returns a retryTransport whose waits are recorded rather than
+// slept through.
+func recordingRetry(maxRetries int) (*retryTransport, *[]time.Duration) {
+	waits := &[]time.Duration{}
+	rt := newRetryTransport(newBaseTransport(), Config{
+		MaxRetries: maxRetries,
+		MinBackoff: 100 * time.Millisecond,
+		MaxBackoff: time.Second,
+	})
+	rt.sleep = func(ctx context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		return nil
+	}
+	return rt, waits
+}
+
+func send(t *testing.T, rt http.RoundTripper, method, url, body string) *http.Response {
+	t.Helper()
+
+	var r io.Reader
+	if body != "" {
+		r = strings.NewReader(body)
+	}
+	req, err := http.NewRequest(method, url, r)
+	if err != nil {
+		t.Fatalf("building request: %v", err)
+	}
+	resp, err := (&http.Client{Transport: rt}).Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	return resp
+}
+
+// TestRetryRidesOutTransientFailures covers a single 429 or 502 failing a whole
+// apply halfway through.
+func TestRetryRidesOutTransientFailures(t *testing.T) {
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			srv := newFlakyServer(t, 2, status, nil)
+			rt, waits := recordingRetry(3)
+
+			resp := send(t, rt, http.MethodGet, srv.URL+"/api/2/roles/1", "")
+
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("expected the third attempt to succeed, got %d", resp.StatusCode)
+			}
+			if n := srv.calls.Load(); n != 3 {
+				t.Fatalf("expected 3 attempts, got %d", n)
+			}
+			if len(*waits) != 2 {
+				t.Fatalf("expected 2 waits, got %v", *waits)
+			}
+		})
+	}
+}
+
+func TestRetryGivesUpAfterMaxRetries(t *testing.T) {
+	srv := newFlakyServer(t, 100, http.StatusBadGateway, nil)
+	rt, _ := recordingRetry(2)
+
+	resp := send(t, rt, http.MethodGet, srv.URL, "")
+
+	// The last failure is handed back as it came, so the SDK still reports
+	// the status it always did.
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected the final 502 to be returned, got %d", resp.StatusCode)
+	}
+	if n := srv.calls.Load(); n != 3 {
+		t.Fatalf("expected the first attempt plus 2 retries, got %d", n)
+	}
+}
+
+func TestRetryZeroMeansNever(t *testing.T) {
+	srv := newFlakyServer(t, 1, http.StatusTooManyRequests, nil)
+	rt, _ := recordingRetry(0)
+
+	if resp := send(t, rt, http.MethodGet, srv.URL, ""); resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected the 429 to be returned unretried, got %d", resp.StatusCode)
+	}
+	if n := srv.calls.Load(); n != 1 {
+		t.Fatalf("expected a single attempt, got %d", n)
+	}
+}
+
+// TestRetryLeavesUnsafePostsAlone: a POST that failed with a 502 may have
+// created its object anyway, and sending it again would create a second one.
+// A 429 is refused before anything happens, so that one is safe.
+func TestRetryLeavesUnsafePostsAlone(t *testing.T) {
+	t.Run("502 is not retried", func(t *testing.T) {
+		srv := newFlakyServer(t, 1, http.StatusBadGateway, nil)
+		rt, _ := recordingRetry(3)
+
+		if resp := send(t, rt, http.MethodPost, srv.URL+"/api/2/users", `{"username":"jdoe"}`); resp.StatusCode != http.StatusBadGateway {
+			t.Fatalf("expected the 502 to be returned, got %d", resp.StatusCode)
+		}
+		if n := srv.calls.Load(); n != 1 {
+			t.Fatalf("expected a single attempt, got %d", n)
+		}
+	})
+
+	t.Run("429 is retried with its body", func(t *testing.T) {
+		srv := newFlakyServer(t, 1, http.StatusTooManyRequests, nil)
+		rt, _ := recordingRetry(3)
+
+		if resp := send(t, rt, http.MethodPost, srv.URL+"/api/2/users", `{"username":"jdoe"}`); resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected the retry to succeed, got %d", resp.StatusCode)
+		}
+		if len(srv.bodies) != 2 || srv.bodies[0] != srv.bodies[1] || srv.bodies[1] == "" {
+			t.Fatalf("expected the same body on both attempts, got %q", srv.bodies)
+		}
+	})
+}
+
+func TestRetryHonoursRetryAfter(t *testing.T) {
+	srv := newFlakyServer(t, 1, http.StatusTooManyRequests, http.Header{"Retry-After": {"7"}})
+	rt, waits := recordingRetry(3)
+
+	send(t, rt, http.MethodGet, srv.URL, "")
+
+	if len(*waits) != 1 || (*waits)[0] != 7*time.Second {
+		t.Fatalf("expected a single 7s wait from Retry-After, got %v", *waits)
+	}
+}
+
+func TestRetryDoesNotRetryClientErrors(t *testing.T) {
+	for _, status := range []int{http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity, http.StatusNotImplemented} {
+		srv := newFlakyServer(t, 1, status, nil)
+		rt, _ := recordingRetry(3)
+
+		if resp := send(t, rt, http.MethodPut, srv.URL, `{}`); resp.StatusCode != status {
+			t.Fatalf("expected %d to be returned as is, got %d", status, resp.StatusCode)
+		}
+		if n := srv.calls.Load(); n != 1 {
+			t.Fatalf("%d: expected a single attempt, got %d", status, n)
+		}
+	}
+}
+
+func TestRetryStopsWhenTheContextEnds(t *testing.T) {
+	srv := newFlakyServer(t, 100, http.StatusServiceUnavailable, nil)
+	rt := newRetryTransport(newBaseTransport(), Config{MaxRetries: 10, MinBackoff: time.Hour, MaxBackoff: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+
+	start := time.Now()
+	if _, err := rt.RoundTrip(req); err == nil {
+		t.Fatal("expected the expired context to end the retries with an error")
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Fatal("expected the wait to be cut short by the context")
+	}
+}
+
+func TestBackoffGrowsAndIsCapped(t *testing.T) {
+	rt := &retryTransport{minBackoff: time.Second, maxBackoff: 8 * time.Second}
+
+	for attempt, ceiling := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 8 * time.Second, 8 * time.Second} {
+		for i := 0; i < 20; i++ {
+			got := rt.backoff(attempt)
+			if got < ceiling/2 || got > ceiling {
+				t.Fatalf("attempt %d: expected a wait in [%s, %s], got %s", attempt, ceiling/2, ceiling, got)
+			}
+		}
+	}
+}
+
+// TestClientRetriesThroughAuthentication wires the whole client together: a
+// failure injected after the token has been issued is retried with the same
+// token, and the token is not fetched again for it.
+func TestClientRetriesThroughAuthentication(t *testing.T) {
+	ft := newFakeTenant(t, "flaky")
+	var failed atomic.Bool
+	inner := ft.Server.Config.Handler
+	ft.Server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != tokenPath && failed.CompareAndSwap(false, true) {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		inner.ServeHTTP(w, r)
+	})
+
+	cfg := ft.config()
+	cfg.MaxRetries = 2
+	cfg.MinBackoff = time.Millisecond
+	cfg.MaxBackoff = time.Millisecond
+	c, err := NewHTTPClient(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resp, err := c.Get(ft.URL + "/api/2/roles/1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected the retry to succeed, got %d", resp.StatusCode)
+	}
+	if n := ft.tokenCalls.Load(); n != 1 {
+		t.Fatalf("expected a single token fetch, got %d", n)
+	}
+}
+
+func TestConfigValidateBackoff(t *testing.T) {
+	cfg := Config{ClientID: "id", ClientSecret: "secret", URL: "https://api.us.onelogin.com", MinBackoff: 10 * time.Second, MaxBackoff: time.Second}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected a max backoff below the min to be rejected")
+	}
+
+	cfg = Config{ClientID: "id", ClientSecret: "secret", URL: "https://api.us.onelogin.com", MaxRetries: -1}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected negative retries to be rejected")
+	}
+}
@@ -0,0 +1,150 @@
+package apiclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// tokenPath is the client-credentials endpoint, relative to Config.URL.
+const tokenPath = "/auth/oauth2/v2/token"
+
+// tokenExpiryMargin is how long before its stated expiry a token stops being
+// used. A token that expires between being attached and being checked gets a
+// 401 for a request that did nothing wrong.
+const tokenExpiryMargin = time.Minute
+
+// token is an access token and the moment it stops being worth sending.
+type token struct {
+	value   string
+	expires time.Time
+}
+
+func (t token) valid(now time.Time) bool {
+	return t.value != "" && now.Before(t.expires)
+}
+
+// tokenSource fetches access tokens for one set of credentials and keeps them
+// in a tokenCache, which it may share with others.
+type tokenSource struct {
+	cfg   Config
+	base  http.RoundTripper
+	cache *tokenCache
+	// disk, when set, holds the token between runs.
+	disk *diskCache
+	now  func() time.Time
+}
+
+// newTokenSource returns a tokenSource for cfg's credentials that shares its
+// token with every other one built for the same credentials and URL.
+func newTokenSource(cfg Config, base http.RoundTripper) *tokenSource {
+	s := &tokenSource{
+		cfg:   cfg,
+		base:  base,
+		cache: sharedTokenCache(cfg),
+		now:   time.Now,
+	}
+	if cfg.TokenCacheDir != "" {
+		s.disk = newDiskCache(cfg)
+	}
+	return s
+}
+
+// Token returns a usable access token, fetching a new one when there is none
+// or the one held is about to expire.
+//
+// The lock is held across the fetch on purpose. Resources are applied in
+// parallel, and without it every one of them that starts before the first
+// token arrives asks for its own.
+func (s *tokenSource) Token(ctx context.Context) (string, error) {
+	c := s.cache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := s.now()
+	if c.current.valid(now) {
+		return c.current.value, nil
+	}
+	if s.disk != nil {
+		if t, ok := s.disk.load(); ok && t.valid(now) {
+			c.current = t
+			return t.value, nil
+		}
+	}
+
+	t, err := s.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	c.current = t
+	if s.disk != nil {
+		s.disk.store(t)
+	}
+	return t.value, nil
+}
+
+// Invalidate drops rejected, a token the API refused, so the next Token call
+// fetches another. A token that has already been replaced is left alone: a
+// request that was sent with the old one must not throw away the new one.
+func (s *tokenSource) Invalidate(rejected string) {
+	c := s.cache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.current.value == rejected {
+		c.current = token{}
+	}
+	if s.disk != nil {
+		s.disk.remove(rejected)
+	}
+}
+
+// tokenResponse is the part of the token endpoint's reply that matters here.
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+func (s *tokenSource) fetch(ctx context.Context) (token, error) {
+	body, _ := json.Marshal(map[string]string{"grant_type": "client_credentials"})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.URL+tokenPath, bytes.NewReader(body))
+	if err != nil {
+		return token{}, err
+	}
+	req.SetBasicAuth(s.cfg.ClientID, s.cfg.ClientSecret)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := (&http.Client{Transport: s.base, Timeout: s.cfg.Timeout}).Do(req)
+	if err != nil {
+		return token{}, fmt.Errorf("fetching OneLogin access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return token{}, fmt.Errorf("fetching OneLogin access token: %w", err)
+	}
+	// The same wording the SDK uses for a failed call, so a status check
+	// written against one matches the other.
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return token{}, fmt.Errorf("fetching OneLogin access token: request failed with status: %d", resp.StatusCode)
+	}
+
+	var tr tokenResponse
+	if err := json.Unmarshal(raw, &tr); err != nil {
+		return token{}, fmt.Errorf("fetching OneLogin access token: decoding response: %w", err)
+	}
+	if tr.AccessToken == "" {
+		return token{}, fmt.Errorf("fetching OneLogin access token: response carried no access_token")
+	}
+
+	return token{
+		value:   tr.AccessToken,
+		expires: s.now().Add(time.Duration(tr.ExpiresIn)*time.Second - tokenExpiryMargin),
+	}, nil
+}
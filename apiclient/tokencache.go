@@ -0,0 +1,137 @@
+package apiclient
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// tokenCache holds the current access token for one set of credentials. Its
+// lock is held across a fetch, so however many clients share it, one fetch is
+// in flight at a time.
+type tokenCache struct {
+	mu      sync.Mutex
+	current token
+}
+
+// tokenCaches are the caches in this process, by credentials and URL. Aliased
+// provider blocks, and the providers of every module that configures its own,
+// all run in one plugin process; with the same credentials they can use the
+// same token rather than each spending the tenant's token quota on its own.
+var tokenCaches = struct {
+	sync.Mutex
+	m map[string]*tokenCache
+}{m: map[string]*tokenCache{}}
+
+// sharedTokenCache returns the cache for cfg's credentials and URL. The secret
+// is part of the key: a block configured with a wrong secret must fail, not
+// quietly borrow the token of one configured with the right one.
+func sharedTokenCache(cfg Config) *tokenCache {
+	key := hashKey(cfg.ClientID, cfg.ClientSecret, cfg.URL)
+
+	tokenCaches.Lock()
+	defer tokenCaches.Unlock()
+	c, ok := tokenCaches.m[key]
+	if !ok {
+		c = &tokenCache{}
+		tokenCaches.m[key] = c
+	}
+	return c
+}
+
+func hashKey(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		// A separator, so that ("ab", "c") and ("a", "bc") differ.
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// diskCache keeps a token in a file between runs. The file is named by a hash
+// of the client ID and API host, so nothing about the credentials can be read
+// from a directory listing, and it is written readable by its owner only.
+//
+// Nothing coordinates two processes that miss at once; both fetch, and the
+// second write wins. That costs one extra token, not a failure. Every error is
+// treated as a miss: the cache saves a fetch, and is never a reason to fail
+// one.
+type diskCache struct {
+	path string
+}
+
+func newDiskCache(cfg Config) *diskCache {
+	host := cfg.URL
+	if u, err := url.Parse(cfg.URL); err == nil {
+		host = u.Host
+	}
+	return &diskCache{path: filepath.Join(cfg.TokenCacheDir, "onelogin-token-"+hashKey(cfg.ClientID, host)+".json")}
+}
+
+// cachedToken is a token as it is written to disk.
+type cachedToken struct {
+	AccessToken string    `json:"access_token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+func (c *diskCache) load() (token, bool) {
+	info, err := os.Stat(c.path)
+	// A file others can read may have been put there by someone else, and
+	// its token already leaked. It is replaced rather than trusted.
+	if err != nil || info.Mode().Perm()&0o077 != 0 {
+		return token{}, false
+	}
+	raw, err := os.ReadFile(c.path)
+	if err != nil {
+		return token{}, false
+	}
+	var ct cachedToken
+	if err := json.Unmarshal(raw, &ct); err != nil || ct.AccessToken == "" {
+		return token{}, false
+	}
+	return token{value: ct.AccessToken, expires: ct.ExpiresAt}, true
+}
+
+func (c *diskCache) store(t token) {
+	raw, err := json.Marshal(cachedToken{AccessToken: t.value, ExpiresAt: t.expires})
+	if err != nil {
+		return
+	}
+	dir := filepath.Dir(c.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return
+	}
+
+	// Written aside and renamed into place, so a process reading at the same
+	// moment sees the old token or the new one, never half of either.
+	f, err := os.CreateTemp(dir, ".onelogin-token-*")
+	if err != nil {
+		return
+	}
+	defer os.Remove(f.Name())
+	if err := f.Chmod(0o600); err != nil {
+		f.Close()
+		return
+	}
+	if _, err := f.Write(raw); err != nil {
+		f.Close()
+		return
+	}
+	if err := f.Close(); err != nil {
+		return
+	}
+	os.Rename(f.Name(), c.path)
+}
+
+// remove deletes the file if it still holds rejected.
+func (c *diskCache) remove(rejected string) {
+	if t, ok := c.load(); ok && t.value == rejected {
+		os.Remove(c.path)
+	}
+}
@@ -0,0 +1,349 @@
+package apiclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestTokenSharedAcrossClients covers aliased provider blocks, or modules that
+// each configure their own provider, with the same credentials: one token
+// between them, however many requests they make and however concurrently.
+func TestTokenSharedAcrossClients(t *testing.T) {
+	ft := newFakeTenant(t, "shared")
+
+	const clients, requests = 5, 40
+	var wg sync.WaitGroup
+	var failed atomic.Int32
+	for i := 0; i < clients; i++ {
+		c, err := NewHTTPClient(ft.config())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for j := 0; j < requests; j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				resp, err := c.Get(ft.URL + "/api/2/roles")
+				if err != nil {
+					failed.Add(1)
+					return
+				}
+				io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+				if resp.StatusCode != http.StatusOK {
+					failed.Add(1)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	if n := failed.Load(); n != 0 {
+		t.Fatalf("%d requests failed", n)
+	}
+	if n := ft.apiCalls.Load(); n != clients*requests {
+		t.Fatalf("expected %d API calls, got %d", clients*requests, n)
+	}
+	if n := ft.tokenCalls.Load(); n != 1 {
+		t.Fatalf("expected a single token fetch, got %d", n)
+	}
+}
+
+// TestTokenNotSharedWithWrongSecret keeps a block with a mistyped secret from
+// working by borrowing the token of one with the right secret.
+func TestTokenNotSharedWithWrongSecret(t *testing.T) {
+	ft := newFakeTenant(t, "secret")
+
+	right, err := NewHTTPClient(ft.config())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := right.Get(ft.URL + "/api/2/roles")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	cfg := ft.config()
+	cfg.ClientSecret = "mistyped"
+	wrong, err := NewHTTPClient(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := wrong.Get(ft.URL + "/api/2/roles"); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("expected the wrong secret to be refused a token, got %v", err)
+	}
+}
+
+func TestTokenCachedOnDisk(t *testing.T) {
+	ft := newFakeTenant(t, "disk")
+	dir := filepath.Join(t.TempDir(), "cache")
+
+	cfg := ft.config()
+	cfg.TokenCacheDir = dir
+
+	// Three runs, each a new plugin process with nothing in memory.
+	for run := 0; run < 3; run++ {
+		forgetTokens()
+		c, err := NewHTTPClient(cfg)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp, err := c.Get(ft.URL + "/api/2/roles")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+	}
+	if n := ft.tokenCalls.Load(); n != 1 {
+		t.Fatalf("expected one token fetch across runs, got %d", n)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected a single cache file, got %v", entries)
+	}
+	name := entries[0].Name()
+	if strings.Contains(name, ft.clientID) {
+		t.Fatalf("expected the file name not to carry the client ID, got %q", name)
+	}
+	info, err := entries[0].Info()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Fatalf("expected the file to be 0600, got %o", perm)
+	}
+	raw, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(raw), ft.clientSecret) {
+		t.Fatal("expected the client secret not to be written to disk")
+	}
+
+	t.Run("keyed by API host", func(t *testing.T) {
+		other := newFakeTenant(t, "disk")
+		cfg := other.config()
+		cfg.TokenCacheDir = dir
+		forgetTokens()
+		c, err := NewHTTPClient(cfg)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp, err := c.Get(other.URL + "/api/2/roles")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+		if n := other.tokenCalls.Load(); n != 1 {
+			t.Fatalf("expected another host to fetch its own token, got %d fetches", n)
+		}
+	})
+
+	t.Run("a file others can read is not trusted", func(t *testing.T) {
+		path := filepath.Join(dir, name)
+		if err := os.Chmod(path, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		before := ft.tokenCalls.Load()
+
+		forgetTokens()
+		c, err := NewHTTPClient(cfg)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp, err := c.Get(ft.URL + "/api/2/roles")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+
+		if n := ft.tokenCalls.Load() - before; n != 1 {
+			t.Fatalf("expected a fresh token, got %d fetches", n)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); perm != 0o600 {
+			t.Fatalf("expected the file to be replaced as 0600, got %o", perm)
+		}
+	})
+}
+
+// revokingTenant issues a new token on every fetch and honours only tokens
+// that have not been revoked.
+type revokingTenant struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	issued  int
+	revoked map[string]bool
+	bodies  []string
+
+	tokenCalls atomic.Int32
+}
+
+func newRevokingTenant(t *testing.T) *revokingTenant {
+	t.Helper()
+
+	rt := &revokingTenant{revoked: map[string]bool{}}
+	rt.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rt.mu.Lock()
+		defer rt.mu.Unlock()
+
+		if r.URL.Path == tokenPath {
+			rt.tokenCalls.Add(1)
+			rt.issued++
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token": fmt.Sprintf("token-%d", rt.issued),
+				"expires_in":   36000,
+			})
+			return
+		}
+
+		tok := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if tok == "" || rt.revoked[tok] {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		rt.bodies = append(rt.bodies, string(body))
+		json.NewEncoder(w).Encode(map[string]interface{}{"token": tok})
+	}))
+	t.Cleanup(rt.Close)
+	t.Cleanup(forgetTokens)
+	return rt
+}
+
+func (rt *revokingTenant) revokeAll() {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	for i := 1; i <= rt.issued; i++ {
+		rt.revoked[fmt.Sprintf("token-%d", i)] = true
+	}
+}
+
+func (rt *revokingTenant) config() Config {
+	return Config{ClientID: "id", ClientSecret: "secret", URL: rt.URL, Timeout: 5 * time.Second}
+}
+
+func TestTokenRefreshedOn401(t *testing.T) {
+	rt := newRevokingTenant(t)
+	c, err := NewHTTPClient(rt.config())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	post := func() *http.Response {
+		t.Helper()
+		resp, err := c.Post(rt.URL+"/api/2/roles", "application/json", strings.NewReader(`{"name":"admins"}`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		return resp
+	}
+
+	if resp := post(); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	rt.revokeAll()
+	if resp := post(); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected the revoked token to be replaced, got %d", resp.StatusCode)
+	}
+	if n := rt.tokenCalls.Load(); n != 2 {
+		t.Fatalf("expected a second token after the first was revoked, got %d fetches", n)
+	}
+	// The retried request carried the same body as the refused one.
+	if len(rt.bodies) != 2 || rt.bodies[1] != `{"name":"admins"}` {
+		t.Fatalf("expected the body to be sent again, got %q", rt.bodies)
+	}
+}
+
+// TestTokenRefreshedOn401FromDisk covers a token another process cached for
+// credentials that have since been rotated.
+func TestTokenRefreshedOn401FromDisk(t *testing.T) {
+	rt := newRevokingTenant(t)
+	cfg := rt.config()
+	cfg.TokenCacheDir = t.TempDir()
+
+	get := func() int {
+		t.Helper()
+		forgetTokens()
+		c, err := NewHTTPClient(cfg)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp, err := c.Get(rt.URL + "/api/2/roles")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if status := get(); status != http.StatusOK {
+		t.Fatalf("expected 200, got %d", status)
+	}
+	rt.revokeAll()
+	if status := get(); status != http.StatusOK {
+		t.Fatalf("expected the cached token to be replaced, got %d", status)
+	}
+	// And the replacement is what the next run picks up.
+	if status := get(); status != http.StatusOK {
+		t.Fatalf("expected 200, got %d", status)
+	}
+	if n := rt.tokenCalls.Load(); n != 2 {
+		t.Fatalf("expected two token fetches, got %d", n)
+	}
+}
+
+// TestPersistent401NotRetriedForever covers a 401 a new token does not fix,
+// such as an API client without permission: reported after one refresh.
+func TestPersistent401NotRetriedForever(t *testing.T) {
+	var tokenCalls, apiCalls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == tokenPath {
+			n := tokenCalls.Add(1)
+			json.NewEncoder(w).Encode(map[string]interface{}{"access_token": fmt.Sprintf("token-%d", n), "expires_in": 36000})
+			return
+		}
+		apiCalls.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+	defer forgetTokens()
+
+	c, err := NewHTTPClient(Config{ClientID: "id", ClientSecret: "secret", URL: srv.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := c.Get(srv.URL + "/api/2/roles")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected the 401 to be returned, got %d", resp.StatusCode)
+	}
+	if tokenCalls.Load() != 2 || apiCalls.Load() != 2 {
+		t.Fatalf("expected one refresh and one retry, got %d fetches and %d calls", tokenCalls.Load(), apiCalls.Load())
+	}
+}
@@ -0,0 +1,145 @@
+package apiclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// newTransport is the base transport with cfg's proxy and TLS settings
+// applied: where requests go on their way out, which servers are trusted, and
+// what this client presents when a server asks who it is.
+func newTransport(cfg Config) (*http.Transport, error) {
+	t := newBaseTransport()
+
+	if cfg.ProxyURL != "" {
+		proxy, err := parseProxyURL(cfg.ProxyURL)
+		if err != nil {
+			return nil, err
+		}
+		// Set outright rather than through HTTPS_PROXY, which is read once
+		// per process and would apply to every provider block alike.
+		t.Proxy = http.ProxyURL(proxy)
+	}
+
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		t.TLSClientConfig = tlsConfig
+	}
+	return t, nil
+}
+
+// parseProxyURL accepts what http.ProxyURL can use: an http, https or socks5
+// URL with a host.
+func parseProxyURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy_url %q: %w", raw, err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("invalid proxy_url %q: expected an http, https or socks5 URL", raw)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy_url %q: no host", raw)
+	}
+	return u, nil
+}
+
+// newTLSConfig returns nil when cfg changes nothing about TLS, so the
+// transport keeps Go's defaults exactly.
+func newTLSConfig(cfg Config) (*tls.Config, error) {
+	roots, err := rootCAs(cfg)
+	if err != nil {
+		return nil, err
+	}
+	certs, err := clientCertificates(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if roots == nil && certs == nil && !cfg.InsecureSkipVerify {
+		return nil, nil
+	}
+
+	return &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		RootCAs:            roots,
+		Certificates:       certs,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}, nil
+}
+
+// rootCAs is the system pool with cfg's certificates added. Added rather than
+// used on their own: behind a TLS-inspecting proxy the proxy's CA is all that
+// is needed, but the same configuration run from a laptop without the proxy
+// still has to trust OneLogin's public certificate.
+func rootCAs(cfg Config) (*x509.CertPool, error) {
+	if cfg.CACertPEM == "" && cfg.CACertFile == "" {
+		return nil, nil
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+
+	if cfg.CACertPEM != "" && !pool.AppendCertsFromPEM([]byte(cfg.CACertPEM)) {
+		return nil, errors.New("ca_cert_pem holds no PEM-encoded certificate")
+	}
+	if cfg.CACertFile != "" {
+		pem, err := os.ReadFile(cfg.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading ca_cert_file: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ca_cert_file %s holds no PEM-encoded certificate", cfg.CACertFile)
+		}
+	}
+	return pool, nil
+}
+
+func clientCertificates(cfg Config) ([]tls.Certificate, error) {
+	certPEM, err := pemFromConfig(cfg.ClientCertPEM, cfg.ClientCertFile, "client_cert_file")
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := pemFromConfig(cfg.ClientKeyPEM, cfg.ClientKeyFile, "client_key_file")
+	if err != nil {
+		return nil, err
+	}
+	if certPEM == nil && keyPEM == nil {
+		return nil, nil
+	}
+
+	// The key is never quoted back: an error from X509KeyPair says what is
+	// wrong with it, not what it is.
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client certificate or key: %w", err)
+	}
+	return []tls.Certificate{cert}, nil
+}
+
+// pemFromConfig returns inline when set, otherwise the contents of file, or
+// nil when neither is.
+func pemFromConfig(inline, file, fileArg string) ([]byte, error) {
+	if inline != "" {
+		return []byte(inline), nil
+	}
+	if file == "" {
+		return nil, nil
+	}
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", fileArg, err)
+	}
+	return b, nil
+}
@@ -0,0 +1,418 @@
+package apiclient
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testCA issues certificates for the local servers and clients below, the way
+// a corporate CA or a TLS-inspecting proxy would.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  string
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, key: key, pem: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))}
+}
+
+// issue returns a PEM certificate and key signed by the CA, for a server on
+// the loopback address or for a client.
+func (ca *testCA) issue(t *testing.T, usage x509.ExtKeyUsage) (certPEM, keyPEM string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, _ := rand.Int(rand.Reader, big.NewInt(1<<62))
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		DNSNames:     []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+// newTLSTenant is a fakeTenant served over TLS with a certificate from ca.
+// clientCAs, when set, is required to have issued the certificate the client
+// presents.
+func newTLSTenant(t *testing.T, ca *testCA, clientCAs *testCA) *fakeTenant {
+	t.Helper()
+
+	certPEM, keyPEM := ca.issue(t, x509.ExtKeyUsageServerAuth)
+	cert, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ft := newFakeTenantHandler("tls")
+	ft.Server = httptest.NewUnstartedServer(ft)
+	ft.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+	if clientCAs != nil {
+		pool := x509.NewCertPool()
+		pool.AddCert(clientCAs.cert)
+		ft.TLS.ClientCAs = pool
+		ft.TLS.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	// The server's own errors, a client hanging up on an untrusted
+	// certificate, are what some of these tests are for.
+	ft.Config.ErrorLog = log.New(io.Discard, "", 0)
+	ft.StartTLS()
+	t.Cleanup(ft.Close)
+	t.Cleanup(forgetTokens)
+	return ft
+}
+
+// get makes one authenticated call through a client built from cfg.
+func get(t *testing.T, cfg Config) error {
+	t.Helper()
+
+	client, err := NewHTTPClient(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error building the client: %v", err)
+	}
+	resp, err := client.Get(cfg.URL + "/api/2/ping")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func TestCustomCA(t *testing.T) {
+	ca := newTestCA(t)
+	ft := newTLSTenant(t, ca, nil)
+
+	t.Run("an unknown CA is refused", func(t *testing.T) {
+		err := get(t, ft.config())
+		if err == nil || !strings.Contains(err.Error(), "certificate") {
+			t.Fatalf("expected a certificate error, got %v", err)
+		}
+	})
+
+	t.Run("ca_cert_pem", func(t *testing.T) {
+		cfg := ft.config()
+		cfg.CACertPEM = ca.pem
+		if err := get(t, cfg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("ca_cert_file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "ca.pem")
+		if err := os.WriteFile(path, []byte(ca.pem), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		cfg := ft.config()
+		cfg.CACertFile = path
+		if err := get(t, cfg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("insecure_skip_verify", func(t *testing.T) {
+		cfg := ft.config()
+		cfg.InsecureSkipVerify = true
+		if err := get(t, cfg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if n := ft.foreign.Load(); n != 0 {
+		t.Fatalf("%d requests arrived with the wrong credentials", n)
+	}
+}
+
+func TestClientCertificate(t *testing.T) {
+	ca := newTestCA(t)
+	ft := newTLSTenant(t, ca, ca)
+	certPEM, keyPEM := ca.issue(t, x509.ExtKeyUsageClientAuth)
+
+	t.Run("refused without one", func(t *testing.T) {
+		cfg := ft.config()
+		cfg.CACertPEM = ca.pem
+		if err := get(t, cfg); err == nil {
+			t.Fatal("expected the server to refuse a client without a certificate")
+		}
+	})
+
+	t.Run("inline", func(t *testing.T) {
+		cfg := ft.config()
+		cfg.CACertPEM = ca.pem
+		cfg.ClientCertPEM, cfg.ClientKeyPEM = certPEM, keyPEM
+		if err := get(t, cfg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("from files", func(t *testing.T) {
+		// Its own token, so that its token request has to get through too.
+		forgetTokens()
+		dir := t.TempDir()
+		cfg := ft.config()
+		cfg.CACertPEM = ca.pem
+		cfg.ClientCertFile = filepath.Join(dir, "client.pem")
+		cfg.ClientKeyFile = filepath.Join(dir, "client.key")
+		if err := os.WriteFile(cfg.ClientCertFile, []byte(certPEM), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(cfg.ClientKeyFile, []byte(keyPEM), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := get(t, cfg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	// The token request is the first thing sent, and it has to present the
+	// certificate too.
+	if n := ft.tokenCalls.Load(); n != 2 {
+		t.Fatalf("expected a token fetched by each client that got through, got %d", n)
+	}
+}
+
+// forwardProxy is a local stand-in for an egress proxy: it tunnels CONNECT
+// requests and forwards plain ones, and records the hosts it was asked for.
+type forwardProxy struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	targets []string
+	plain   atomic.Int32
+}
+
+func newForwardProxy(t *testing.T) *forwardProxy {
+	t.Helper()
+
+	p := &forwardProxy{}
+	p.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		p.targets = append(p.targets, r.Host)
+		p.mu.Unlock()
+
+		if r.Method != http.MethodConnect {
+			p.plain.Add(1)
+			p.forward(w, r)
+			return
+		}
+
+		upstream, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			upstream.Close()
+			return
+		}
+		conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+
+		go func() {
+			defer upstream.Close()
+			io.Copy(upstream, conn)
+		}()
+		go func() {
+			defer conn.Close()
+			io.Copy(conn, upstream)
+		}()
+	}))
+	t.Cleanup(p.Close)
+	return p
+}
+
+func (p *forwardProxy) forward(w http.ResponseWriter, r *http.Request) {
+	out := r.Clone(r.Context())
+	out.RequestURI = ""
+	resp, err := http.DefaultTransport.RoundTrip(out)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	for k, v := range resp.Header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(resp.StatusCode)
+	io.Copy(w, resp.Body)
+}
+
+func (p *forwardProxy) sawOnly(host string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.targets) == 0 {
+		return false
+	}
+	for _, target := range p.targets {
+		if target != host {
+			return false
+		}
+	}
+	return true
+}
+
+func TestProxy(t *testing.T) {
+	t.Run("tunnels https", func(t *testing.T) {
+		ca := newTestCA(t)
+		ft := newTLSTenant(t, ca, nil)
+		proxy := newForwardProxy(t)
+
+		cfg := ft.config()
+		cfg.ProxyURL = proxy.URL
+		cfg.CACertPEM = ca.pem
+		if err := get(t, cfg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// Both the token request and the API call went through the proxy.
+		if ft.tokenCalls.Load() != 1 || ft.apiCalls.Load() != 1 {
+			t.Fatalf("expected one token and one API call, got %d and %d", ft.tokenCalls.Load(), ft.apiCalls.Load())
+		}
+		if !proxy.sawOnly(ft.Listener.Addr().String()) || proxy.plain.Load() != 0 {
+			t.Fatalf("expected only CONNECTs to the tenant, got %v", proxy.targets)
+		}
+	})
+
+	t.Run("forwards http", func(t *testing.T) {
+		ft := newFakeTenant(t, "plain")
+		proxy := newForwardProxy(t)
+
+		cfg := ft.config()
+		cfg.ProxyURL = proxy.URL
+		if err := get(t, cfg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if proxy.plain.Load() != 2 || !proxy.sawOnly(ft.Listener.Addr().String()) {
+			t.Fatalf("expected the token request and the API call to be forwarded, got %v", proxy.targets)
+		}
+	})
+
+	t.Run("an unreachable proxy is an error, not a bypass", func(t *testing.T) {
+		ft := newFakeTenant(t, "bypass")
+		proxy := newForwardProxy(t)
+		proxy.Close()
+
+		cfg := ft.config()
+		cfg.ProxyURL = proxy.URL
+		cfg.MaxRetries = 0
+		if err := get(t, cfg); err == nil {
+			t.Fatal("expected an error from a proxy that is not there")
+		}
+		if n := ft.tokenCalls.Load() + ft.apiCalls.Load(); n != 0 {
+			t.Fatalf("expected nothing to reach the tenant directly, got %d requests", n)
+		}
+	})
+}
+
+func TestTransportConfigErrors(t *testing.T) {
+	ca := newTestCA(t)
+	certPEM, keyPEM := ca.issue(t, x509.ExtKeyUsageClientAuth)
+	base := Config{ClientID: "id", ClientSecret: "secret", URL: "https://api.us.onelogin.com"}
+
+	for name, tc := range map[string]struct {
+		edit func(*Config)
+		want string
+	}{
+		"proxy without a scheme":  {func(c *Config) { c.ProxyURL = "proxy.corp:3128" }, "proxy_url"},
+		"proxy with a bad scheme": {func(c *Config) { c.ProxyURL = "ftp://proxy.corp" }, "proxy_url"},
+		"ca_cert_pem not a PEM":   {func(c *Config) { c.CACertPEM = "not a certificate" }, "ca_cert_pem"},
+		"missing ca_cert_file":    {func(c *Config) { c.CACertFile = "/nonexistent/ca.pem" }, "ca_cert_file"},
+		"certificate without key": {func(c *Config) { c.ClientCertPEM = certPEM }, "together"},
+		"key without certificate": {func(c *Config) { c.ClientKeyFile = "/tmp/key.pem" }, "together"},
+		"both forms of the key": {func(c *Config) {
+			c.ClientCertPEM, c.ClientKeyPEM, c.ClientKeyFile = certPEM, keyPEM, "/tmp/key.pem"
+		}, "client_key_file"},
+		"mismatched certificate and key": {func(c *Config) {
+			otherCert, _ := ca.issue(t, x509.ExtKeyUsageClientAuth)
+			c.ClientCertPEM, c.ClientKeyPEM = otherCert, keyPEM
+		}, "invalid client certificate"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := base
+			tc.edit(&cfg)
+			_, err := NewHTTPClient(cfg)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected an error mentioning %q, got %v", tc.want, err)
+			}
+			if strings.Contains(err.Error(), "PRIVATE KEY") {
+				t.Fatalf("the error quotes the key: %v", err)
+			}
+		})
+	}
+}
+
+// TestTransportDefaultsUntouched covers a configuration with none of the
+// options set: the transport keeps Go's own TLS settings and the proxy from
+// the environment.
+func TestTransportDefaultsUntouched(t *testing.T) {
+	tr, err := newTransport(Config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tr.TLSClientConfig != nil && (tr.TLSClientConfig.RootCAs != nil || tr.TLSClientConfig.InsecureSkipVerify || len(tr.TLSClientConfig.Certificates) != 0) {
+		t.Fatalf("expected Go's default TLS settings, got %+v", tr.TLSClientConfig)
+	}
+	if tr.Proxy == nil {
+		t.Fatal("expected the proxy to come from the environment")
+	}
+}
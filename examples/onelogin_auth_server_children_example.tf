@@ -0,0 +1,44 @@
+resource onelogin_auth_servers api {
+  name = "acctest-api"
+  description = "Contacts API"
+  configuration {
+    resource_identifier = "https://example.com/acctest-contacts"
+    audiences = ["https://example.com/acctest-contacts"]
+  }
+}
+
+resource onelogin_auth_server_scope read {
+  auth_server_id = onelogin_auth_servers.api.id
+  value = "contacts:read"
+  description = "Read contacts"
+}
+
+resource onelogin_auth_server_scope write {
+  auth_server_id = onelogin_auth_servers.api.id
+  value = "contacts:write"
+  description = "Change contacts"
+}
+
+resource onelogin_auth_server_claim groups {
+  auth_server_id = onelogin_auth_servers.api.id
+  name = "groups"
+  user_attribute_mappings = "memberOf"
+}
+
+resource onelogin_oidc_apps client {
+  connector_id = 108419
+  name = "acctest-api-client"
+
+  configuration = {
+    redirect_uri = "https://localhost:3000/callback"
+    login_url = "https://www.test.com"
+    oidc_application_type = 0
+    token_endpoint_auth_method = 1
+  }
+}
+
+resource onelogin_auth_server_client_app client {
+  auth_server_id = onelogin_auth_servers.api.id
+  app_id = onelogin_oidc_apps.client.id
+  scope_ids = [onelogin_auth_server_scope.read.scope_id]
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	olsdk "github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
+
+	"github.com/onelogin/terraform-provider-onelogin/export"
+	"github.com/onelogin/terraform-provider-onelogin/onelogin"
+)
+
+const exportUsage = `Usage: terraform-provider-onelogin export [-dir DIR] [-kinds KINDS]
+
+Writes the objects in a OneLogin tenant out as Terraform configuration, one
+file per kind of object, with an import block for each resource in imports.tf.
+Run terraform plan in the directory to review the imports, then apply.
+
+The tenant and credentials are read from the environment, as the provider
+reads them. ONELOGIN_CLIENT_ID, ONELOGIN_CLIENT_SECRET and ONELOGIN_API_URL
+are required. ONELOGIN_SUBDOMAIN is optional and only supplements the URL.
+The provider's other ONELOGIN_* settings apply as well.
+
+Options:
+`
+
+// runExport runs the export subcommand and returns the exit code: 0 when
+// everything was exported, 1 when something could not be, 2 for bad usage.
+func runExport(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, exportUsage)
+		fs.PrintDefaults()
+	}
+	dir := fs.String("dir", "onelogin-export", "directory to write to; it is created if need be, and no file in it is overwritten")
+	kinds := fs.String("kinds", "", "comma-separated kinds of object to export, of "+strings.Join(export.Kinds(), ", ")+" (default all)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "unexpected argument %q\n", fs.Arg(0))
+		fs.Usage()
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Configured with nothing, the provider takes every setting from its
+	// environment defaults, just as a provider block that sets nothing would.
+	p := onelogin.Provider()
+	diags := p.Configure(ctx, terraform.NewResourceConfigRaw(map[string]interface{}{}))
+	printDiagnostics(stderr, diags)
+	if diags.HasError() {
+		return 1
+	}
+	sdk, ok := p.Meta().(*olsdk.OneloginSDK)
+	if !ok {
+		fmt.Fprintf(stderr, "Error: unexpected provider client %T\n", p.Meta())
+		return 1
+	}
+
+	opts := export.Options{}
+	if *kinds != "" {
+		for _, k := range strings.Split(*kinds, ",") {
+			opts.Kinds = append(opts.Kinds, strings.TrimSpace(k))
+		}
+	}
+	src := export.Source{Provider: p, HTTPClient: sdk.Client.HttpClient, URL: sdk.Client.OLdomain}
+	result, err := export.Run(ctx, src, *dir, opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	types := make([]string, 0, len(result.Resources))
+	for typ := range result.Resources {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+	for _, typ := range types {
+		fmt.Fprintf(stdout, "%6d %s\n", result.Resources[typ], typ)
+	}
+	if len(types) == 0 {
+		fmt.Fprintln(stdout, "Nothing to export.")
+	} else {
+		fmt.Fprintf(stdout, "Written to %s.\n", *dir)
+	}
+
+	if len(result.Problems) > 0 {
+		fmt.Fprintf(stderr, "\n%d objects could not be exported:\n", len(result.Problems))
+		for _, problem := range result.Problems {
+			fmt.Fprintf(stderr, "  %v\n", problem)
+		}
+		return 1
+	}
+	return 0
+}
+
+func printDiagnostics(w io.Writer, diags diag.Diagnostics) {
+	for _, d := range diags {
+		severity := "Warning"
+		if d.Severity == diag.Error {
+			severity = "Error"
+		}
+		fmt.Fprintf(w, "%s: %s\n", severity, d.Summary)
+		if d.Detail != "" {
+			fmt.Fprintf(w, "  %s\n", d.Detail)
+		}
+	}
+}
@@ -0,0 +1,435 @@
+// Package export writes the objects in an existing OneLogin tenant out as
+// Terraform configuration, with an import block for each, so that bringing a
+// tenant under Terraform does not start with writing hundreds of resources by
+// hand.
+//
+// Objects are listed straight from the API, then read the way terraform
+// import reads them: through the resource's importer and its Read, which is
+// where each ol_schema package's Flatten functions are called. The
+// configuration written is therefore what Read would record for the object,
+// and a plan run straight after the import shows no changes.
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Source is the tenant an export reads.
+type Source struct {
+	// Provider is the OneLogin provider, already configured. Its resources
+	// read every object that is exported.
+	Provider *schema.Provider
+	// HTTPClient and URL list each kind of object. The client authenticates
+	// its own requests, as an apiclient client does.
+	HTTPClient HTTPClient
+	URL        string
+}
+
+// HTTPClient sends an export's requests. *http.Client is one.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Options narrow what an export writes.
+type Options struct {
+	// Kinds, if set, limits the export to these kinds of object, by the names
+	// in Kinds. All of them are exported otherwise.
+	Kinds []string
+}
+
+// Result is what an export wrote.
+type Result struct {
+	// Resources counts the resources written, by resource type.
+	Resources map[string]int
+	// Problems are the objects that could not be exported, one error each.
+	// The rest of the export goes ahead without them.
+	Problems []error
+}
+
+// object is one object as the API lists it.
+type object = map[string]interface{}
+
+// kind is one kind of object an export lists.
+type kind struct {
+	name string
+	// file is the file its resources are written to.
+	file string
+	// path is its list endpoint.
+	path string
+	// perApp lists it once for each app, with the app's ID in place of
+	// {app_id} in path.
+	perApp bool
+	// filters, if set, lists it once with each of these query parameters and
+	// exports everything found, for an endpoint that leaves objects out unless
+	// asked for them.
+	filters []url.Values
+	// resourceType is the resource an object is managed by.
+	resourceType func(o object) string
+	// label is what an object's resource is named after.
+	label func(o object) string
+	// importID is the ID terraform import takes for an object. app is the ID
+	// of the app it belongs to, for kinds listed per app.
+	importID func(app string, o object) string
+}
+
+// OneLogin's auth_method values for the apps that have a resource of their own.
+const (
+	authMethodSAML = 2
+	authMethodOIDC = 8
+)
+
+// kinds are exported in this order. Apps come first, since app rules are
+// listed per app.
+var kinds = []kind{
+	{
+		name: "apps",
+		file: "apps.tf",
+		path: "/api/2/apps",
+		resourceType: func(o object) string {
+			method, _ := o["auth_method"].(float64)
+			switch int(method) {
+			case authMethodSAML:
+				return "onelogin_saml_apps"
+			case authMethodOIDC:
+				return "onelogin_oidc_apps"
+			}
+			return "onelogin_apps"
+		},
+	},
+	{
+		name:         "app_rules",
+		file:         "app_rules.tf",
+		path:         "/api/2/apps/{app_id}/rules",
+		perApp:       true,
+		resourceType: fixedType("onelogin_app_rules"),
+		importID: func(app string, o object) string {
+			return app + ":" + objectID(o)
+		},
+	},
+	{name: "roles", file: "roles.tf", path: "/api/2/roles", resourceType: fixedType("onelogin_roles")},
+	{
+		name:         "users",
+		file:         "users.tf",
+		path:         "/api/2/users",
+		resourceType: fixedType("onelogin_users"),
+		label: func(o object) string {
+			if username, _ := o["username"].(string); username != "" {
+				return username
+			}
+			email, _ := o["email"].(string)
+			return email
+		},
+	},
+	{
+		name: "user_mappings",
+		file: "user_mappings.tf",
+		path: "/api/2/mappings",
+		// The list leaves disabled mappings out unless asked for them.
+		filters:      []url.Values{{"enabled": {"true"}}, {"enabled": {"false"}}},
+		resourceType: fixedType("onelogin_user_mappings"),
+	},
+	{name: "privileges", file: "privileges.tf", path: "/api/2/privileges", resourceType: fixedType("onelogin_privileges")},
+	{name: "smarthooks", file: "smarthooks.tf", path: "/api/2/hooks", resourceType: fixedType("onelogin_smarthooks")},
+	{name: "groups", file: "groups.tf", path: "/api/2/groups", resourceType: fixedType("onelogin_groups")},
+	{name: "self_registration_profiles", file: "self_registration_profiles.tf", path: "/api/2/self_registration_profiles", resourceType: fixedType("onelogin_self_registration_profiles")},
+}
+
+// Kinds are the names of the kinds of object an export can write.
+func Kinds() []string {
+	names := make([]string, len(kinds))
+	for i, k := range kinds {
+		names[i] = k.name
+	}
+	return names
+}
+
+func fixedType(t string) func(object) string {
+	return func(object) string { return t }
+}
+
+// importsFile holds every import block, apart from the resources so that it
+// can be deleted in one go once they have been imported.
+const importsFile = "imports.tf"
+
+// Run exports src into dir, which is created if need be. It refuses to write
+// over a file that is already there.
+func Run(ctx context.Context, src Source, dir string, opts Options) (*Result, error) {
+	selected, err := selectKinds(opts.Kinds)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{importsFile}
+	for _, k := range selected {
+		files = append(files, k.file)
+	}
+	for _, f := range files {
+		if _, err := os.Stat(filepath.Join(dir, f)); err == nil {
+			return nil, fmt.Errorf("%s already exists; export into an empty directory", filepath.Join(dir, f))
+		}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	e := &exporter{
+		src:    src,
+		result: &Result{Resources: map[string]int{}},
+		labels: newLabels(),
+		out:    newOutput(),
+	}
+	for _, k := range selected {
+		if err := e.export(ctx, k); err != nil {
+			return nil, err
+		}
+	}
+	if err := e.out.write(dir); err != nil {
+		return nil, err
+	}
+	return e.result, nil
+}
+
+func selectKinds(names []string) ([]kind, error) {
+	if len(names) == 0 {
+		return kinds, nil
+	}
+	want := map[string]bool{}
+	for _, n := range names {
+		want[n] = true
+	}
+	var selected []kind
+	for _, k := range kinds {
+		if want[k.name] {
+			selected = append(selected, k)
+			delete(want, k.name)
+		}
+	}
+	for n := range want {
+		return nil, fmt.Errorf("unknown kind %q; the kinds are %s", n, strings.Join(Kinds(), ", "))
+	}
+	return selected, nil
+}
+
+type exporter struct {
+	src    Source
+	result *Result
+	labels *labels
+	out    *output
+	// apps are the IDs of the apps in the tenant, listed once for the kinds
+	// listed per app.
+	apps []string
+}
+
+func (e *exporter) export(ctx context.Context, k kind) error {
+	apps := []string{""}
+	if k.perApp {
+		var err error
+		if apps, err = e.appIDs(ctx); err != nil {
+			return err
+		}
+	}
+
+	filters := k.filters
+	if filters == nil {
+		filters = []url.Values{nil}
+	}
+
+	for _, app := range apps {
+		path := strings.ReplaceAll(k.path, "{app_id}", url.PathEscape(app))
+		seen := map[string]bool{}
+		for _, filter := range filters {
+			objects, err := e.list(ctx, path, filter)
+			if err != nil {
+				return fmt.Errorf("listing %s: %v", k.name, err)
+			}
+			for _, o := range objects {
+				// Filters that overlap must not export an object twice.
+				id := objectID(o)
+				if seen[id] {
+					continue
+				}
+				seen[id] = true
+				if err := e.exportObject(ctx, k, app, o); err != nil {
+					e.result.Problems = append(e.result.Problems, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (e *exporter) appIDs(ctx context.Context) ([]string, error) {
+	if e.apps != nil {
+		return e.apps, nil
+	}
+	apps, err := e.list(ctx, "/api/2/apps", nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing apps: %v", err)
+	}
+	e.apps = []string{}
+	for _, app := range apps {
+		e.apps = append(e.apps, objectID(app))
+	}
+	return e.apps, nil
+}
+
+// exportObject reads one object through its resource and adds it to the
+// output.
+func (e *exporter) exportObject(ctx context.Context, k kind, app string, o object) error {
+	typ := k.resourceType(o)
+	id := objectID(o)
+	if k.importID != nil {
+		id = k.importID(app, o)
+	}
+	r, ok := e.src.Provider.ResourcesMap[typ]
+	if !ok {
+		return fmt.Errorf("%s %s: the provider has no %s resource", k.name, id, typ)
+	}
+
+	d, err := e.read(ctx, r, id)
+	if err != nil {
+		return fmt.Errorf("%s %s: %v", typ, id, err)
+	}
+	if d == nil {
+		// Gone between being listed and being read.
+		return nil
+	}
+
+	name := ""
+	if k.label != nil {
+		name = k.label(o)
+	} else {
+		name, _ = o["name"].(string)
+	}
+	label := e.labels.next(typ, name, id)
+	e.out.add(k.file, typ, label, id, r, d)
+	e.result.Resources[typ]++
+	return nil
+}
+
+// read imports and reads an object as terraform import would. It returns nil
+// if Read finds the object gone.
+func (e *exporter) read(ctx context.Context, r *schema.Resource, id string) (*schema.ResourceData, error) {
+	meta := e.src.Provider.Meta()
+	d := r.Data(nil)
+	d.SetId(id)
+
+	imported := []*schema.ResourceData{d}
+	if r.Importer != nil && r.Importer.StateContext != nil {
+		var err error
+		if imported, err = r.Importer.StateContext(ctx, d, meta); err != nil {
+			return nil, err
+		}
+	}
+	if len(imported) != 1 {
+		return nil, fmt.Errorf("import gave %d resources, expected one", len(imported))
+	}
+	d = imported[0]
+
+	if r.ReadContext == nil {
+		return nil, fmt.Errorf("the resource cannot be read")
+	}
+	if diags := r.ReadContext(ctx, d, meta); diags.HasError() {
+		var msgs []string
+		for _, diag := range diags {
+			msgs = append(msgs, diag.Summary)
+		}
+		return nil, fmt.Errorf("%s", strings.Join(msgs, "; "))
+	}
+	if d.Id() == "" {
+		return nil, nil
+	}
+	return d, nil
+}
+
+// listPageLimit is the page size asked for. Later pages are fetched with the
+// After-Cursor of the one before, which the v2 API refuses alongside a limit.
+const listPageLimit = "100"
+
+// maxListPages bounds a list walk, so that a server that never stops giving
+// cursors fails the export rather than hanging it.
+const maxListPages = 10000
+
+// list fetches every object a list endpoint has that matches filter. The
+// filter is sent with every page, not only the first.
+func (e *exporter) list(ctx context.Context, path string, filter url.Values) ([]object, error) {
+	base := strings.TrimRight(e.src.URL, "/") + path
+	withFilter := func(q url.Values) url.Values {
+		for k, v := range filter {
+			q[k] = v
+		}
+		return q
+	}
+	query := withFilter(url.Values{"limit": {listPageLimit}})
+
+	var all []object
+	for page := 0; page < maxListPages; page++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := e.src.HTTPClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("GET %s: %s: %s", path, resp.Status, strings.TrimSpace(string(body)))
+		}
+		objects, err := decodeList(body)
+		if err != nil {
+			return nil, fmt.Errorf("GET %s: %v", path, err)
+		}
+		all = append(all, objects...)
+
+		cursor := resp.Header.Get("After-Cursor")
+		if cursor == "" || cursor == query.Get("cursor") {
+			return all, nil
+		}
+		query = withFilter(url.Values{"cursor": {cursor}})
+	}
+	return nil, fmt.Errorf("GET %s: more than %d pages", path, maxListPages)
+}
+
+// decodeList reads a list response: a bare array, as the v2 API gives, or
+// one wrapped in "data", as the v1 API does.
+func decodeList(body []byte) ([]object, error) {
+	var items []object
+	if err := json.Unmarshal(body, &items); err == nil {
+		return items, nil
+	}
+	var wrapped struct {
+		Data []object `json:"data"`
+	}
+	if err := json.Unmarshal(body, &wrapped); err != nil || wrapped.Data == nil {
+		return nil, fmt.Errorf("unexpected list response: %.200s", body)
+	}
+	return wrapped.Data, nil
+}
+
+// objectID is the id of an API object as a string, whether the API gave it as
+// a number or a UUID.
+func objectID(o object) string {
+	switch v := o["id"].(type) {
+	case float64:
+		return strconv.FormatInt(int64(v), 10)
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,303 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/onelogin/terraform-provider-onelogin/apiclient"
+	"github.com/onelogin/terraform-provider-onelogin/fakeapi"
+)
+
+// testProvider stands in for the OneLogin provider, which needs the OneLogin
+// SDK: a few resources that read the fake API directly, enough to exercise
+// each path through an export.
+func testProvider(t *testing.T, srv *fakeapi.Server) (*schema.Provider, *http.Client) {
+	t.Helper()
+
+	client, err := apiclient.NewHTTPClient(apiclient.Config{
+		ClientID:     fakeapi.ClientID,
+		ClientSecret: fakeapi.ClientSecret,
+		URL:          srv.URL,
+		Timeout:      10 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	get := func(ctx context.Context, path string, d *schema.ResourceData, fields ...string) diag.Diagnostics {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+path, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound {
+			d.SetId("")
+			return nil
+		}
+		var o map[string]interface{}
+		if err := json.NewDecoder(resp.Body).Decode(&o); err != nil {
+			return diag.FromErr(err)
+		}
+		for _, f := range fields {
+			if err := d.Set(f, o[f]); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+		return nil
+	}
+
+	app := func() *schema.Resource {
+		return &schema.Resource{
+			Importer: &schema.ResourceImporter{StateContext: schema.ImportStatePassthroughContext},
+			ReadContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+				return get(ctx, "/api/2/apps/"+d.Id(), d, "name", "connector_id", "description", "visible", "parameters")
+			},
+			Schema: map[string]*schema.Schema{
+				"name":         {Type: schema.TypeString, Required: true},
+				"connector_id": {Type: schema.TypeInt, Required: true},
+				"description":  {Type: schema.TypeString, Optional: true},
+				"visible":      {Type: schema.TypeBool, Optional: true, Default: true},
+				"parameters": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+						"param_key_name": {Type: schema.TypeString, Required: true},
+						"label":          {Type: schema.TypeString, Optional: true},
+					}},
+				},
+				"created_at": {Type: schema.TypeString, Computed: true},
+			},
+		}
+	}
+
+	p := &schema.Provider{
+		ResourcesMap: map[string]*schema.Resource{
+			"onelogin_apps":      app(),
+			"onelogin_saml_apps": app(),
+			"onelogin_roles": {
+				ReadContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+					return get(ctx, "/api/2/roles/"+d.Id(), d, "name")
+				},
+				Schema: map[string]*schema.Schema{
+					"name": {Type: schema.TypeString, Required: true},
+				},
+			},
+			"onelogin_user_mappings": {
+				ReadContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+					return get(ctx, "/api/2/mappings/"+d.Id(), d, "name", "enabled")
+				},
+				Schema: map[string]*schema.Schema{
+					"name":    {Type: schema.TypeString, Required: true},
+					"enabled": {Type: schema.TypeBool, Optional: true},
+				},
+			},
+			"onelogin_app_rules": {
+				Importer: &schema.ResourceImporter{
+					StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+						app, rule, _ := strings.Cut(d.Id(), ":")
+						d.SetId(rule)
+						appID, _ := strconv.Atoi(app)
+						d.Set("app_id", appID)
+						return []*schema.ResourceData{d}, nil
+					},
+				},
+				ReadContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+					return get(ctx, fmt.Sprintf("/api/2/apps/%d/rules/%s", d.Get("app_id").(int), d.Id()), d, "name", "enabled")
+				},
+				Schema: map[string]*schema.Schema{
+					"app_id":  {Type: schema.TypeInt, Required: true},
+					"name":    {Type: schema.TypeString, Required: true},
+					"enabled": {Type: schema.TypeBool, Optional: true},
+				},
+			},
+		},
+	}
+	p.SetMeta(client)
+	return p, client
+}
+
+func TestExport(t *testing.T) {
+	srv := fakeapi.NewServer()
+	defer srv.Close()
+	p, client := testProvider(t, srv)
+
+	engineering := srv.Seed(fakeapi.Roles, map[string]interface{}{"name": "Engineering"})
+	again := srv.Seed(fakeapi.Roles, map[string]interface{}{"name": "engineering"})
+	srv.Seed(fakeapi.Roles, map[string]interface{}{"name": "2FA ${enforced}"})
+
+	web := srv.Seed(fakeapi.Apps, map[string]interface{}{
+		"name": "Web", "connector_id": float64(1), "visible": true,
+		"parameters": []interface{}{map[string]interface{}{"param_key_name": "email", "label": "Email"}},
+	})
+	saml := srv.Seed(fakeapi.Apps, map[string]interface{}{
+		"name": "Payroll", "connector_id": float64(2), "auth_method": float64(authMethodSAML),
+		"description": "Pays people", "visible": false,
+	})
+	// The test provider has no onelogin_oidc_apps, so this one cannot be read.
+	oidc := srv.Seed(fakeapi.Apps, map[string]interface{}{"name": "Portal", "connector_id": float64(3), "auth_method": float64(authMethodOIDC)})
+	rule := srv.Seed(fakeapi.AppRules, map[string]interface{}{"app_id": saml, "name": "Admins", "enabled": true, "position": float64(1)})
+	srv.Seed(fakeapi.Mappings, map[string]interface{}{"name": "Engineers", "match": "all", "enabled": true, "position": float64(1)})
+	// Listed only when asked for with enabled=false.
+	retired := srv.Seed(fakeapi.Mappings, map[string]interface{}{"name": "Retired", "match": "all", "enabled": false})
+
+	dir := filepath.Join(t.TempDir(), "out")
+	src := Source{Provider: p, HTTPClient: client, URL: srv.URL}
+	result, err := Run(context.Background(), src, dir, Options{Kinds: []string{"apps", "app_rules", "roles", "user_mappings"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Problems) != 1 || !strings.Contains(result.Problems[0].Error(), "onelogin_oidc_apps") || !strings.Contains(result.Problems[0].Error(), oidc) {
+		t.Fatalf("expected the OIDC app to be the one problem, got %v", result.Problems)
+	}
+	want := map[string]int{"onelogin_apps": 1, "onelogin_saml_apps": 1, "onelogin_app_rules": 1, "onelogin_roles": 3, "onelogin_user_mappings": 2}
+	for typ, n := range want {
+		if result.Resources[typ] != n {
+			t.Errorf("expected %d %s, got %d", n, typ, result.Resources[typ])
+		}
+	}
+
+	files := map[string]string{}
+	parser := hclparse.NewParser()
+	for _, name := range []string{"apps.tf", "app_rules.tf", "roles.tf", "user_mappings.tf", "imports.tf"} {
+		raw, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("expected %s to be written: %v", name, err)
+		}
+		if _, diags := parser.ParseHCL(raw, name); diags.HasErrors() {
+			t.Fatalf("%s does not parse: %v\n%s", name, diags, raw)
+		}
+		files[name] = string(raw)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "users.tf")); err == nil {
+		t.Error("expected users.tf not to be written for a kind not asked for")
+	}
+
+	expect := func(file string, snippets ...string) {
+		t.Helper()
+		for _, s := range snippets {
+			if !strings.Contains(files[file], s) {
+				t.Errorf("expected %s to contain %q, got:\n%s", file, s, files[file])
+			}
+		}
+	}
+	reject := func(file string, snippets ...string) {
+		t.Helper()
+		for _, s := range snippets {
+			if strings.Contains(files[file], s) {
+				t.Errorf("expected %s not to contain %q, got:\n%s", file, s, files[file])
+			}
+		}
+	}
+
+	expect("roles.tf",
+		`resource "onelogin_roles" "engineering" {`,
+		`resource "onelogin_roles" "engineering_2" {`,
+		// A name that cannot start a label, and a template sequence that
+		// must come out as literal text.
+		`resource "onelogin_roles" "_2fa_enforced" {`,
+		`name = "2FA $${enforced}"`,
+	)
+	expect("apps.tf",
+		`resource "onelogin_apps" "web" {`,
+		`resource "onelogin_saml_apps" "payroll" {`,
+		`description  = "Pays people"`,
+		`visible      = false`,
+		"parameters {",
+		`param_key_name = "email"`,
+	)
+	// Computed attributes, defaults and empty values are left out.
+	reject("apps.tf", "created_at", "visible      = true", `description  = ""`, "portal")
+	expect("app_rules.tf", `resource "onelogin_app_rules" "admins" {`, "app_id  = "+saml, "enabled = true")
+	expect("user_mappings.tf",
+		`resource "onelogin_user_mappings" "engineers" {`,
+		`resource "onelogin_user_mappings" "retired" {`,
+	)
+	expect("imports.tf",
+		"to = onelogin_user_mappings.retired\n",
+		`id = "`+retired+`"`,
+		"to = onelogin_roles.engineering\n",
+		`id = "`+engineering+`"`,
+		"to = onelogin_roles.engineering_2\n",
+		`id = "`+again+`"`,
+		"to = onelogin_apps.web\n",
+		`id = "`+web+`"`,
+		"to = onelogin_app_rules.admins\n",
+		`id = "`+saml+":"+rule+`"`,
+	)
+
+	t.Run("refuses to overwrite", func(t *testing.T) {
+		_, err := Run(context.Background(), src, dir, Options{Kinds: []string{"roles"}})
+		if err == nil || !strings.Contains(err.Error(), "already exists") {
+			t.Fatalf("expected an error, got %v", err)
+		}
+	})
+
+	t.Run("unknown kind", func(t *testing.T) {
+		_, err := Run(context.Background(), src, t.TempDir(), Options{Kinds: []string{"widgets"}})
+		if err == nil || !strings.Contains(err.Error(), "widgets") {
+			t.Fatalf("expected an error, got %v", err)
+		}
+	})
+}
+
+func TestExportPagesThroughLists(t *testing.T) {
+	srv := fakeapi.NewServer()
+	defer srv.Close()
+	p, client := testProvider(t, srv)
+
+	const roles = 250
+	for i := 0; i < roles; i++ {
+		srv.Seed(fakeapi.Roles, map[string]interface{}{"name": fmt.Sprintf("role %d", i)})
+	}
+
+	result, err := Run(context.Background(), Source{Provider: p, HTTPClient: client, URL: srv.URL}, t.TempDir(), Options{Kinds: []string{"roles"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := result.Resources["onelogin_roles"]; n != roles {
+		t.Fatalf("expected %d roles, got %d", roles, n)
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+	for in, want := range map[string]string{
+		"Engineering":          "engineering",
+		"Sales & Marketing":    "sales_marketing",
+		"  leading/trailing  ": "leading_trailing",
+		"jdoe@example.com":     "jdoe_example_com",
+		"2FA":                  "_2fa",
+		"Ünïcode":              "n_code",
+		"***":                  "",
+	} {
+		if got := identifier(in); got != want {
+			t.Errorf("identifier(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	l := newLabels()
+	for _, want := range []string{"admins", "admins_2", "admins_3"} {
+		if got := l.next("onelogin_roles", "Admins", "1"); got != want {
+			t.Errorf("expected %q, got %q", want, got)
+		}
+	}
+	if got := l.next("onelogin_apps", "Admins", "1"); got != "admins" {
+		t.Errorf("expected labels to be unique per type only, got %q", got)
+	}
+	if got := l.next("onelogin_roles", "***", "42"); got != "roles_42" {
+		t.Errorf("expected a name with nothing usable to fall back to the ID, got %q", got)
+	}
+}
@@ -0,0 +1,289 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// labels hands out resource names, unique within each resource type.
+type labels struct {
+	taken map[string]map[string]bool
+}
+
+func newLabels() *labels {
+	return &labels{taken: map[string]map[string]bool{}}
+}
+
+// next names a resource of type typ after name, made into an identifier. A
+// name taken already gets a number after it, and one with nothing usable in it
+// is named after id instead.
+func (l *labels) next(typ, name, id string) string {
+	base := identifier(name)
+	if base == "" {
+		base = identifier(strings.TrimPrefix(typ, "onelogin_") + "_" + id)
+	}
+	if l.taken[typ] == nil {
+		l.taken[typ] = map[string]bool{}
+	}
+	label := base
+	for n := 2; l.taken[typ][label]; n++ {
+		label = base + "_" + strconv.Itoa(n)
+	}
+	l.taken[typ][label] = true
+	return label
+}
+
+// identifier lowercases s and turns every run of characters a Terraform name
+// cannot have into one underscore. A name cannot start with a digit either, so
+// one that would gets an underscore in front.
+func identifier(s string) string {
+	var b strings.Builder
+	pending := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if pending && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			pending = false
+			b.WriteRune(r)
+			continue
+		}
+		pending = true
+	}
+	out := b.String()
+	if out != "" && out[0] >= '0' && out[0] <= '9' {
+		out = "_" + out
+	}
+	return out
+}
+
+// output collects resources and import blocks until they are written.
+type output struct {
+	files   map[string]*hclwrite.File
+	imports *hclwrite.File
+}
+
+func newOutput() *output {
+	imports := hclwrite.NewEmptyFile()
+	imports.Body().AppendUnstructuredTokens(comment(
+		"Import blocks for the resources exported alongside this file. Once terraform apply\n" +
+			"has imported them, this file can be deleted.",
+	))
+	return &output{files: map[string]*hclwrite.File{}, imports: imports}
+}
+
+func comment(text string) hclwrite.Tokens {
+	var tokens hclwrite.Tokens
+	for _, line := range strings.Split(text, "\n") {
+		tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenComment, Bytes: []byte("# " + line + "\n")})
+	}
+	return tokens
+}
+
+// add writes a resource block for d, whose resource is r, to file, and an
+// import block for it.
+func (o *output) add(file, typ, label, importID string, r *schema.Resource, d *schema.ResourceData) {
+	f, ok := o.files[file]
+	if !ok {
+		f = hclwrite.NewEmptyFile()
+		o.files[file] = f
+	} else {
+		f.Body().AppendNewline()
+	}
+
+	values := map[string]interface{}{}
+	for k := range r.Schema {
+		values[k] = d.Get(k)
+	}
+	block := f.Body().AppendNewBlock("resource", []string{typ, label})
+	writeBody(block.Body(), r.Schema, values)
+
+	o.imports.Body().AppendNewline()
+	imp := o.imports.Body().AppendNewBlock("import", nil)
+	imp.Body().SetAttributeTraversal("to", hcl.Traversal{
+		hcl.TraverseRoot{Name: typ},
+		hcl.TraverseAttr{Name: label},
+	})
+	imp.Body().SetAttributeValue("id", cty.StringVal(importID))
+}
+
+func (o *output) write(dir string) error {
+	files := map[string]*hclwrite.File{}
+	for name, f := range o.files {
+		files[name] = f
+	}
+	if len(o.files) > 0 {
+		files[importsFile] = o.imports
+	}
+	for name, f := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), hclwrite.Format(f.Bytes()), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeBody writes values, the attributes of a resource or of one of its
+// nested blocks, as configuration for s. Only what can be configured is
+// written, and an optional attribute only where it differs from what leaving
+// it out would give. Arguments come first, required then optional, and
+// nested blocks after them.
+func writeBody(body *hclwrite.Body, s map[string]*schema.Schema, values map[string]interface{}) {
+	var required, optional, blocks []string
+	for k, sch := range s {
+		if !configurable(k, sch) {
+			continue
+		}
+		v := values[k]
+		if !sch.Required && isDefault(sch, v) {
+			continue
+		}
+		switch {
+		case isBlock(sch):
+			blocks = append(blocks, k)
+		case sch.Required:
+			required = append(required, k)
+		default:
+			optional = append(optional, k)
+		}
+	}
+	sort.Strings(required)
+	sort.Strings(optional)
+	sort.Strings(blocks)
+
+	for _, k := range append(required, optional...) {
+		body.SetAttributeValue(k, ctyValue(values[k]))
+	}
+	for _, k := range blocks {
+		elem := s[k].Elem.(*schema.Resource)
+		for _, item := range listOf(values[k]) {
+			m, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			body.AppendNewline()
+			writeBody(body.AppendNewBlock(k, nil).Body(), elem.Schema, m)
+		}
+	}
+}
+
+// configurable is whether k is something configuration sets, rather than
+// something only Read does.
+func configurable(k string, sch *schema.Schema) bool {
+	if k == "id" || sch.Deprecated != "" {
+		return false
+	}
+	return sch.Required || sch.Optional
+}
+
+func isBlock(sch *schema.Schema) bool {
+	if sch.Type != schema.TypeList && sch.Type != schema.TypeSet {
+		return false
+	}
+	_, ok := sch.Elem.(*schema.Resource)
+	return ok
+}
+
+// isDefault is whether v is what leaving the attribute out of configuration
+// gives: its default, or with none, the zero value.
+func isDefault(sch *schema.Schema, v interface{}) bool {
+	if sch.Default != nil {
+		return reflect.DeepEqual(sch.Default, v)
+	}
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case int:
+		return v == 0
+	case float64:
+		return v == 0
+	case bool:
+		return !v
+	case *schema.Set:
+		return v.Len() == 0
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// listOf is the elements of a list or set value.
+func listOf(v interface{}) []interface{} {
+	switch v := v.(type) {
+	case *schema.Set:
+		return v.List()
+	case []interface{}:
+		return v
+	}
+	return nil
+}
+
+// ctyValue converts a value as ResourceData gives it into one hclwrite can
+// write. Sets of numbers or strings are sorted, so that exporting the same
+// tenant twice writes the same files.
+func ctyValue(v interface{}) cty.Value {
+	switch v := v.(type) {
+	case string:
+		return cty.StringVal(v)
+	case int:
+		return cty.NumberIntVal(int64(v))
+	case float64:
+		return cty.NumberFloatVal(v)
+	case bool:
+		return cty.BoolVal(v)
+	case *schema.Set:
+		items := v.List()
+		sort.SliceStable(items, func(i, j int) bool { return lessScalar(items[i], items[j]) })
+		return ctyValue(items)
+	case []interface{}:
+		if len(v) == 0 {
+			return cty.EmptyTupleVal
+		}
+		vals := make([]cty.Value, len(v))
+		for i, item := range v {
+			vals[i] = ctyValue(item)
+		}
+		return cty.TupleVal(vals)
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return cty.EmptyObjectVal
+		}
+		vals := make(map[string]cty.Value, len(v))
+		for k, item := range v {
+			vals[k] = ctyValue(item)
+		}
+		return cty.ObjectVal(vals)
+	case nil:
+		return cty.NullVal(cty.DynamicPseudoType)
+	}
+	return cty.StringVal(fmt.Sprint(v))
+}
+
+func lessScalar(a, b interface{}) bool {
+	switch a := a.(type) {
+	case int:
+		if b, ok := b.(int); ok {
+			return a < b
+		}
+	case string:
+		if b, ok := b.(string); ok {
+			return a < b
+		}
+	}
+	return false
+}
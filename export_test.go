@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/onelogin/terraform-provider-onelogin/fakeapi"
+)
+
+// TestExportCommand runs the export subcommand end to end, with the provider
+// itself reading a fake tenant.
+func TestExportCommand(t *testing.T) {
+	srv := fakeapi.NewServer()
+	defer srv.Close()
+	t.Setenv("ONELOGIN_CLIENT_ID", fakeapi.ClientID)
+	t.Setenv("ONELOGIN_CLIENT_SECRET", fakeapi.ClientSecret)
+	t.Setenv("ONELOGIN_API_URL", srv.URL)
+	t.Setenv("ONELOGIN_SUBDOMAIN", "")
+
+	role := srv.Seed(fakeapi.Roles, map[string]interface{}{"name": "Engineering"})
+	user := srv.Seed(fakeapi.Users, map[string]interface{}{
+		"username": "jdoe", "email": "jdoe@example.com", "firstname": "Jane", "lastname": "Doe",
+	})
+
+	dir := filepath.Join(t.TempDir(), "tenant")
+	var stdout, stderr bytes.Buffer
+	if code := runExport([]string{"-dir", dir, "-kinds", "roles, users"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d\n%s", code, stderr.String())
+	}
+	for _, want := range []string{"1 onelogin_roles", "1 onelogin_users", "Written to " + dir} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("expected the summary to contain %q, got:\n%s", want, stdout.String())
+		}
+	}
+
+	read := func(name string) string {
+		t.Helper()
+		raw, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("expected %s to be written: %v", name, err)
+		}
+		return string(raw)
+	}
+	if roles := read("roles.tf"); !strings.Contains(roles, `resource "onelogin_roles" "engineering" {`) || !strings.Contains(roles, `name = "Engineering"`) {
+		t.Errorf("unexpected roles.tf:\n%s", roles)
+	}
+	if users := read("users.tf"); !strings.Contains(users, `resource "onelogin_users" "jdoe" {`) || !strings.Contains(users, `"jdoe@example.com"`) {
+		t.Errorf("unexpected users.tf:\n%s", users)
+	}
+	imports := read("imports.tf")
+	for _, want := range []string{"to = onelogin_roles.engineering", `id = "` + role + `"`, "to = onelogin_users.jdoe", `id = "` + user + `"`} {
+		if !strings.Contains(imports, want) {
+			t.Errorf("expected imports.tf to contain %q, got:\n%s", want, imports)
+		}
+	}
+}
+
+func TestExportCommandUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := runExport([]string{"-nope"}, &stdout, &stderr); code != 2 {
+		t.Fatalf("expected exit code 2 for an unknown flag, got %d", code)
+	}
+	if code := runExport([]string{"stray"}, &stdout, &stderr); code != 2 {
+		t.Fatalf("expected exit code 2 for an argument, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "ONELOGIN_CLIENT_ID") {
+		t.Fatalf("expected the usage to say where credentials come from, got:\n%s", stderr.String())
+	}
+}
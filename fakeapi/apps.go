@@ -0,0 +1,259 @@
+package fakeapi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// OneLogin's auth_method values for the app kinds the fake tells apart.
+const (
+	authMethodPassword = 0
+	authMethodSAML     = 2
+	authMethodOIDC     = 8
+)
+
+func (s *Server) registerApps(mux *http.ServeMux) {
+	s.register(mux, resource{
+		kind:    Apps,
+		path:    "/api/2/apps",
+		merge:   mergeApp,
+		prepare: prepareApp,
+		deleted: func(s *Server, key string, o object) {
+			rules := s.collection(AppRules)
+			for _, k := range append([]string(nil), rules.keys...) {
+				if strings.HasPrefix(k, key+"/") {
+					rules.remove(k)
+				}
+			}
+			clients := s.collection(AuthServerClients)
+			for _, k := range append([]string(nil), clients.keys...) {
+				if strings.HasSuffix(k, "/"+key) {
+					clients.remove(k)
+				}
+			}
+		},
+	})
+
+	s.register(mux, resource{
+		kind:    AppRules,
+		path:    "/api/2/apps/{app_id}/rules",
+		ordered: true,
+		scope: func(s *Server, r *http.Request) (string, *apiError) {
+			appKey := r.PathValue("app_id")
+			if _, ok := s.collection(Apps).get(appKey); !ok {
+				return "", errNotFound
+			}
+			return appKey + "/", nil
+		},
+		prepare: prepareAppRule,
+		deleted: func(s *Server, key string, o object) {
+			renumber(s.scoped(AppRules, scopeOf(key)))
+		},
+	})
+
+	// Every rule of an app has a position, enabled or not, so a sort lists
+	// them all.
+	mux.HandleFunc("PUT /api/2/apps/{app_id}/rules/sort", func(w http.ResponseWriter, r *http.Request) {
+		appKey := r.PathValue("app_id")
+		if _, ok := s.collection(Apps).get(appKey); !ok {
+			writeError(w, errNotFound)
+			return
+		}
+		sortIDs, e := decodeIDs(r)
+		if e != nil {
+			writeError(w, e)
+			return
+		}
+		rules := s.scoped(AppRules, appKey+"/")
+		if e := sortAll("rules", rules, sortIDs); e != nil {
+			writeError(w, e)
+			return
+		}
+		for _, rule := range rules {
+			s.stamp(rule, false)
+		}
+		writeJSON(w, http.StatusOK, idList(sortIDs))
+	})
+
+	s.registerCatalogue(mux, "/api/2/apps/{app_id}/rules", appRuleCatalogue, func(s *Server, r *http.Request) *apiError {
+		if _, ok := s.collection(Apps).get(r.PathValue("app_id")); !ok {
+			return errNotFound
+		}
+		return nil
+	})
+}
+
+// mergeApp applies an app update. parameters and configuration are merged key
+// by key, as the API does, so a parameter keeps its ID and one left out of the
+// body is not dropped.
+func mergeApp(stored, patch object) {
+	nested := map[string]map[string]interface{}{}
+	for _, k := range []string{"parameters", "configuration"} {
+		if m, ok := stored[k].(map[string]interface{}); ok {
+			nested[k] = m
+		}
+	}
+	merge(stored, patch)
+
+	for k, old := range nested {
+		incoming, ok := patch[k].(map[string]interface{})
+		if !ok {
+			stored[k] = old
+			continue
+		}
+		for name, v := range incoming {
+			if k == "parameters" {
+				if prev, ok := old[name].(map[string]interface{}); ok {
+					if param, ok := v.(map[string]interface{}); ok {
+						param["id"] = prev["id"]
+					}
+				}
+			}
+			old[name] = v
+		}
+		stored[k] = old
+	}
+}
+
+func prepareApp(s *Server, key string, o object) *apiError {
+	if e := required(o, "name"); e != nil {
+		return e
+	}
+	if id, _ := o["connector_id"].(float64); id == 0 {
+		return invalid("connector_id", "connector_id is required")
+	}
+
+	// role_ids are not checked against the roles that exist: the role
+	// attachment fixtures name roles by literal ID.
+	o["role_ids"] = idList(ids(o["role_ids"]))
+
+	if _, ok := o["visible"]; !ok {
+		o["visible"] = true
+	}
+	if _, ok := o["allow_assumed_signin"]; !ok {
+		o["allow_assumed_signin"] = false
+	}
+	if _, ok := o["provisioning"].(map[string]interface{}); !ok {
+		o["provisioning"] = map[string]interface{}{"enabled": false}
+	}
+	if _, ok := o["icon_url"]; !ok {
+		o["icon_url"] = ""
+	}
+
+	params, _ := o["parameters"].(map[string]interface{})
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+	for _, v := range params {
+		if param, ok := v.(map[string]interface{}); ok {
+			if _, ok := param["id"].(float64); !ok {
+				param["id"] = float64(s.nextID())
+			}
+		}
+	}
+	o["parameters"] = params
+
+	config, _ := o["configuration"].(map[string]interface{})
+	if config == nil {
+		config = map[string]interface{}{}
+	}
+	o["configuration"] = config
+
+	if _, ok := o["sso"]; !ok {
+		if e := s.issueSSO(o, config); e != nil {
+			return e
+		}
+	}
+	if id, ok := config["certificate_id"]; ok && id != nil {
+		if _, ok := s.collection(Certificates).get(idKey(id)); !ok {
+			return invalid("certificate_id", "certificate %s does not exist", idKey(id))
+		}
+	}
+	return nil
+}
+
+// signingCertificate is the certificate node of a SAML app's sso: the
+// certificate its configuration names, if it names one, and otherwise the one
+// it was issued.
+func (s *Server) signingCertificate(app object, sso map[string]interface{}) {
+	config, _ := app["configuration"].(map[string]interface{})
+	if config == nil || config["certificate_id"] == nil {
+		return
+	}
+	cert, ok := s.collection(Certificates).get(idKey(config["certificate_id"]))
+	if !ok {
+		return
+	}
+	sso["certificate"] = map[string]interface{}{"id": cert["id"], "name": cert["name"], "value": cert["value"]}
+}
+
+// issueSSO gives a new app the sso block OneLogin generates for it. Which kind
+// of app it is comes from the connector, which the fake does not know, so it
+// goes by the configuration keys each kind takes instead.
+func (s *Server) issueSSO(o, config object) *apiError {
+	id := idKey(o["id"])
+	switch {
+	case config["signature_algorithm"] != nil:
+		cert, e := s.standardCertificate()
+		if e != nil {
+			return e
+		}
+		o["auth_method"] = float64(authMethodSAML)
+		o["sso"] = map[string]interface{}{
+			"metadata_url": fmt.Sprintf("%s/saml/metadata/%s", s.URL, id),
+			"acs_url":      fmt.Sprintf("%s/trust/saml2/http-post/sso/%s", s.URL, id),
+			"sls_url":      fmt.Sprintf("%s/trust/saml2/http-redirect/slo/%s", s.URL, id),
+			"issuer":       fmt.Sprintf("%s/saml/metadata/%s", s.URL, id),
+			"certificate":  cert,
+		}
+	case config["redirect_uri"] != nil || config["oidc_application_type"] != nil:
+		o["auth_method"] = float64(authMethodOIDC)
+		o["sso"] = map[string]interface{}{
+			"client_id":     s.nextStringID(),
+			"client_secret": strings.ReplaceAll(s.nextStringID(), "-", ""),
+		}
+	default:
+		o["auth_method"] = float64(authMethodPassword)
+	}
+	return nil
+}
+
+func prepareAppRule(s *Server, key string, o object) *apiError {
+	if e := required(o, "name"); e != nil {
+		return e
+	}
+	if _, ok := o["match"]; !ok {
+		o["match"] = "all"
+	}
+	if _, ok := o["enabled"]; !ok {
+		o["enabled"] = true
+	}
+	for _, k := range []string{"conditions", "actions"} {
+		if _, ok := o[k].([]interface{}); !ok {
+			o[k] = []interface{}{}
+		}
+	}
+	place(s.scoped(AppRules, scopeOf(key)), o, position(o))
+	return nil
+}
+
+// scoped lists the objects of kind under one parent.
+func (s *Server) scoped(kind Kind, prefix string) []object {
+	c := s.collection(kind)
+	out := []object{}
+	for _, k := range c.keys {
+		if strings.HasPrefix(k, prefix) {
+			out = append(out, c.byKey[k])
+		}
+	}
+	return out
+}
+
+// scopeOf is the parent prefix of a nested key: "12/34" is under "12/".
+func scopeOf(key string) string {
+	if i := strings.LastIndex(key, "/"); i >= 0 {
+		return key[:i+1]
+	}
+	return ""
+}
@@ -0,0 +1,228 @@
+package fakeapi
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Token lifetimes an auth server gets when its configuration does not set
+// them.
+const (
+	defaultAccessTokenMinutes  = 10
+	defaultRefreshTokenMinutes = 20160
+)
+
+// An auth server's scopes, claims and client apps are nested under it, keyed
+// "auth_id/id". A client app has no ID of its own: it is the app it grants,
+// so its key ends in the app's ID.
+
+func (s *Server) registerAuthServers(mux *http.ServeMux) {
+	s.register(mux, resource{
+		kind: AuthServers,
+		path: "/api/2/api_authorizations",
+		prepare: func(s *Server, key string, o object) *apiError {
+			if e := required(o, "name"); e != nil {
+				return e
+			}
+			config, ok := o["configuration"].(map[string]interface{})
+			if !ok {
+				return invalid("configuration", "configuration is required")
+			}
+			if e := required(config, "resource_identifier"); e != nil {
+				return e
+			}
+			if audiences, _ := config["audiences"].([]interface{}); len(audiences) == 0 {
+				return invalid("audiences", "audiences must have at least one entry")
+			}
+			if v, _ := config["access_token_expiration_minutes"].(float64); v == 0 {
+				config["access_token_expiration_minutes"] = float64(defaultAccessTokenMinutes)
+			}
+			if v, _ := config["refresh_token_expiration_minutes"].(float64); v == 0 {
+				config["refresh_token_expiration_minutes"] = float64(defaultRefreshTokenMinutes)
+			}
+			return nil
+		},
+		deleted: func(s *Server, key string, o object) {
+			for _, kind := range []Kind{AuthServerScopes, AuthServerClaims, AuthServerClients} {
+				c := s.collection(kind)
+				for _, k := range append([]string(nil), c.keys...) {
+					if strings.HasPrefix(k, key+"/") {
+						c.remove(k)
+					}
+				}
+			}
+		},
+	})
+
+	s.register(mux, resource{
+		kind:  AuthServerScopes,
+		path:  "/api/2/api_authorizations/{auth_id}/scopes",
+		scope: authServerScope,
+		prepare: func(s *Server, key string, o object) *apiError {
+			if e := required(o, "value"); e != nil {
+				return e
+			}
+			for _, other := range s.scoped(AuthServerScopes, scopeOf(key)) {
+				if idKey(other["id"]) != idKey(o["id"]) && strings.EqualFold(other["value"].(string), o["value"].(string)) {
+					return invalid("value", "value must be unique")
+				}
+			}
+			return nil
+		},
+		deleted: func(s *Server, key string, o object) {
+			for _, client := range s.scoped(AuthServerClients, scopeOf(key)) {
+				client["scopes"] = idList(withoutID(ids(client["scopes"]), o["id"].(float64)))
+			}
+		},
+	})
+
+	s.register(mux, resource{
+		kind:  AuthServerClaims,
+		path:  "/api/2/api_authorizations/{auth_id}/claims",
+		scope: authServerScope,
+		// A claim is given a name and shown with it as its label.
+		prepare: func(s *Server, key string, o object) *apiError {
+			if name, ok := o["name"]; ok {
+				o["label"] = name
+				delete(o, "name")
+			}
+			if e := required(o, "label"); e != nil {
+				return invalid("name", "name is required")
+			}
+			for k, v := range map[string]interface{}{
+				"user_attribute_mappings":   nil,
+				"user_attribute_macros":     nil,
+				"attribute_transformations": nil,
+				"skip_if_blank":             false,
+				"values":                    []interface{}{},
+				"default_values":            "",
+				"provisioned_entitlements":  false,
+			} {
+				if _, ok := o[k]; !ok {
+					o[k] = v
+				}
+			}
+			return nil
+		},
+	})
+
+	clients := "/api/2/api_authorizations/{auth_id}/clients"
+	mux.HandleFunc("GET "+clients, func(w http.ResponseWriter, r *http.Request) {
+		prefix, e := authServerScope(s, r)
+		if e != nil {
+			writeError(w, e)
+			return
+		}
+		items := []object{}
+		for _, client := range s.scoped(AuthServerClients, prefix) {
+			items = append(items, s.renderClientApp(prefix, client))
+		}
+		writeList(w, r, items)
+	})
+	mux.HandleFunc("POST "+clients, func(w http.ResponseWriter, r *http.Request) {
+		prefix, e := authServerScope(s, r)
+		if e != nil {
+			writeError(w, e)
+			return
+		}
+		o, e := decodeObject(r)
+		if e != nil {
+			writeError(w, e)
+			return
+		}
+		app, ok := s.collection(Apps).get(idKey(o["app_id"]))
+		if !ok {
+			writeError(w, invalid("app_id", "app %s does not exist", idKey(o["app_id"])))
+			return
+		}
+		key := prefix + idKey(app["id"])
+		if _, ok := s.collection(AuthServerClients).get(key); ok {
+			writeError(w, invalid("app_id", "app %s is already a client of this authorization server", idKey(app["id"])))
+			return
+		}
+		server, _ := s.collection(AuthServers).get(r.PathValue("auth_id"))
+		client := object{"app_id": app["id"], "api_auth_id": server["id"], "scopes": o["scopes"]}
+		if e := s.clientAppScopesExist(prefix, client); e != nil {
+			writeError(w, e)
+			return
+		}
+		s.collection(AuthServerClients).put(key, client)
+		writeJSON(w, http.StatusCreated, object{"app_id": client["app_id"], "api_auth_id": client["api_auth_id"]})
+	})
+	mux.HandleFunc("PUT "+clients+"/{id}", func(w http.ResponseWriter, r *http.Request) {
+		prefix, e := authServerScope(s, r)
+		if e != nil {
+			writeError(w, e)
+			return
+		}
+		client, ok := s.collection(AuthServerClients).get(prefix + r.PathValue("id"))
+		if !ok {
+			writeError(w, errNotFound)
+			return
+		}
+		o, e := decodeObject(r)
+		if e != nil {
+			writeError(w, e)
+			return
+		}
+		updated := clone(client)
+		updated["scopes"] = o["scopes"]
+		if e := s.clientAppScopesExist(prefix, updated); e != nil {
+			writeError(w, e)
+			return
+		}
+		s.collection(AuthServerClients).put(prefix+r.PathValue("id"), updated)
+		writeJSON(w, http.StatusOK, object{"app_id": updated["app_id"], "api_auth_id": updated["api_auth_id"]})
+	})
+	mux.HandleFunc("DELETE "+clients+"/{id}", func(w http.ResponseWriter, r *http.Request) {
+		prefix, e := authServerScope(s, r)
+		if e != nil {
+			writeError(w, e)
+			return
+		}
+		if !s.collection(AuthServerClients).remove(prefix + r.PathValue("id")) {
+			writeError(w, errNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// authServerScope reads the auth server a scope, claim or client app is nested
+// under from the path.
+func authServerScope(s *Server, r *http.Request) (string, *apiError) {
+	authKey := r.PathValue("auth_id")
+	if _, ok := s.collection(AuthServers).get(authKey); !ok {
+		return "", errNotFound
+	}
+	return authKey + "/", nil
+}
+
+func (s *Server) clientAppScopesExist(prefix string, client object) *apiError {
+	for _, id := range ids(client["scopes"]) {
+		if _, ok := s.collection(AuthServerScopes).get(prefix + idKey(id)); !ok {
+			return invalid("scopes", "scope %s does not belong to this authorization server", idKey(id))
+		}
+	}
+	return nil
+}
+
+// renderClientApp is how the API lists a client app: with the app's name, and
+// its scopes in full rather than by ID.
+func (s *Server) renderClientApp(prefix string, client object) object {
+	out := clone(client)
+	if app, ok := s.collection(Apps).get(idKey(client["app_id"])); ok {
+		out["name"] = app["name"]
+	}
+	scopeIDs := ids(client["scopes"])
+	sort.Float64s(scopeIDs)
+	scopes := []interface{}{}
+	for _, id := range scopeIDs {
+		if scope, ok := s.collection(AuthServerScopes).get(prefix + idKey(id)); ok {
+			scopes = append(scopes, object{"id": scope["id"], "value": scope["value"], "description": scope["description"]})
+		}
+	}
+	out["scopes"] = scopes
+	return out
+}
@@ -0,0 +1,153 @@
+package fakeapi
+
+import "net/http"
+
+// A catalogue is what the rule and mapping endpoints list as allowed in
+// conditions and actions: the sources a condition can check, the operators
+// each source takes, and the actions with their values. OneLogin's app rule
+// catalogue differs from one connector to the next; the fake has one for every
+// app and one for mappings, with what the fixtures use in them.
+type catalogue struct {
+	conditions []option
+	operators  map[string][]option
+	actions    []option
+	// conditionValues and actionValues list the values a condition or action
+	// takes, for those limited to a set. The rest take whatever they are given
+	// and list none.
+	conditionValues map[string]func(s *Server) []option
+	actionValues    map[string]func(s *Server) []option
+}
+
+// option is one entry in a catalogue list.
+type option struct {
+	name  string
+	value interface{}
+}
+
+var appRuleCatalogue = catalogue{
+	conditions: []option{
+		{"Last Login", "last_login"},
+		{"Has Role", "has_role"},
+		{"Email", "email"},
+		{"MemberOf", "member_of"},
+	},
+	operators: map[string][]option{
+		"last_login": {{"is more than (days ago)", ">"}, {"is less than (days ago)", "<"}},
+		"has_role":   {{"includes", "ri"}, {"does not include", "!ri"}},
+		"email":      {{"is", "="}, {"is not", "!="}, {"contains", "~"}, {"does not contain", "!~"}},
+		"member_of":  {{"contains", "~"}, {"does not contain", "!~"}},
+	},
+	conditionValues: map[string]func(s *Server) []option{
+		"has_role": roleOptions,
+	},
+	actions: []option{
+		{"Set Amazon Username", "set_amazonusername"},
+		{"Set Role", "set_role"},
+	},
+	actionValues: map[string]func(s *Server) []option{
+		"set_amazonusername": func(*Server) []option {
+			return []option{{"Email", "email"}, {"Username", "username"}, {"MemberOf", "member_of"}}
+		},
+		"set_role": roleOptions,
+	},
+}
+
+var mappingCatalogue = catalogue{
+	conditions: []option{
+		{"Email", "email"},
+		{"Department", "department"},
+		{"Title", "title"},
+		{"Last Login", "last_login"},
+		{"Has Role", "has_role"},
+	},
+	operators: map[string][]option{
+		"email":      {{"is", "="}, {"is not", "!="}, {"contains", "~"}, {"does not contain", "!~"}},
+		"department": {{"is", "="}, {"is not", "!="}, {"contains", "~"}, {"does not contain", "!~"}},
+		"title":      {{"is", "="}, {"is not", "!="}, {"contains", "~"}, {"does not contain", "!~"}},
+		"last_login": {{"is more than (days ago)", ">"}, {"is less than (days ago)", "<"}},
+		"has_role":   {{"includes", "ri"}, {"does not include", "!ri"}},
+	},
+	conditionValues: map[string]func(s *Server) []option{
+		"has_role": roleOptions,
+	},
+	actions: []option{
+		{"Add Role", "add_role"},
+		{"Set Role", "set_role"},
+		{"Set Status", "set_status"},
+		{"Set User Principal Name", "set_userprincipalname"},
+	},
+	actionValues: map[string]func(s *Server) []option{
+		"add_role": roleOptions,
+		"set_role": roleOptions,
+		"set_status": func(*Server) []option {
+			return []option{{"Unactivated", "0"}, {"Active", "1"}, {"Suspended", "2"}, {"Locked", "3"}}
+		},
+	},
+}
+
+// roleOptions lists the tenant's roles, by ID.
+func roleOptions(s *Server) []option {
+	out := []option{}
+	for _, role := range s.collection(Roles).all() {
+		out = append(out, option{role["name"].(string), role["id"]})
+	}
+	return out
+}
+
+// registerCatalogue serves cat under base: base/conditions,
+// base/conditions/{value}/operators and /values, base/actions and
+// base/actions/{value}/values. check, if set, is run first and fails the
+// request if it returns an error.
+func (s *Server) registerCatalogue(mux *http.ServeMux, base string, cat catalogue, check func(s *Server, r *http.Request) *apiError) {
+	serve := func(path string, list func(r *http.Request) ([]option, bool)) {
+		mux.HandleFunc("GET "+base+path, func(w http.ResponseWriter, r *http.Request) {
+			if check != nil {
+				if e := check(s, r); e != nil {
+					writeError(w, e)
+					return
+				}
+			}
+			options, ok := list(r)
+			if !ok {
+				writeError(w, errNotFound)
+				return
+			}
+			out := make([]interface{}, len(options))
+			for i, o := range options {
+				out[i] = map[string]interface{}{"name": o.name, "value": o.value}
+			}
+			writeJSON(w, http.StatusOK, out)
+		})
+	}
+	values := func(known []option, lists map[string]func(s *Server) []option, value string) ([]option, bool) {
+		if !hasOption(known, value) {
+			return nil, false
+		}
+		if list, ok := lists[value]; ok {
+			return list(s), true
+		}
+		return []option{}, true
+	}
+
+	serve("/conditions", func(*http.Request) ([]option, bool) { return cat.conditions, true })
+	serve("/conditions/{value}/operators", func(r *http.Request) ([]option, bool) {
+		operators, ok := cat.operators[r.PathValue("value")]
+		return operators, ok
+	})
+	serve("/conditions/{value}/values", func(r *http.Request) ([]option, bool) {
+		return values(cat.conditions, cat.conditionValues, r.PathValue("value"))
+	})
+	serve("/actions", func(*http.Request) ([]option, bool) { return cat.actions, true })
+	serve("/actions/{value}/values", func(r *http.Request) ([]option, bool) {
+		return values(cat.actions, cat.actionValues, r.PathValue("value"))
+	})
+}
+
+func hasOption(options []option, value string) bool {
+	for _, o := range options {
+		if o.value == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,194 @@
+package fakeapi
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+)
+
+// Certificates are the signing certificates a SAML app can use in place of the
+// account's standard one. POST /api/2/certificates/generate makes one with a
+// new key; POST /api/2/certificates uploads one with its private key, which is
+// never returned. Only the name can change afterwards. A SAML app signs with
+// the certificate its configuration's certificate_id names, and a certificate
+// an app signs with cannot be deleted.
+
+// certificateKeySizes are the RSA key sizes a generated certificate can have.
+var certificateKeySizes = map[int]bool{2048: true, 3072: true, 4096: true}
+
+func (s *Server) registerCertificates(mux *http.ServeMux) {
+	res := resource{
+		kind: Certificates,
+		path: "/api/2/certificates",
+		merge: func(stored, patch object) {
+			if name, ok := patch["name"]; ok {
+				stored["name"] = name
+			}
+		},
+		prepare:   prepareCertificate,
+		deletable: certificateDeletable,
+	}
+	s.register(mux, res)
+
+	mux.HandleFunc("POST /api/2/certificates/generate", func(w http.ResponseWriter, r *http.Request) {
+		o, e := decodeObject(r)
+		if e != nil {
+			writeError(w, e)
+			return
+		}
+		if e := required(o, "name"); e != nil {
+			writeError(w, e)
+			return
+		}
+		keySize := 2048
+		if v, ok := o["key_size"].(float64); ok {
+			keySize = int(v)
+		}
+		if !certificateKeySizes[keySize] {
+			writeError(w, invalid("key_size", "key_size must be one of 2048, 3072, 4096"))
+			return
+		}
+		years := 3
+		if v, ok := o["validity_years"].(float64); ok {
+			years = int(v)
+		}
+		if years < 1 || years > 20 {
+			writeError(w, invalid("validity_years", "validity_years must be between 1 and 20"))
+			return
+		}
+
+		id := s.nextID()
+		key, err := rsa.GenerateKey(rand.Reader, keySize)
+		if err != nil {
+			writeError(w, badRequest("generating key: %v", err))
+			return
+		}
+		notBefore := s.now().UTC().Truncate(time.Second)
+		cert, err := selfSigned(key, o["name"].(string), int64(id), notBefore, notBefore.AddDate(years, 0, 0))
+		if err != nil {
+			writeError(w, badRequest("generating certificate: %v", err))
+			return
+		}
+
+		stored := object{"id": float64(id), "name": o["name"], "value": cert, "key_size": float64(keySize)}
+		s.stamp(stored, true)
+		if e := prepareCertificate(s, idKey(stored["id"]), stored); e != nil {
+			writeError(w, e)
+			return
+		}
+		s.collection(Certificates).put(idKey(stored["id"]), stored)
+		res.write(w, http.StatusCreated, s.render(Certificates, stored))
+	})
+}
+
+// prepareCertificate checks the certificate is one, and for an upload that the
+// private key sent with it is its own.
+func prepareCertificate(s *Server, key string, o object) *apiError {
+	if e := required(o, "name"); e != nil {
+		return e
+	}
+	value, _ := o["value"].(string)
+	block, _ := pem.Decode([]byte(value))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return invalid("value", "value must be a PEM encoded certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return invalid("value", "value is not a valid certificate: %v", err)
+	}
+
+	if pk, ok := o["private_key"].(string); ok {
+		delete(o, "private_key")
+		block, _ := pem.Decode([]byte(pk))
+		if block == nil {
+			return invalid("private_key", "private_key must be PEM encoded")
+		}
+		signer, err := parsePrivateKey(block.Bytes)
+		if err != nil {
+			return invalid("private_key", "private_key is not a valid key: %v", err)
+		}
+		if !signer.Public().(interface{ Equal(crypto.PublicKey) bool }).Equal(cert.PublicKey) {
+			return invalid("private_key", "private_key does not match the certificate")
+		}
+	} else if _, ok := o["key_size"]; !ok {
+		// Stored without a key, it could not sign anything.
+		return invalid("private_key", "private_key is required")
+	}
+	if rsaKey, ok := cert.PublicKey.(*rsa.PublicKey); ok {
+		o["key_size"] = float64(rsaKey.N.BitLen())
+	}
+	return nil
+}
+
+// certificateDeletable refuses to delete a certificate an app signs with.
+func certificateDeletable(s *Server, key string, o object) *apiError {
+	for _, app := range s.collection(Apps).all() {
+		config, _ := app["configuration"].(map[string]interface{})
+		if config != nil && idKey(config["certificate_id"]) == key {
+			return &apiError{
+				status:  http.StatusConflict,
+				name:    "ConflictError",
+				message: fmt.Sprintf("Certificate is in use by app %s", idKey(app["id"])),
+			}
+		}
+	}
+	return nil
+}
+
+func parsePrivateKey(der []byte) (crypto.Signer, error) {
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+	return signer, nil
+}
+
+// selfSigned makes a PEM encoded self-signed certificate for key.
+func selfSigned(key *rsa.PrivateKey, name string, serial int64, notBefore, notAfter time.Time) (string, error) {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name, Organization: []string{"OneLogin"}},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})), nil
+}
+
+// standardCertificate is the sso certificate node of an app signing with the
+// account's standard certificate. It is made the first time it is needed, so
+// a server that never has a SAML app never generates a key.
+func (s *Server) standardCertificate() (object, *apiError) {
+	if s.standardCert == nil {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, badRequest("generating key: %v", err)
+		}
+		id := s.nextID()
+		notBefore := s.now().UTC().Truncate(time.Second)
+		cert, err := selfSigned(key, "Standard OneLogin Certificate", int64(id), notBefore, notBefore.AddDate(5, 0, 0))
+		if err != nil {
+			return nil, badRequest("generating certificate: %v", err)
+		}
+		s.standardCert = object{"id": float64(id), "name": "Standard OneLogin Certificate", "value": cert}
+	}
+	return clone(s.standardCert), nil
+}
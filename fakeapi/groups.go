@@ -0,0 +1,51 @@
+package fakeapi
+
+import "net/http"
+
+func (s *Server) registerGroups(mux *http.ServeMux) {
+	s.register(mux, resource{
+		kind: Groups,
+		path: "/api/2/groups",
+		prepare: func(s *Server, key string, o object) *apiError {
+			if e := required(o, "name"); e != nil {
+				return e
+			}
+			// Accepted in a body, then neither stored nor returned.
+			o["reference"] = nil
+			return s.unique(Groups, key, o, "name")
+		},
+	})
+
+	// The groups data sources still read the v1 API, which wraps every
+	// response in a status and returns even a single group as a list.
+	mux.HandleFunc("GET /api/1/groups", func(w http.ResponseWriter, r *http.Request) {
+		groups := []interface{}{}
+		for _, g := range s.collection(Groups).all() {
+			groups = append(groups, s.render(Groups, g))
+		}
+		writeV1(w, groups)
+	})
+	mux.HandleFunc("GET /api/1/groups/{id}", func(w http.ResponseWriter, r *http.Request) {
+		g, ok := s.collection(Groups).get(r.PathValue("id"))
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]interface{}{
+				"status": map[string]interface{}{"error": true, "code": 404, "type": "not found", "message": "Group not found"},
+			})
+			return
+		}
+		writeV1(w, []interface{}{s.render(Groups, g)})
+	})
+}
+
+func writeV1(w http.ResponseWriter, data []interface{}) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status": map[string]interface{}{"error": false, "code": 200, "type": "success", "message": "Success"},
+		"pagination": map[string]interface{}{
+			"before_cursor": nil,
+			"after_cursor":  nil,
+			"previous_link": nil,
+			"next_link":     nil,
+		},
+		"data": data,
+	})
+}
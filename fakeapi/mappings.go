@@ -0,0 +1,269 @@
+package fakeapi
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Only enabled mappings are ordered. A disabled one has no position, and
+// enabling it puts it last unless the update says where. PUT
+// /api/2/mappings/sort reorders all of the enabled mappings at once.
+//
+// The catalogue of what conditions and actions can use is served under
+// /api/2/mappings/conditions and /api/2/mappings/actions.
+//
+// POST /api/2/mappings/{id}/dryrun runs a mapping, enabled or not, against the
+// users whose IDs are posted, and reports what its actions would change
+// without changing anything.
+
+func (s *Server) registerMappings(mux *http.ServeMux) {
+	s.register(mux, resource{
+		kind:    Mappings,
+		path:    "/api/2/mappings",
+		ordered: true,
+		// The list leaves disabled mappings out unless asked for them.
+		defaultQuery: map[string]string{"enabled": "true"},
+		prepare:      prepareMapping,
+		deleted: func(s *Server, key string, o object) {
+			renumber(s.enabledMappings())
+		},
+	})
+
+	mux.HandleFunc("PUT /api/2/mappings/sort", func(w http.ResponseWriter, r *http.Request) {
+		sortIDs, e := decodeIDs(r)
+		if e != nil {
+			writeError(w, e)
+			return
+		}
+		mappings := s.enabledMappings()
+		if e := sortAll("mappings", mappings, sortIDs); e != nil {
+			writeError(w, e)
+			return
+		}
+		for _, m := range mappings {
+			s.stamp(m, false)
+		}
+		writeJSON(w, http.StatusOK, idList(sortIDs))
+	})
+
+	s.registerCatalogue(mux, "/api/2/mappings", mappingCatalogue, nil)
+
+	mux.HandleFunc("POST /api/2/mappings/{id}/dryrun", func(w http.ResponseWriter, r *http.Request) {
+		mapping, ok := s.collection(Mappings).get(r.PathValue("id"))
+		if !ok {
+			writeError(w, errNotFound)
+			return
+		}
+		userIDs, e := decodeIDs(r)
+		if e != nil {
+			writeError(w, e)
+			return
+		}
+		out := []interface{}{}
+		for _, id := range userIDs {
+			user, ok := s.collection(Users).get(idKey(id))
+			if !ok {
+				writeError(w, invalid("user_ids", "user %s does not exist", idKey(id)))
+				return
+			}
+			out = append(out, s.dryRun(mapping, s.render(Users, user)))
+		}
+		writeJSON(w, http.StatusOK, out)
+	})
+}
+
+// dryRun is what mapping would do to user: whether its conditions match and,
+// if they do, each action's value before and after.
+func (s *Server) dryRun(mapping, user object) map[string]interface{} {
+	result := map[string]interface{}{
+		"user": map[string]interface{}{
+			"id": user["id"], "firstname": user["firstname"], "lastname": user["lastname"], "email": user["email"],
+		},
+		"mapped":  s.mappingMatches(mapping, user),
+		"changes": []interface{}{},
+	}
+	if !result["mapped"].(bool) {
+		return result
+	}
+	changes := []interface{}{}
+	for _, a := range asObjects(mapping["actions"]) {
+		action, _ := a["action"].(string)
+		values := toStrings(a["value"])
+		before, after := actionEffect(action, values, user)
+		changes = append(changes, map[string]interface{}{
+			"action": action, "value": stringList(values), "before": stringList(before), "after": stringList(after),
+		})
+	}
+	result["changes"] = changes
+	return result
+}
+
+func (s *Server) mappingMatches(mapping, user object) bool {
+	conditions := asObjects(mapping["conditions"])
+	any := mapping["match"] == "any"
+	for _, c := range conditions {
+		if conditionMatches(c, user, s.now()) == any {
+			return any
+		}
+	}
+	return !any || len(conditions) == 0
+}
+
+// conditionMatches evaluates one condition from the mapping catalogue.
+func conditionMatches(c, user object, now time.Time) bool {
+	source, _ := c["source"].(string)
+	operator, _ := c["operator"].(string)
+	value, _ := c["value"].(string)
+	switch source {
+	case "has_role":
+		has := containsID(ids(user["role_ids"]), parseFloat(value))
+		return has == (operator == "ri")
+	case "last_login":
+		// Never having logged in is longer ago than any number of days.
+		days := float64(1 << 30)
+		if at, err := time.Parse(time.RFC3339, fmt.Sprint(user["last_login"])); err == nil {
+			days = now.Sub(at).Hours() / 24
+		}
+		if operator == ">" {
+			return days > parseFloat(value)
+		}
+		return days < parseFloat(value)
+	}
+	field, _ := user[source].(string)
+	switch operator {
+	case "=":
+		return field == value
+	case "!=":
+		return field != value
+	case "~":
+		return strings.Contains(field, value)
+	case "!~":
+		return !strings.Contains(field, value)
+	}
+	return false
+}
+
+var macro = regexp.MustCompile(`\$\{user\.(\w+)\}`)
+
+// actionEffect is a user's value for action before and after it is applied
+// with values.
+func actionEffect(action string, values []string, user object) (before, after []string) {
+	switch action {
+	case "add_role", "set_role":
+		for _, id := range ids(user["role_ids"]) {
+			before = append(before, idKey(id))
+		}
+		if action == "set_role" {
+			return before, values
+		}
+		after = append(after, before...)
+		for _, v := range values {
+			if !containsString(after, v) {
+				after = append(after, v)
+			}
+		}
+		return before, after
+	case "set_status":
+		return []string{idKey(user["status"])}, values
+	}
+	field := strings.TrimPrefix(action, "set_")
+	if v, ok := user[field]; ok && v != nil {
+		before = []string{fmt.Sprint(v)}
+	}
+	for _, v := range values {
+		after = append(after, macro.ReplaceAllStringFunc(v, func(m string) string {
+			if v, ok := user[macro.FindStringSubmatch(m)[1]]; ok && v != nil {
+				return fmt.Sprint(v)
+			}
+			return ""
+		}))
+	}
+	return before, after
+}
+
+func prepareMapping(s *Server, key string, o object) *apiError {
+	if e := required(o, "name"); e != nil {
+		return e
+	}
+	if _, ok := o["match"]; !ok {
+		o["match"] = "all"
+	}
+	for _, k := range []string{"conditions", "actions"} {
+		if _, ok := o[k].([]interface{}); !ok {
+			o[k] = []interface{}{}
+		}
+	}
+
+	enabled, _ := o["enabled"].(bool)
+	o["enabled"] = enabled
+	if !enabled {
+		o["position"] = nil
+		others := []object{}
+		for _, m := range s.enabledMappings() {
+			if idKey(m["id"]) != idKey(o["id"]) {
+				others = append(others, m)
+			}
+		}
+		renumber(others)
+		return nil
+	}
+	place(s.enabledMappings(), o, position(o))
+	return nil
+}
+
+func (s *Server) enabledMappings() []object {
+	out := []object{}
+	for _, m := range s.collection(Mappings).all() {
+		if enabled, _ := m["enabled"].(bool); enabled {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+func asObjects(v interface{}) []object {
+	var out []object
+	list, _ := v.([]interface{})
+	for _, item := range list {
+		if o, ok := item.(map[string]interface{}); ok {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+func toStrings(v interface{}) []string {
+	var out []string
+	list, _ := v.([]interface{})
+	for _, item := range list {
+		out = append(out, idKey(item))
+	}
+	return out
+}
+
+// stringList is a list to write out: an empty one rather than null.
+func stringList(in []string) []interface{} {
+	out := make([]interface{}, len(in))
+	for i, v := range in {
+		out[i] = v
+	}
+	return out
+}
+
+func containsString(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+func parseFloat(v string) float64 {
+	f, _ := strconv.ParseFloat(v, 64)
+	return f
+}
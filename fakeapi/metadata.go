@@ -0,0 +1,72 @@
+package fakeapi
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/pem"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+)
+
+// A SAML app's IdP metadata is served at the metadata_url in its sso, outside
+// /api/ and without a token, as OneLogin serves it: service providers fetch it
+// themselves. It carries the app's issuer, its single sign-on and logout
+// endpoints and the certificate it signs with.
+
+// samlMetadataTemplate is the document, shaped like OneLogin's, given the
+// entity ID, the logout endpoint, the sign-on endpoint for HTTP-Redirect and
+// for HTTP-POST, and the certificate's base64 DER.
+const samlMetadataTemplate = `<?xml version="1.0"?>
+<EntityDescriptor xmlns="urn:oasis:names:tc:SAML:2.0:metadata" entityID="%[1]s">
+  <IDPSSODescriptor xmlns:ds="http://www.w3.org/2000/09/xmldsig#" protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+    <KeyDescriptor use="signing">
+      <ds:KeyInfo>
+        <ds:X509Data>
+          <ds:X509Certificate>%[5]s</ds:X509Certificate>
+        </ds:X509Data>
+      </ds:KeyInfo>
+    </KeyDescriptor>
+    <SingleLogoutService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="%[2]s"/>
+    <NameIDFormat>urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress</NameIDFormat>
+    <SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="%[3]s"/>
+    <SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="%[4]s"/>
+  </IDPSSODescriptor>
+</EntityDescriptor>
+`
+
+func (s *Server) samlMetadata(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	app, ok := s.collection(Apps).get(r.PathValue("id"))
+	sso, _ := app["sso"].(map[string]interface{})
+	if !ok || sso["metadata_url"] == nil {
+		http.NotFound(w, r)
+		return
+	}
+	sso = s.render(Apps, app)["sso"].(map[string]interface{})
+	cert, _ := sso["certificate"].(map[string]interface{})
+	value, _ := cert["value"].(string)
+	// Metadata carries the certificate as bare base64.
+	if block, _ := pem.Decode([]byte(value)); block != nil {
+		value = base64.StdEncoding.EncodeToString(block.Bytes)
+	}
+
+	id := idKey(app["id"])
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, samlMetadataTemplate,
+		escapeXML(sso["issuer"]),
+		escapeXML(sso["sls_url"]),
+		escapeXML(fmt.Sprintf("%s/trust/saml2/http-redirect/sso/%s", s.URL, id)),
+		escapeXML(sso["acs_url"]),
+		escapeXML(value),
+	)
+}
+
+func escapeXML(v interface{}) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(fmt.Sprint(v)))
+	return buf.String()
+}
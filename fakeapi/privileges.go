@@ -0,0 +1,32 @@
+package fakeapi
+
+import "net/http"
+
+func (s *Server) registerPrivileges(mux *http.ServeMux) {
+	s.register(mux, resource{
+		kind: Privileges,
+		path: "/api/2/privileges",
+		prepare: func(s *Server, key string, o object) *apiError {
+			if e := required(o, "name"); e != nil {
+				return e
+			}
+			doc, ok := o["privilege"].(map[string]interface{})
+			if !ok {
+				return invalid("privilege", "privilege is required")
+			}
+			statements, _ := doc["Statement"].([]interface{})
+			if len(statements) == 0 {
+				return invalid("privilege", "privilege must have at least one Statement")
+			}
+			if _, ok := doc["Version"]; !ok {
+				doc["Version"] = "2018-05-18"
+			}
+			for _, k := range []string{"user_ids", "role_ids"} {
+				if _, ok := o[k]; ok {
+					o[k] = idList(ids(o[k]))
+				}
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,263 @@
+package fakeapi
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// resource describes one collection endpoint -- POST and GET on path, GET,
+// PUT and DELETE on path/{id} -- and what is particular about it. Most of
+// OneLogin's v2 endpoints behave alike, so each kind only has to say where it
+// differs.
+type resource struct {
+	kind Kind
+	path string
+
+	// envelope, if set, is the key the API nests request and response objects
+	// under, as in {"self_registration_profile": {...}}.
+	envelope string
+	// requestEnvelope is the same for requests only.
+	requestEnvelope string
+
+	// ordered lists objects by position rather than in creation order.
+	ordered bool
+	// defaultQuery holds list filters the API applies when the request does
+	// not give its own.
+	defaultQuery map[string]string
+
+	// scope, if set, reads the parent an object is nested under from the path
+	// (an app, for its rules) and returns the prefix its keys carry.
+	scope func(s *Server, r *http.Request) (string, *apiError)
+
+	// merge applies an update to a copy of the stored object. Defaults to
+	// replacing the keys the body carries.
+	merge func(stored, patch object)
+	// prepare validates an object about to be stored, new or updated, and
+	// fills in whatever the API would. Nothing is stored if it fails.
+	prepare func(s *Server, key string, o object) *apiError
+	// deletable, if set, refuses to delete an object that is still in use.
+	deletable func(s *Server, key string, o object) *apiError
+	// deleted tidies up references to an object that has gone.
+	deleted func(s *Server, key string, o object)
+}
+
+func (s *Server) register(mux *http.ServeMux, res resource) {
+	mux.HandleFunc("POST "+res.path, func(w http.ResponseWriter, r *http.Request) {
+		prefix, e := res.scopeOf(s, r)
+		if e != nil {
+			writeError(w, e)
+			return
+		}
+		o, e := res.decode(r)
+		if e != nil {
+			writeError(w, e)
+			return
+		}
+
+		delete(o, "id")
+		var key string
+		if stringIDKinds[res.kind] {
+			key = s.nextStringID()
+			o["id"] = key
+		} else {
+			id := s.nextID()
+			o["id"] = float64(id)
+			key = idKey(o["id"])
+		}
+		key = prefix + key
+		s.stamp(o, true)
+
+		if res.prepare != nil {
+			if e := res.prepare(s, key, o); e != nil {
+				writeError(w, e)
+				return
+			}
+		}
+		s.collection(res.kind).put(key, o)
+		res.write(w, http.StatusCreated, s.render(res.kind, o))
+	})
+
+	mux.HandleFunc("GET "+res.path, func(w http.ResponseWriter, r *http.Request) {
+		prefix, e := res.scopeOf(s, r)
+		if e != nil {
+			writeError(w, e)
+			return
+		}
+		items := []object{}
+		for _, o := range s.scoped(res.kind, prefix) {
+			items = append(items, s.render(res.kind, o))
+		}
+		if res.ordered {
+			sort.SliceStable(items, func(i, j int) bool { return before(items[i], items[j]) })
+		}
+
+		q := r.URL.Query()
+		for k, v := range res.defaultQuery {
+			if !q.Has(k) {
+				q.Set(k, v)
+			}
+		}
+		r.URL.RawQuery = q.Encode()
+		writeList(w, r, items)
+	})
+
+	item := res.path + "/{id}"
+
+	mux.HandleFunc("GET "+item, func(w http.ResponseWriter, r *http.Request) {
+		_, o, e := res.find(s, r)
+		if e != nil {
+			writeError(w, e)
+			return
+		}
+		res.write(w, http.StatusOK, s.render(res.kind, o))
+	})
+
+	mux.HandleFunc("PUT "+item, func(w http.ResponseWriter, r *http.Request) {
+		key, stored, e := res.find(s, r)
+		if e != nil {
+			writeError(w, e)
+			return
+		}
+		patch, e := res.decode(r)
+		if e != nil {
+			writeError(w, e)
+			return
+		}
+		delete(patch, "id")
+		delete(patch, "created_at")
+
+		o := clone(stored)
+		if res.merge != nil {
+			res.merge(o, patch)
+		} else {
+			merge(o, patch)
+		}
+		s.stamp(o, false)
+
+		if res.prepare != nil {
+			if e := res.prepare(s, key, o); e != nil {
+				writeError(w, e)
+				return
+			}
+		}
+		s.collection(res.kind).put(key, o)
+		res.write(w, http.StatusOK, s.render(res.kind, o))
+	})
+
+	mux.HandleFunc("DELETE "+item, func(w http.ResponseWriter, r *http.Request) {
+		key, o, e := res.find(s, r)
+		if e != nil {
+			writeError(w, e)
+			return
+		}
+		if res.deletable != nil {
+			if e := res.deletable(s, key, o); e != nil {
+				writeError(w, e)
+				return
+			}
+		}
+		s.collection(res.kind).remove(key)
+		if res.deleted != nil {
+			res.deleted(s, key, o)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func (res resource) scopeOf(s *Server, r *http.Request) (string, *apiError) {
+	if res.scope == nil {
+		return "", nil
+	}
+	return res.scope(s, r)
+}
+
+func (res resource) find(s *Server, r *http.Request) (string, object, *apiError) {
+	prefix, e := res.scopeOf(s, r)
+	if e != nil {
+		return "", nil, e
+	}
+	key := prefix + r.PathValue("id")
+	o, ok := s.collection(res.kind).get(key)
+	if !ok {
+		return "", nil, errNotFound
+	}
+	return key, o, nil
+}
+
+func (res resource) decode(r *http.Request) (object, *apiError) {
+	o, e := decodeObject(r)
+	if e != nil {
+		return nil, e
+	}
+	for _, env := range []string{res.envelope, res.requestEnvelope} {
+		if env == "" {
+			continue
+		}
+		if inner, ok := o[env].(map[string]interface{}); ok {
+			return inner, nil
+		}
+	}
+	return o, nil
+}
+
+func (res resource) write(w http.ResponseWriter, status int, o object) {
+	if res.envelope != "" {
+		writeJSON(w, status, map[string]interface{}{res.envelope: o})
+		return
+	}
+	writeJSON(w, status, o)
+}
+
+// render is how the API shows a stored object: a copy, with whatever is
+// derived from other objects filled in and whatever the API never returns
+// taken out.
+func (s *Server) render(kind Kind, o object) object {
+	out := clone(o)
+	switch kind {
+	case Users:
+		out["role_ids"] = idList(s.rolesOfUser(o["id"].(float64)))
+	case Apps:
+		if sso, ok := out["sso"].(map[string]interface{}); ok && sso["certificate"] != nil {
+			s.signingCertificate(out, sso)
+		}
+	case Roles:
+		// Membership lives on the sub-endpoints, not the role.
+		delete(out, "users")
+		delete(out, "admins")
+		delete(out, "apps")
+	case SmartHooks:
+		out["env_vars"] = s.hookEnvVars(o["env_vars"])
+	case SmartHookEnvVars:
+		// Values are write-only: the API never gives one back.
+		delete(out, "value")
+	}
+	return out
+}
+
+// required is a 422 for a string field that is missing or blank.
+func required(o object, field string) *apiError {
+	if v, ok := o[field].(string); !ok || strings.TrimSpace(v) == "" {
+		return invalid(field, "%s is required", field)
+	}
+	return nil
+}
+
+// unique is a 422 if another object of kind already has this value for field,
+// compared without regard to case.
+func (s *Server) unique(kind Kind, key string, o object, field string) *apiError {
+	v, ok := o[field].(string)
+	if !ok || v == "" {
+		return nil
+	}
+	c := s.collection(kind)
+	for _, k := range c.keys {
+		if k == key {
+			continue
+		}
+		if other, ok := c.byKey[k][field].(string); ok && strings.EqualFold(other, v) {
+			return invalid(field, "%s must be unique", field)
+		}
+	}
+	return nil
+}
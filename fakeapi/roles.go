@@ -0,0 +1,211 @@
+package fakeapi
+
+import (
+	"net/http"
+)
+
+// A role's users and admins are stored on the role. Its apps are not: an app
+// lists its roles in role_ids, the same relationship seen from the other side,
+// so that side is the one kept and the role's apps are worked out from it.
+// Either way in, both ways out agree.
+
+func (s *Server) registerRoles(mux *http.ServeMux) {
+	s.register(mux, resource{
+		kind:    Roles,
+		path:    "/api/2/roles",
+		prepare: prepareRole,
+		deleted: func(s *Server, key string, o object) {
+			id := o["id"].(float64)
+			for _, app := range s.collection(Apps).all() {
+				app["role_ids"] = idList(withoutID(ids(app["role_ids"]), id))
+			}
+			for _, p := range s.collection(Privileges).all() {
+				if _, ok := p["role_ids"]; ok {
+					p["role_ids"] = idList(withoutID(ids(p["role_ids"]), id))
+				}
+			}
+		},
+	})
+
+	for _, member := range []string{"users", "admins"} {
+		member := member
+		mux.HandleFunc("GET /api/2/roles/{id}/"+member, func(w http.ResponseWriter, r *http.Request) {
+			role, ok := s.collection(Roles).get(r.PathValue("id"))
+			if !ok {
+				writeError(w, errNotFound)
+				return
+			}
+			writeList(w, r, s.userSummaries(ids(role[member])))
+		})
+		mux.HandleFunc("POST /api/2/roles/{id}/"+member, func(w http.ResponseWriter, r *http.Request) {
+			s.changeRoleUsers(w, r, member, true)
+		})
+		mux.HandleFunc("DELETE /api/2/roles/{id}/"+member, func(w http.ResponseWriter, r *http.Request) {
+			s.changeRoleUsers(w, r, member, false)
+		})
+	}
+
+	mux.HandleFunc("GET /api/2/roles/{id}/apps", func(w http.ResponseWriter, r *http.Request) {
+		role, ok := s.collection(Roles).get(r.PathValue("id"))
+		if !ok {
+			writeError(w, errNotFound)
+			return
+		}
+		apps := []object{}
+		for _, app := range s.collection(Apps).all() {
+			if containsID(ids(app["role_ids"]), role["id"].(float64)) {
+				apps = append(apps, object{"id": app["id"], "name": app["name"], "icon_url": app["icon_url"]})
+			}
+		}
+		writeList(w, r, apps)
+	})
+
+	// Apps are replaced wholesale; there is no add or remove.
+	mux.HandleFunc("PUT /api/2/roles/{id}/apps", func(w http.ResponseWriter, r *http.Request) {
+		role, ok := s.collection(Roles).get(r.PathValue("id"))
+		if !ok {
+			writeError(w, errNotFound)
+			return
+		}
+		appIDs, e := decodeIDs(r)
+		if e != nil {
+			writeError(w, e)
+			return
+		}
+		if e := s.setRoleApps(role["id"].(float64), appIDs); e != nil {
+			writeError(w, e)
+			return
+		}
+		writeJSON(w, http.StatusOK, idObjects(appIDs))
+	})
+}
+
+func prepareRole(s *Server, key string, o object) *apiError {
+	if e := required(o, "name"); e != nil {
+		return e
+	}
+	for _, member := range []string{"users", "admins"} {
+		if _, ok := o[member]; !ok {
+			o[member] = []interface{}{}
+			continue
+		}
+		if e := s.usersExist(member, ids(o[member])); e != nil {
+			return e
+		}
+		o[member] = idList(ids(o[member]))
+	}
+
+	if _, ok := o["apps"]; ok {
+		appIDs := ids(o["apps"])
+		delete(o, "apps")
+		if e := s.setRoleApps(o["id"].(float64), appIDs); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// changeRoleUsers adds the users in the body to a role's users or admins, or
+// removes them.
+func (s *Server) changeRoleUsers(w http.ResponseWriter, r *http.Request, member string, add bool) {
+	role, ok := s.collection(Roles).get(r.PathValue("id"))
+	if !ok {
+		writeError(w, errNotFound)
+		return
+	}
+	userIDs, e := decodeIDs(r)
+	if e != nil {
+		writeError(w, e)
+		return
+	}
+
+	current := ids(role[member])
+	if add {
+		if e := s.usersExist(member, userIDs); e != nil {
+			writeError(w, e)
+			return
+		}
+		for _, id := range userIDs {
+			if !containsID(current, id) {
+				current = append(current, id)
+			}
+		}
+	} else {
+		for _, id := range userIDs {
+			current = withoutID(current, id)
+		}
+	}
+	role[member] = idList(current)
+	s.stamp(role, false)
+
+	if !add {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusOK, idObjects(userIDs))
+}
+
+// setRoleApps makes appIDs exactly the apps that carry the role.
+func (s *Server) setRoleApps(roleID float64, appIDs []float64) *apiError {
+	apps := s.collection(Apps)
+	for _, id := range appIDs {
+		if _, ok := apps.get(idKey(id)); !ok {
+			return invalid("apps", "app %s does not exist", idKey(id))
+		}
+	}
+	for _, app := range apps.all() {
+		roles := withoutID(ids(app["role_ids"]), roleID)
+		if containsID(appIDs, app["id"].(float64)) {
+			roles = append(roles, roleID)
+		}
+		app["role_ids"] = idList(roles)
+	}
+	return nil
+}
+
+func (s *Server) usersExist(field string, userIDs []float64) *apiError {
+	users := s.collection(Users)
+	for _, id := range userIDs {
+		if _, ok := users.get(idKey(id)); !ok {
+			return invalid(field, "user %s does not exist", idKey(id))
+		}
+	}
+	return nil
+}
+
+// userSummaries is the shape the role user and admin lists give each member.
+func (s *Server) userSummaries(userIDs []float64) []object {
+	out := make([]object, 0, len(userIDs))
+	for _, id := range userIDs {
+		u, ok := s.collection(Users).get(idKey(id))
+		if !ok {
+			continue
+		}
+		name := ""
+		if first, ok := u["firstname"].(string); ok {
+			name = first
+		}
+		if last, ok := u["lastname"].(string); ok && last != "" {
+			if name != "" {
+				name += " "
+			}
+			name += last
+		}
+		out = append(out, object{
+			"id":       u["id"],
+			"name":     name,
+			"username": u["username"],
+			"email":    u["email"],
+			"assigned": true,
+		})
+	}
+	return out
+}
+
+func idObjects(in []float64) []object {
+	out := make([]object, 0, len(in))
+	for _, id := range in {
+		out = append(out, object{"id": id})
+	}
+	return out
+}
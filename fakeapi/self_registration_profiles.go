@@ -0,0 +1,102 @@
+package fakeapi
+
+import "net/http"
+
+const profileEnvelope = "self_registration_profile"
+
+func (s *Server) registerSelfRegistrationProfiles(mux *http.ServeMux) {
+	s.register(mux, resource{
+		kind:     SelfRegistrationProfiles,
+		path:     "/api/2/self_registration_profiles",
+		envelope: profileEnvelope,
+		// Fields have endpoints of their own and are not written through the
+		// profile.
+		merge: func(stored, patch object) {
+			delete(patch, "fields")
+			merge(stored, patch)
+		},
+		prepare: func(s *Server, key string, o object) *apiError {
+			if e := required(o, "name"); e != nil {
+				return e
+			}
+			if e := required(o, "url"); e != nil {
+				return e
+			}
+			if _, ok := o["fields"].([]interface{}); !ok {
+				o["fields"] = []interface{}{}
+			}
+			for _, k := range []string{"default_role_id", "default_group_id"} {
+				if _, ok := o[k]; !ok {
+					o[k] = nil
+				}
+			}
+			return s.unique(SelfRegistrationProfiles, key, o, "url")
+		},
+	})
+
+	mux.HandleFunc("POST /api/2/self_registration_profiles/{id}/fields", func(w http.ResponseWriter, r *http.Request) {
+		profile, ok := s.collection(SelfRegistrationProfiles).get(r.PathValue("id"))
+		if !ok {
+			writeError(w, errNotFound)
+			return
+		}
+		body, e := decodeObject(r)
+		if e != nil {
+			writeError(w, e)
+			return
+		}
+		attrID, _ := body["custom_attribute_id"].(float64)
+		attr, ok := s.collection(CustomAttributes).get(idKey(attrID))
+		if !ok {
+			writeError(w, invalid("custom_attribute_id", "custom attribute %s does not exist", idKey(attrID)))
+			return
+		}
+
+		field := object{
+			"id":                  float64(s.nextID()),
+			"custom_attribute_id": attrID,
+			"name":                attr["name"],
+		}
+		fields, _ := profile["fields"].([]interface{})
+		profile["fields"] = append(fields, field)
+		s.stamp(profile, false)
+		writeJSON(w, http.StatusCreated, field)
+	})
+
+	mux.HandleFunc("DELETE /api/2/self_registration_profiles/{id}/fields/{field_id}", func(w http.ResponseWriter, r *http.Request) {
+		profile, ok := s.collection(SelfRegistrationProfiles).get(r.PathValue("id"))
+		if !ok {
+			writeError(w, errNotFound)
+			return
+		}
+		fields, _ := profile["fields"].([]interface{})
+		kept := []interface{}{}
+		for _, f := range fields {
+			if field, ok := f.(map[string]interface{}); ok && idKey(field["id"]) == r.PathValue("field_id") {
+				continue
+			}
+			kept = append(kept, f)
+		}
+		if len(kept) == len(fields) {
+			writeError(w, errNotFound)
+			return
+		}
+		profile["fields"] = kept
+		s.stamp(profile, false)
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// withoutFieldFor drops the profile fields that collect a deleted custom
+// attribute.
+func withoutFieldFor(fields interface{}, attrID float64) []interface{} {
+	list, _ := fields.([]interface{})
+	kept := []interface{}{}
+	for _, f := range list {
+		if field, ok := f.(map[string]interface{}); ok && field["custom_attribute_id"] == attrID {
+			continue
+		}
+		kept = append(kept, f)
+	}
+	return kept
+}
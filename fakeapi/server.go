@@ -0,0 +1,272 @@
+// Package fakeapi is an in-process stand-in for the OneLogin API, for running
+// the provider's acceptance tests without a tenant.
+//
+// It keeps state the way a tenant does: an object created through one request
+// is there for the next, a role's members are the users and apps that name it,
+// and deleting something takes it out of everything that referred to it. That
+// is what lets resource.Test drive the example fixtures through create, read,
+// update, import and destroy and see the same results a real account would give.
+//
+// It speaks the same wire format the provider does -- paths under /api/2 (and
+// /api/1/groups, which the groups data sources still read), bare JSON objects
+// and arrays, the v2 error body, cursor pagination headers -- and issues access
+// tokens from /auth/oauth2/v2/token for ClientID and ClientSecret only. SAML
+// apps' IdP metadata is served, unauthenticated, from /saml/metadata. It does
+// not try to enforce everything OneLogin enforces: where the fixtures refer to
+// IDs from somebody else's account, it accepts them.
+package fakeapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+)
+
+// The credentials the token endpoint accepts.
+const (
+	ClientID     = "fake-client-id"
+	ClientSecret = "fake-client-secret"
+)
+
+// tokenLifetime is the expires_in the token endpoint reports, matching what
+// OneLogin issues.
+const tokenLifetime = 36000
+
+// Kind names one collection of objects the server holds.
+type Kind string
+
+// The collections the server holds, one for each kind of object the provider
+// manages.
+const (
+	Users                    Kind = "users"
+	CustomAttributes         Kind = "custom_attributes"
+	Roles                    Kind = "roles"
+	Apps                     Kind = "apps"
+	AppRules                 Kind = "app_rules"
+	Mappings                 Kind = "mappings"
+	Privileges               Kind = "privileges"
+	SmartHooks               Kind = "hooks"
+	SmartHookEnvVars         Kind = "hook_envs"
+	Groups                   Kind = "groups"
+	AuthServers              Kind = "auth_servers"
+	AuthServerScopes         Kind = "auth_server_scopes"
+	AuthServerClaims         Kind = "auth_server_claims"
+	AuthServerClients        Kind = "auth_server_clients"
+	SelfRegistrationProfiles Kind = "self_registration_profiles"
+	Certificates             Kind = "certificates"
+)
+
+// Server is a running fake tenant. The embedded httptest.Server supplies URL
+// and Close.
+type Server struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	collections map[Kind]*collection
+	lastID      int
+	token       string
+	tokenCalls  int
+	now         func() time.Time
+	// standardCert is the account's standard signing certificate, made
+	// when the first SAML app is.
+	standardCert object
+}
+
+// NewServer starts a fake tenant with nothing in it. The caller closes it.
+func NewServer() *Server {
+	s := &Server{
+		collections: map[Kind]*collection{},
+		token:       "fake-access-token",
+		now:         time.Now,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /auth/oauth2/v2/token", s.issueToken)
+
+	api := http.NewServeMux()
+	s.registerUsers(api)
+	s.registerRoles(api)
+	s.registerApps(api)
+	s.registerMappings(api)
+	s.registerPrivileges(api)
+	s.registerSmartHooks(api)
+	s.registerGroups(api)
+	s.registerAuthServers(api)
+	s.registerSelfRegistrationProfiles(api)
+	s.registerCertificates(api)
+	mux.Handle("/api/", s.authenticated(api))
+	mux.HandleFunc("GET /saml/metadata/{id}", s.samlMetadata)
+
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// TokenRequests is how many tokens have been issued.
+func (s *Server) TokenRequests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tokenCalls
+}
+
+// Objects returns a copy of every object of kind, in the order they were
+// created, as the API would show them.
+func (s *Server) Objects(kind Kind) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := []map[string]interface{}{}
+	for _, o := range s.collection(kind).all() {
+		out = append(out, s.render(kind, o))
+	}
+	return out
+}
+
+// Seed stores obj as though it had been created through the API and returns
+// its ID, for a test that needs something to exist before the provider looks.
+// Nothing is validated.
+func (s *Server) Seed(kind Kind, obj map[string]interface{}) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o := clone(obj)
+	var key string
+	if stringIDKinds[kind] {
+		key = s.nextStringID()
+		o["id"] = key
+	} else {
+		id := s.nextID()
+		o["id"] = float64(id)
+		key = fmt.Sprint(id)
+	}
+	if kind == AppRules {
+		key = fmt.Sprintf("%v/%s", o["app_id"], key)
+		delete(o, "app_id")
+	}
+	s.stamp(o, true)
+	s.collection(kind).put(key, o)
+	return fmt.Sprint(o["id"])
+}
+
+// stringIDKinds are the kinds OneLogin identifies by UUID rather than number.
+var stringIDKinds = map[Kind]bool{
+	Privileges:       true,
+	SmartHooks:       true,
+	SmartHookEnvVars: true,
+}
+
+// issueToken answers the client credentials grant. Anything but ClientID and
+// ClientSecret is refused the way OneLogin refuses it.
+func (s *Server) issueToken(w http.ResponseWriter, r *http.Request) {
+	id, secret, ok := r.BasicAuth()
+	if !ok || id != ClientID || secret != ClientSecret {
+		writeError(w, errUnauthorized)
+		return
+	}
+
+	s.mu.Lock()
+	s.tokenCalls++
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token":  s.token,
+		"refresh_token": "fake-refresh-token",
+		"token_type":    "bearer",
+		"expires_in":    tokenLifetime,
+		"account_id":    1,
+		"created_at":    s.now().UTC().Format(time.RFC3339),
+	})
+}
+
+// authenticated refuses any API request that does not carry the issued token,
+// and holds the lock for the rest: every handler reads and writes shared state,
+// and Terraform sends requests in parallel.
+func (s *Server) authenticated(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+s.token {
+			writeError(w, errUnauthorized)
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// apiError is an error response in the shape the v2 API gives them.
+type apiError struct {
+	status  int
+	name    string
+	message string
+	errors  []interface{}
+}
+
+var (
+	errUnauthorized = &apiError{status: http.StatusUnauthorized, name: "Unauthorized", message: "Authentication Failure"}
+	errNotFound     = &apiError{status: http.StatusNotFound, name: "NotFoundError", message: "Not Found"}
+)
+
+func badRequest(format string, args ...interface{}) *apiError {
+	return &apiError{status: http.StatusBadRequest, name: "BadRequestError", message: fmt.Sprintf(format, args...)}
+}
+
+// invalid is a 422, naming the field the way OneLogin does in errors[].
+func invalid(field, format string, args ...interface{}) *apiError {
+	return &apiError{
+		status:  http.StatusUnprocessableEntity,
+		name:    "UnprocessableEntityError",
+		message: "Validation Failed",
+		errors: []interface{}{map[string]interface{}{
+			"field":   field,
+			"message": fmt.Sprintf(format, args...),
+		}},
+	}
+}
+
+func writeError(w http.ResponseWriter, e *apiError) {
+	body := map[string]interface{}{
+		"statusCode": e.status,
+		"name":       e.name,
+		"message":    e.message,
+	}
+	if len(e.errors) > 0 {
+		body["errors"] = e.errors
+	}
+	writeJSON(w, e.status, body)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// decodeObject reads a JSON object body. Numbers come back as float64, the same
+// as the provider sees them in a response.
+func decodeObject(r *http.Request) (object, *apiError) {
+	var o object
+	if err := json.NewDecoder(r.Body).Decode(&o); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, badRequest("request body is required")
+		}
+		return nil, badRequest("invalid JSON: %v", err)
+	}
+	if o == nil {
+		return nil, badRequest("request body must be a JSON object")
+	}
+	return o, nil
+}
+
+// decodeIDs reads a body that is a bare JSON array of numeric IDs, the shape
+// the role membership endpoints take.
+func decodeIDs(r *http.Request) ([]float64, *apiError) {
+	var ids []float64
+	if err := json.NewDecoder(r.Body).Decode(&ids); err != nil {
+		return nil, badRequest("expected a JSON array of IDs: %v", err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,769 @@
+package fakeapi
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/onelogin/terraform-provider-onelogin/apiclient"
+)
+
+// client talks to a fake tenant through the provider's own HTTP client, so the
+// token exchange and headers are the ones the provider sends.
+type client struct {
+	t    *testing.T
+	srv  *Server
+	http *http.Client
+}
+
+func newClient(t *testing.T) *client {
+	t.Helper()
+
+	srv := NewServer()
+	t.Cleanup(srv.Close)
+
+	hc, err := apiclient.NewHTTPClient(apiclient.Config{ClientID: ClientID, ClientSecret: ClientSecret, URL: srv.URL})
+	if err != nil {
+		t.Fatalf("building client: %v", err)
+	}
+	return &client{t: t, srv: srv, http: hc}
+}
+
+func (c *client) do(method, path string, body interface{}) (int, interface{}, http.Header) {
+	c.t.Helper()
+
+	var buf bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&buf).Encode(body)
+	}
+	req, err := http.NewRequest(method, c.srv.URL+path, &buf)
+	if err != nil {
+		c.t.Fatalf("building request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		c.t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	var out interface{}
+	json.NewDecoder(resp.Body).Decode(&out)
+	return resp.StatusCode, out, resp.Header
+}
+
+// create POSTs body and returns the new object's ID, failing unless it was
+// created.
+func (c *client) create(path string, body map[string]interface{}) string {
+	c.t.Helper()
+
+	status, out, _ := c.do(http.MethodPost, path, body)
+	if status != http.StatusCreated {
+		c.t.Fatalf("POST %s: expected 201, got %d: %v", path, status, out)
+	}
+	obj, _ := out.(map[string]interface{})
+	if inner, ok := obj[profileEnvelope].(map[string]interface{}); ok {
+		obj = inner
+	}
+	return idKey(obj["id"])
+}
+
+func (c *client) get(path string) map[string]interface{} {
+	c.t.Helper()
+
+	status, out, _ := c.do(http.MethodGet, path, nil)
+	if status != http.StatusOK {
+		c.t.Fatalf("GET %s: expected 200, got %d: %v", path, status, out)
+	}
+	obj, ok := out.(map[string]interface{})
+	if !ok {
+		c.t.Fatalf("GET %s: expected an object, got %T", path, out)
+	}
+	return obj
+}
+
+func (c *client) list(path string) []interface{} {
+	c.t.Helper()
+
+	status, out, _ := c.do(http.MethodGet, path, nil)
+	if status != http.StatusOK {
+		c.t.Fatalf("GET %s: expected 200, got %d: %v", path, status, out)
+	}
+	items, ok := out.([]interface{})
+	if !ok {
+		c.t.Fatalf("GET %s: expected an array, got %T", path, out)
+	}
+	return items
+}
+
+// num turns an ID back into the number the API takes in a body.
+func num(id string) float64 {
+	n, _ := strconv.ParseFloat(id, 64)
+	return n
+}
+
+func listIDs(items []interface{}) []string {
+	out := []string{}
+	for _, item := range items {
+		out = append(out, idKey(item.(map[string]interface{})["id"]))
+	}
+	return out
+}
+
+func TestTokenIsRequired(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/api/2/users")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected a request without a token to be refused, got %d", resp.StatusCode)
+	}
+
+	hc, _ := apiclient.NewHTTPClient(apiclient.Config{ClientID: ClientID, ClientSecret: "wrong", URL: srv.URL})
+	if _, err := hc.Get(srv.URL + "/api/2/users"); err == nil {
+		t.Fatal("expected the wrong secret to be refused a token")
+	}
+	if n := srv.TokenRequests(); n != 0 {
+		t.Fatalf("expected no tokens issued, got %d", n)
+	}
+}
+
+func TestUserLifecycle(t *testing.T) {
+	c := newClient(t)
+
+	id := c.create("/api/2/users", map[string]interface{}{
+		"username":          "jdoe",
+		"email":             "jdoe@example.com",
+		"password":          "hunter2",
+		"custom_attributes": map[string]interface{}{"dept": "IT"},
+	})
+
+	user := c.get("/api/2/users/" + id)
+	if _, ok := user["password"]; ok {
+		t.Fatal("expected the password not to be returned")
+	}
+	if user["status"] != float64(1) || user["created_at"] == nil {
+		t.Fatalf("expected defaults filled in, got %v", user)
+	}
+
+	if got := listIDs(c.list("/api/2/users?username=jdoe")); len(got) != 1 || got[0] != id {
+		t.Fatalf("expected ?username= to find the user, got %v", got)
+	}
+	if got := c.list("/api/2/users?username=nobody"); len(got) != 0 {
+		t.Fatalf("expected no match, got %v", got)
+	}
+
+	// Custom attributes merge by key rather than being replaced.
+	c.do(http.MethodPut, "/api/2/users/"+id, map[string]interface{}{
+		"custom_attributes": map[string]interface{}{"site": "NYC"},
+	})
+	attrs := c.get("/api/2/users/" + id)["custom_attributes"].(map[string]interface{})
+	if attrs["dept"] != "IT" || attrs["site"] != "NYC" {
+		t.Fatalf("expected both custom attributes, got %v", attrs)
+	}
+
+	if status, _, _ := c.do(http.MethodPost, "/api/2/users", map[string]interface{}{"username": "JDOE"}); status != http.StatusUnprocessableEntity {
+		t.Fatalf("expected a duplicate username to be refused with a 422, got %d", status)
+	}
+
+	if status, _, _ := c.do(http.MethodDelete, "/api/2/users/"+id, nil); status != http.StatusNoContent {
+		t.Fatalf("expected a 204 from delete, got %d", status)
+	}
+	if status, _, _ := c.do(http.MethodGet, "/api/2/users/"+id, nil); status != http.StatusNotFound {
+		t.Fatalf("expected a 404 after delete, got %d", status)
+	}
+}
+
+// TestRoleMembershipIsOneRelationship checks that a role's members and the
+// members' own view of the role agree whichever side they were written from.
+func TestRoleMembershipIsOneRelationship(t *testing.T) {
+	c := newClient(t)
+
+	alice := c.create("/api/2/users", map[string]interface{}{"username": "alice"})
+	bob := c.create("/api/2/users", map[string]interface{}{"username": "bob"})
+	app := c.create("/api/2/apps", map[string]interface{}{"name": "App", "connector_id": 1})
+
+	role := c.create("/api/2/roles", map[string]interface{}{
+		"name":  "Admins",
+		"users": []interface{}{num(alice)},
+		"apps":  []interface{}{num(app)},
+	})
+
+	if r := c.get("/api/2/roles/" + role); r["users"] != nil || r["apps"] != nil {
+		t.Fatalf("expected membership to be left off the role itself, got %v", r)
+	}
+	if got := listIDs(c.list("/api/2/roles/" + role + "/users")); fmt.Sprint(got) != fmt.Sprint([]string{alice}) {
+		t.Fatalf("expected alice in the role, got %v", got)
+	}
+	if got := c.get("/api/2/apps/" + app)["role_ids"]; fmt.Sprint(got) != fmt.Sprintf("[%s]", role) {
+		t.Fatalf("expected the app to list the role, got %v", got)
+	}
+	if got := c.get("/api/2/users/" + alice)["role_ids"]; fmt.Sprint(got) != fmt.Sprintf("[%s]", role) {
+		t.Fatalf("expected alice to list the role, got %v", got)
+	}
+
+	c.do(http.MethodPost, "/api/2/roles/"+role+"/users", []interface{}{num(bob)})
+	c.do(http.MethodDelete, "/api/2/roles/"+role+"/users", []interface{}{num(alice)})
+	if got := listIDs(c.list("/api/2/roles/" + role + "/users")); fmt.Sprint(got) != fmt.Sprint([]string{bob}) {
+		t.Fatalf("expected only bob after the add and remove, got %v", got)
+	}
+
+	c.do(http.MethodPut, "/api/2/roles/"+role+"/apps", []interface{}{})
+	if got := c.list("/api/2/roles/" + role + "/apps"); len(got) != 0 {
+		t.Fatalf("expected no apps after replacing them with none, got %v", got)
+	}
+
+	if status, _, _ := c.do(http.MethodPost, "/api/2/roles/"+role+"/admins", []interface{}{999}); status != http.StatusUnprocessableEntity {
+		t.Fatalf("expected an unknown user to be refused with a 422, got %d", status)
+	}
+
+	c.do(http.MethodDelete, "/api/2/users/"+bob, nil)
+	if got := c.list("/api/2/roles/" + role + "/users"); len(got) != 0 {
+		t.Fatalf("expected a deleted user to leave the role, got %v", got)
+	}
+}
+
+func TestListsArePagedWithCursors(t *testing.T) {
+	c := newClient(t)
+
+	var want []string
+	for i := 0; i < 5; i++ {
+		want = append(want, c.create("/api/2/roles", map[string]interface{}{"name": fmt.Sprintf("role %d", i)}))
+	}
+
+	var got []string
+	path := "/api/2/roles?limit=2"
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("pagination did not end")
+		}
+		status, out, h := c.do(http.MethodGet, path, nil)
+		if status != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %v", status, out)
+		}
+		got = append(got, listIDs(out.([]interface{}))...)
+		if h.Get("Total-Count") != "5" {
+			t.Fatalf("expected Total-Count 5, got %q", h.Get("Total-Count"))
+		}
+		cursor := h.Get("After-Cursor")
+		if cursor == "" {
+			break
+		}
+		path = "/api/2/roles?cursor=" + cursor
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected every role once, in order: want %v, got %v", want, got)
+	}
+
+	if status, _, _ := c.do(http.MethodGet, "/api/2/roles?limit=2&cursor=abc", nil); status != http.StatusBadRequest {
+		t.Fatalf("expected a cursor alongside limit to be refused, got %d", status)
+	}
+}
+
+func TestListFiltersTakeWildcards(t *testing.T) {
+	c := newClient(t)
+	sales := c.create("/api/2/apps", map[string]interface{}{"name": "Sales Portal", "connector_id": 1})
+	salesforce := c.create("/api/2/apps", map[string]interface{}{"name": "salesforce", "connector_id": 2})
+	presales := c.create("/api/2/apps", map[string]interface{}{"name": "Presales", "connector_id": 1})
+
+	for filter, want := range map[string][]string{
+		"name=Sales":                 nil,
+		"name=sales%20portal":        {sales},
+		"name=Sales*":                {sales, salesforce},
+		"name=*sales":                {presales},
+		"name=S*e":                   {salesforce},
+		"name=Sales*&connector_id=2": {salesforce},
+	} {
+		if got := listIDs(c.list("/api/2/apps?" + filter)); fmt.Sprint(got) != fmt.Sprint(append([]string{}, want...)) {
+			t.Errorf("?%s: expected %v, got %v", filter, want, got)
+		}
+	}
+}
+
+func TestAppRulesKeepContiguousPositions(t *testing.T) {
+	c := newClient(t)
+	app := c.create("/api/2/apps", map[string]interface{}{"name": "App", "connector_id": 1})
+	rules := "/api/2/apps/" + app + "/rules"
+
+	first := c.create(rules, map[string]interface{}{"name": "first"})
+	second := c.create(rules, map[string]interface{}{"name": "second"})
+	top := c.create(rules, map[string]interface{}{"name": "top", "position": 1})
+
+	if got := listIDs(c.list(rules)); fmt.Sprint(got) != fmt.Sprint([]string{top, first, second}) {
+		t.Fatalf("expected the rule placed at 1 to push the others down, got %v", got)
+	}
+
+	c.do(http.MethodDelete, rules+"/"+top, nil)
+	if pos := c.get(rules + "/" + second)["position"]; pos != float64(2) {
+		t.Fatalf("expected positions to close up after a delete, got %v", pos)
+	}
+
+	c.do(http.MethodDelete, "/api/2/apps/"+app, nil)
+	if status, _, _ := c.do(http.MethodGet, rules+"/"+first, nil); status != http.StatusNotFound {
+		t.Fatalf("expected an app's rules to go with it, got %d", status)
+	}
+}
+
+func TestAppsGetSSOByKind(t *testing.T) {
+	c := newClient(t)
+
+	saml := c.get("/api/2/apps/" + c.create("/api/2/apps", map[string]interface{}{
+		"name": "SAML", "connector_id": 50534,
+		"configuration": map[string]interface{}{"signature_algorithm": "SHA-1"},
+	}))
+	if sso, _ := saml["sso"].(map[string]interface{}); sso["metadata_url"] == nil || sso["certificate"] == nil {
+		t.Fatalf("expected a SAML app to be given metadata and a certificate, got %v", saml["sso"])
+	}
+
+	oidc := c.get("/api/2/apps/" + c.create("/api/2/apps", map[string]interface{}{
+		"name": "OIDC", "connector_id": 108419,
+		"configuration": map[string]interface{}{"redirect_uri": "https://localhost/cb"},
+	}))
+	if sso, _ := oidc["sso"].(map[string]interface{}); sso["client_id"] == nil {
+		t.Fatalf("expected an OIDC app to be given a client_id, got %v", oidc["sso"])
+	}
+}
+
+func TestDisabledMappingsAreUnorderedAndUnlisted(t *testing.T) {
+	c := newClient(t)
+
+	on := c.create("/api/2/mappings", map[string]interface{}{"name": "on", "enabled": true})
+	off := c.create("/api/2/mappings", map[string]interface{}{"name": "off", "enabled": false})
+
+	if got := listIDs(c.list("/api/2/mappings")); fmt.Sprint(got) != fmt.Sprint([]string{on}) {
+		t.Fatalf("expected only the enabled mapping by default, got %v", got)
+	}
+	if got := listIDs(c.list("/api/2/mappings?enabled=false")); fmt.Sprint(got) != fmt.Sprint([]string{off}) {
+		t.Fatalf("expected the disabled mapping when asked for, got %v", got)
+	}
+	if pos := c.get("/api/2/mappings/" + off)["position"]; pos != nil {
+		t.Fatalf("expected a disabled mapping to have no position, got %v", pos)
+	}
+}
+
+func TestSortMappings(t *testing.T) {
+	c := newClient(t)
+
+	a := c.create("/api/2/mappings", map[string]interface{}{"name": "a", "enabled": true})
+	b := c.create("/api/2/mappings", map[string]interface{}{"name": "b", "enabled": true})
+	off := c.create("/api/2/mappings", map[string]interface{}{"name": "off", "enabled": false})
+
+	status, out, _ := c.do(http.MethodPut, "/api/2/mappings/sort", []interface{}{num(b), num(a)})
+	if status != http.StatusOK || fmt.Sprint(out) != fmt.Sprint([]interface{}{num(b), num(a)}) {
+		t.Fatalf("expected the sorted IDs back, got %d: %v", status, out)
+	}
+	if got := listIDs(c.list("/api/2/mappings")); fmt.Sprint(got) != fmt.Sprint([]string{b, a}) {
+		t.Fatalf("expected the mappings in their new order, got %v", got)
+	}
+
+	for name, body := range map[string][]interface{}{
+		"one left out":     {num(b)},
+		"a disabled one":   {num(b), num(a), num(off)},
+		"one listed twice": {num(b), num(a), num(a)},
+		"an unknown one":   {num(b), num(a), 999999},
+	} {
+		if status, _, _ := c.do(http.MethodPut, "/api/2/mappings/sort", body); status != http.StatusUnprocessableEntity {
+			t.Errorf("%s: expected a 422, got %d", name, status)
+		}
+	}
+	if got := listIDs(c.list("/api/2/mappings")); fmt.Sprint(got) != fmt.Sprint([]string{b, a}) {
+		t.Fatalf("expected a refused sort to leave the order alone, got %v", got)
+	}
+}
+
+func TestSortAppRules(t *testing.T) {
+	c := newClient(t)
+
+	app := c.create("/api/2/apps", map[string]interface{}{"name": "Rules", "connector_id": 1})
+	other := c.create("/api/2/apps", map[string]interface{}{"name": "Other", "connector_id": 1})
+	base := "/api/2/apps/" + app + "/rules"
+	a := c.create(base, map[string]interface{}{"name": "a"})
+	b := c.create(base, map[string]interface{}{"name": "b", "enabled": false})
+	theirs := c.create("/api/2/apps/"+other+"/rules", map[string]interface{}{"name": "theirs"})
+
+	if status, _, _ := c.do(http.MethodPut, base+"/sort", []interface{}{num(b), num(a)}); status != http.StatusOK {
+		t.Fatalf("expected the sort to succeed, got %d", status)
+	}
+	if got := listIDs(c.list(base)); fmt.Sprint(got) != fmt.Sprint([]string{b, a}) {
+		t.Fatalf("expected the rules in their new order, got %v", got)
+	}
+
+	if status, _, _ := c.do(http.MethodPut, base+"/sort", []interface{}{num(b), num(a), num(theirs)}); status != http.StatusUnprocessableEntity {
+		t.Fatalf("expected another app's rule to be refused, got %d", status)
+	}
+	if status, _, _ := c.do(http.MethodPut, "/api/2/apps/999999/rules/sort", []interface{}{}); status != http.StatusNotFound {
+		t.Fatalf("expected a missing app to be a 404, got %d", status)
+	}
+}
+
+func TestAppRuleCatalogue(t *testing.T) {
+	c := newClient(t)
+
+	app := c.create("/api/2/apps", map[string]interface{}{"name": "Rules", "connector_id": 1})
+	role := c.create("/api/2/roles", map[string]interface{}{"name": "Admins"})
+	base := "/api/2/apps/" + app + "/rules"
+
+	values := func(path string) []string {
+		t.Helper()
+		out := []string{}
+		for _, o := range c.list(path) {
+			out = append(out, idKey(o.(map[string]interface{})["value"]))
+		}
+		return out
+	}
+	for path, want := range map[string][]string{
+		"/conditions/has_role/operators":     {"ri", "!ri"},
+		"/conditions/has_role/values":        {role},
+		"/conditions/last_login/values":      {},
+		"/actions/set_amazonusername/values": {"email", "username", "member_of"},
+	} {
+		if got := values(base + path); fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("%s: expected %v, got %v", path, want, got)
+		}
+	}
+	if got := values(base + "/conditions"); len(got) == 0 {
+		t.Error("expected the conditions to be listed")
+	}
+
+	for _, path := range []string{
+		base + "/conditions/nothing/operators",
+		base + "/actions/nothing/values",
+		"/api/2/apps/999999/rules/conditions",
+	} {
+		if status, _, _ := c.do(http.MethodGet, path, nil); status != http.StatusNotFound {
+			t.Errorf("%s: expected a 404, got %d", path, status)
+		}
+	}
+}
+
+func TestMappingCatalogue(t *testing.T) {
+	c := newClient(t)
+
+	role := c.create("/api/2/roles", map[string]interface{}{"name": "Admins"})
+	mapping := c.create("/api/2/mappings", map[string]interface{}{"name": "on", "enabled": true})
+
+	for path, want := range map[string]int{
+		"/api/2/mappings/conditions":                           5,
+		"/api/2/mappings/conditions/department/operators":      4,
+		"/api/2/mappings/conditions/has_role/values":           1,
+		"/api/2/mappings/actions":                              4,
+		"/api/2/mappings/actions/set_status/values":            4,
+		"/api/2/mappings/actions/set_userprincipalname/values": 0,
+	} {
+		if got := len(c.list(path)); got != want {
+			t.Errorf("%s: expected %d entries, got %d", path, want, got)
+		}
+	}
+	if got := c.list("/api/2/mappings/actions/add_role/values")[0].(map[string]interface{})["value"]; idKey(got) != role {
+		t.Errorf("expected add_role to list role %s, got %v", role, got)
+	}
+	// The catalogue's paths do not shadow the mappings themselves.
+	if got := c.get("/api/2/mappings/" + mapping)["name"]; got != "on" {
+		t.Errorf("expected the mapping to be readable, got %v", got)
+	}
+	if status, _, _ := c.do(http.MethodGet, "/api/2/mappings/actions/nothing/values", nil); status != http.StatusNotFound {
+		t.Errorf("expected a 404 for an unknown action, got %d", status)
+	}
+}
+
+func TestMappingDryRun(t *testing.T) {
+	c := newClient(t)
+
+	role := c.create("/api/2/roles", map[string]interface{}{"name": "Engineers"})
+	eng := c.create("/api/2/users", map[string]interface{}{"email": "eng@example.com", "department": "Engineering"})
+	ops := c.create("/api/2/users", map[string]interface{}{"email": "ops@example.com", "department": "Operations"})
+	mapping := c.create("/api/2/mappings", map[string]interface{}{
+		"name": "Engineers", "match": "all", "enabled": false,
+		"conditions": []interface{}{map[string]interface{}{"source": "department", "operator": "=", "value": "Engineering"}},
+		"actions": []interface{}{
+			map[string]interface{}{"action": "add_role", "value": []interface{}{role}},
+			map[string]interface{}{"action": "set_userprincipalname", "value": []interface{}{"${user.email}"}},
+		},
+	})
+
+	engID, _ := strconv.Atoi(eng)
+	opsID, _ := strconv.Atoi(ops)
+	status, body, _ := c.do(http.MethodPost, "/api/2/mappings/"+mapping+"/dryrun", []interface{}{engID, opsID})
+	if status != http.StatusOK {
+		t.Fatalf("expected a 200, got %d: %v", status, body)
+	}
+	results := body.([]interface{})
+	first, second := results[0].(map[string]interface{}), results[1].(map[string]interface{})
+	if first["mapped"] != true || second["mapped"] != false {
+		t.Fatalf("expected only the engineer to be mapped, got %v", results)
+	}
+	changes := first["changes"].([]interface{})
+	if got := changes[0].(map[string]interface{})["after"]; fmt.Sprint(got) != fmt.Sprintf("[%s]", role) {
+		t.Errorf("expected add_role to add the role, got %v", got)
+	}
+	if got := changes[1].(map[string]interface{})["after"]; fmt.Sprint(got) != "[eng@example.com]" {
+		t.Errorf("expected the macro to be expanded, got %v", got)
+	}
+	if got := c.get("/api/2/users/" + eng)["role_ids"]; fmt.Sprint(got) != "[]" {
+		t.Errorf("expected a dry run to change nothing, got roles %v", got)
+	}
+
+	if status, _, _ := c.do(http.MethodPost, "/api/2/mappings/"+mapping+"/dryrun", []interface{}{999999}); status != http.StatusUnprocessableEntity {
+		t.Errorf("expected a 422 for a user that does not exist, got %d", status)
+	}
+}
+
+func TestCertificates(t *testing.T) {
+	c := newClient(t)
+
+	status, body, _ := c.do(http.MethodPost, "/api/2/certificates/generate", map[string]interface{}{"name": "Signing", "validity_years": 2})
+	if status != http.StatusCreated {
+		t.Fatalf("expected a 201, got %d: %v", status, body)
+	}
+	generated := body.(map[string]interface{})
+	id := idKey(generated["id"])
+	if generated["key_size"] != float64(2048) || generated["private_key"] != nil {
+		t.Fatalf("expected a 2048 bit key and no private key returned, got %v", generated)
+	}
+	if status, _, _ := c.do(http.MethodPost, "/api/2/certificates/generate", map[string]interface{}{"name": "Weak", "key_size": 1024}); status != http.StatusUnprocessableEntity {
+		t.Errorf("expected a 422 for a 1024 bit key, got %d", status)
+	}
+
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	other, _ := rsa.GenerateKey(rand.Reader, 2048)
+	now := time.Now()
+	cert, _ := selfSigned(key, "Uploaded", 1, now, now.AddDate(1, 0, 0))
+	keyPEM := func(k *rsa.PrivateKey) string {
+		return string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(k)}))
+	}
+	if status, _, _ := c.do(http.MethodPost, "/api/2/certificates", map[string]interface{}{"name": "Uploaded", "value": cert, "private_key": keyPEM(other)}); status != http.StatusUnprocessableEntity {
+		t.Errorf("expected a 422 for a key that is not the certificate's, got %d", status)
+	}
+	uploaded := c.create("/api/2/certificates", map[string]interface{}{"name": "Uploaded", "value": cert, "private_key": keyPEM(key)})
+	if got := c.get("/api/2/certificates/" + uploaded); got["value"] != cert || got["private_key"] != nil {
+		t.Fatalf("expected the certificate back without its key, got %v", got)
+	}
+
+	app := c.create("/api/2/apps", map[string]interface{}{
+		"name": "SAML", "connector_id": 1,
+		"configuration": map[string]interface{}{"signature_algorithm": "SHA-256", "certificate_id": generated["id"]},
+	})
+	signing := c.get("/api/2/apps/" + app)["sso"].(map[string]interface{})["certificate"].(map[string]interface{})
+	if signing["value"] != generated["value"] {
+		t.Fatalf("expected the app to sign with the certificate it names, got %v", signing)
+	}
+	if status, _, _ := c.do(http.MethodDelete, "/api/2/certificates/"+id, nil); status != http.StatusConflict {
+		t.Errorf("expected a 409 deleting a certificate in use, got %d", status)
+	}
+	if status, _, _ := c.do(http.MethodPut, "/api/2/apps/"+app, map[string]interface{}{
+		"configuration": map[string]interface{}{"certificate_id": 999999},
+	}); status != http.StatusUnprocessableEntity {
+		t.Errorf("expected a 422 for a certificate that does not exist, got %d", status)
+	}
+}
+
+func TestSAMLMetadata(t *testing.T) {
+	c := newClient(t)
+
+	saml := c.get("/api/2/apps/" + c.create("/api/2/apps", map[string]interface{}{
+		"name": "SAML", "connector_id": 50534,
+		"configuration": map[string]interface{}{"signature_algorithm": "SHA-256"},
+	}))
+	sso := saml["sso"].(map[string]interface{})
+	block, _ := pem.Decode([]byte(sso["certificate"].(map[string]interface{})["value"].(string)))
+	if block == nil {
+		t.Fatalf("expected a SAML app to sign with a real certificate, got %v", sso["certificate"])
+	}
+
+	// Service providers fetch metadata without a token.
+	resp, err := http.Get(sso["metadata_url"].(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected a 200, got %d: %s", resp.StatusCode, body)
+	}
+	for _, want := range []string{
+		fmt.Sprintf("entityID=%q", sso["issuer"]),
+		fmt.Sprintf("Location=%q", sso["acs_url"]),
+		base64.StdEncoding.EncodeToString(block.Bytes),
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("expected the metadata to contain %s, got %s", want, body)
+		}
+	}
+
+	oidc := c.create("/api/2/apps", map[string]interface{}{
+		"name": "OIDC", "connector_id": 108419,
+		"configuration": map[string]interface{}{"redirect_uri": "https://localhost/cb"},
+	})
+	for _, id := range []string{oidc, "999999"} {
+		resp, err := http.Get(c.srv.URL + "/saml/metadata/" + id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("expected no metadata for app %s, got %d", id, resp.StatusCode)
+		}
+	}
+}
+
+func TestSmartHooks(t *testing.T) {
+	c := newClient(t)
+
+	c.create("/api/2/hooks/envs", map[string]interface{}{"name": "API_KEY", "value": "secret"})
+	env := c.list("/api/2/hooks/envs")[0].(map[string]interface{})
+	if _, ok := env["value"]; ok {
+		t.Fatal("expected an environment variable's value never to be returned")
+	}
+
+	hook := c.get("/api/2/hooks/" + c.create("/api/2/hooks", map[string]interface{}{
+		"type": "pre-authentication", "function": "ZnVuYw==", "env_vars": []interface{}{"API_KEY"},
+	}))
+	if hook["context_version"] != "1.0.0" || hook["status"] != "ready" {
+		t.Fatalf("expected defaults filled in, got %v", hook)
+	}
+	if vars := hook["env_vars"].([]interface{}); len(vars) != 1 || vars[0].(map[string]interface{})["name"] != "API_KEY" {
+		t.Fatalf("expected the hook to show its variable, got %v", hook["env_vars"])
+	}
+
+	status, _, _ := c.do(http.MethodPost, "/api/2/hooks", map[string]interface{}{"type": "pre-authentication", "function": "ZnVuYw=="})
+	if status != http.StatusConflict {
+		t.Fatalf("expected a second synchronous hook to be refused with a 409, got %d", status)
+	}
+}
+
+func TestAuthServerScopesClaimsAndClients(t *testing.T) {
+	c := newClient(t)
+
+	server := c.create("/api/2/api_authorizations", map[string]interface{}{
+		"name": "API",
+		"configuration": map[string]interface{}{
+			"resource_identifier": "https://api.example.com", "audiences": []interface{}{"https://api.example.com"},
+		},
+	})
+	base := "/api/2/api_authorizations/" + server
+
+	read := c.create(base+"/scopes", map[string]interface{}{"value": "read:all", "description": "Read"})
+	write := c.create(base+"/scopes", map[string]interface{}{"value": "write:all"})
+	if status, _, _ := c.do(http.MethodPost, base+"/scopes", map[string]interface{}{"value": "READ:ALL"}); status != http.StatusUnprocessableEntity {
+		t.Fatalf("expected a duplicate scope value to be refused, got %d", status)
+	}
+
+	claim := c.get(base + "/claims/" + c.create(base+"/claims", map[string]interface{}{"name": "groups", "user_attribute_mappings": "memberOf"}))
+	if claim["label"] != "groups" {
+		t.Fatalf("expected a claim to be shown with its name as its label, got %v", claim)
+	}
+
+	app := c.create("/api/2/apps", map[string]interface{}{"name": "Client", "connector_id": 108419})
+	status, out, _ := c.do(http.MethodPost, base+"/clients", map[string]interface{}{"app_id": num(app), "scopes": []interface{}{num(read), num(write)}})
+	if status != http.StatusCreated || idKey(out.(map[string]interface{})["app_id"]) != app {
+		t.Fatalf("expected the client app to be added, got %d: %v", status, out)
+	}
+	if status, _, _ := c.do(http.MethodPost, base+"/clients", map[string]interface{}{"app_id": num(app)}); status != http.StatusUnprocessableEntity {
+		t.Fatalf("expected the same app to be refused a second time, got %d", status)
+	}
+
+	c.do(http.MethodDelete, base+"/scopes/"+write, nil)
+	clients := c.list(base + "/clients")
+	scopes := clients[0].(map[string]interface{})["scopes"].([]interface{})
+	if len(scopes) != 1 || scopes[0].(map[string]interface{})["value"] != "read:all" {
+		t.Fatalf("expected the deleted scope to be taken off the client, got %v", scopes)
+	}
+
+	c.do(http.MethodDelete, base, nil)
+	if status, _, _ := c.do(http.MethodGet, base+"/scopes", nil); status != http.StatusNotFound {
+		t.Fatalf("expected an auth server's scopes to go with it, got %d", status)
+	}
+}
+
+func TestSelfRegistrationProfilesAreWrapped(t *testing.T) {
+	c := newClient(t)
+
+	attr := c.create("/api/2/users/custom_attributes", map[string]interface{}{
+		"user_field": map[string]interface{}{"name": "Employee ID", "shortname": "employee_id"},
+	})
+	profile := c.create("/api/2/self_registration_profiles", map[string]interface{}{
+		"self_registration_profile": map[string]interface{}{"name": "Profile", "url": "profile"},
+	})
+
+	status, _, _ := c.do(http.MethodPost, "/api/2/self_registration_profiles/"+profile+"/fields", map[string]interface{}{"custom_attribute_id": num(attr)})
+	if status != http.StatusCreated {
+		t.Fatalf("expected the field to be added, got %d", status)
+	}
+
+	got := c.get("/api/2/self_registration_profiles/" + profile)
+	inner, ok := got[profileEnvelope].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected the profile under %q, got %v", profileEnvelope, got)
+	}
+	if fields := inner["fields"].([]interface{}); len(fields) != 1 || fields[0].(map[string]interface{})["name"] != "Employee ID" {
+		t.Fatalf("expected the field named for its attribute, got %v", inner["fields"])
+	}
+
+	c.do(http.MethodDelete, "/api/2/users/custom_attributes/"+attr, nil)
+	inner = c.get("/api/2/self_registration_profiles/" + profile)[profileEnvelope].(map[string]interface{})
+	if fields := inner["fields"].([]interface{}); len(fields) != 0 {
+		t.Fatalf("expected the field to go with its attribute, got %v", fields)
+	}
+}
+
+func TestGroupsV1IsWrapped(t *testing.T) {
+	c := newClient(t)
+	id := c.create("/api/2/groups", map[string]interface{}{"name": "Eng", "reference": "ignored"})
+
+	if ref, ok := c.get("/api/2/groups/" + id)["reference"]; !ok || ref != nil {
+		t.Fatalf("expected reference to come back null, got %v", ref)
+	}
+
+	body := c.get("/api/1/groups")
+	data, ok := body["data"].([]interface{})
+	if !ok || len(data) != 1 {
+		t.Fatalf("expected the v1 list under data, got %v", body)
+	}
+}
+
+// TestParallelCreates is what Terraform does with its default parallelism.
+func TestParallelCreates(t *testing.T) {
+	c := newClient(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c.create("/api/2/roles", map[string]interface{}{"name": fmt.Sprintf("role %d", i)})
+		}(i)
+	}
+	wg.Wait()
+
+	if n := len(c.srv.Objects(Roles)); n != 20 {
+		t.Fatalf("expected 20 roles, got %d", n)
+	}
+	if n := c.srv.TokenRequests(); n != 1 {
+		t.Fatalf("expected the client to fetch a single token, got %d", n)
+	}
+}
+
+func TestSeed(t *testing.T) {
+	c := newClient(t)
+
+	id := c.srv.Seed(Users, map[string]interface{}{"username": "seeded"})
+	if got := c.get("/api/2/users/" + id)["username"]; got != "seeded" {
+		t.Fatalf("expected the seeded user to be served, got %v", got)
+	}
+}
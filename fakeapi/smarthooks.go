@@ -0,0 +1,135 @@
+package fakeapi
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+)
+
+// synchronousHookTypes run inline with a login, and a tenant may have only one
+// hook of each.
+var synchronousHookTypes = map[string]bool{
+	"pre-authentication": true,
+	"user-migration":     true,
+}
+
+// envVarName is the shape OneLogin accepts for an environment variable name.
+var envVarName = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+func (s *Server) registerSmartHooks(mux *http.ServeMux) {
+	s.register(mux, resource{
+		kind:    SmartHooks,
+		path:    "/api/2/hooks",
+		prepare: prepareSmartHook,
+	})
+
+	s.register(mux, resource{
+		kind: SmartHookEnvVars,
+		path: "/api/2/hooks/envs",
+		// Only the value can change once a variable exists.
+		merge: func(stored, patch object) {
+			if v, ok := patch["value"]; ok {
+				stored["value"] = v
+			}
+		},
+		prepare: func(s *Server, key string, o object) *apiError {
+			if e := required(o, "name"); e != nil {
+				return e
+			}
+			if e := required(o, "value"); e != nil {
+				return e
+			}
+			if !envVarName.MatchString(o["name"].(string)) {
+				return invalid("name", "name must be upper case letters, digits and underscores")
+			}
+			return s.unique(SmartHookEnvVars, key, o, "name")
+		},
+		deleted: func(s *Server, key string, o object) {
+			for _, hook := range s.collection(SmartHooks).all() {
+				names, _ := hook["env_vars"].([]interface{})
+				kept := []interface{}{}
+				for _, n := range names {
+					if n != o["name"] {
+						kept = append(kept, n)
+					}
+				}
+				hook["env_vars"] = kept
+			}
+		},
+	})
+}
+
+func prepareSmartHook(s *Server, key string, o object) *apiError {
+	if e := required(o, "type"); e != nil {
+		return e
+	}
+	if e := required(o, "function"); e != nil {
+		return e
+	}
+
+	hookType := o["type"].(string)
+	if synchronousHookTypes[hookType] {
+		for k, other := range s.collection(SmartHooks).byKey {
+			if k != key && other["type"] == hookType {
+				return &apiError{
+					status:  http.StatusConflict,
+					name:    "ConflictError",
+					message: fmt.Sprintf("The '%s' hook is synchronous and can only have one defined function", hookType),
+				}
+			}
+		}
+	}
+
+	// Hooks refer to environment variables by name.
+	names, _ := o["env_vars"].([]interface{})
+	for _, n := range names {
+		if _, ok := s.envVarNamed(n); !ok {
+			return invalid("env_vars", "environment variable %v does not exist", n)
+		}
+	}
+
+	defaults := object{
+		"env_vars":        []interface{}{},
+		"disabled":        false,
+		"timeout":         float64(1),
+		"retries":         float64(0),
+		"runtime":         "nodejs18.x",
+		"context_version": "1.0.0",
+		"packages":        map[string]interface{}{},
+		"conditions":      []interface{}{},
+		"options": map[string]interface{}{
+			"risk_enabled":            false,
+			"location_enabled":        false,
+			"mfa_device_info_enabled": false,
+		},
+	}
+	for k, v := range defaults {
+		if o[k] == nil {
+			o[k] = v
+		}
+	}
+	o["status"] = "ready"
+	return nil
+}
+
+func (s *Server) envVarNamed(name interface{}) (object, bool) {
+	for _, env := range s.collection(SmartHookEnvVars).all() {
+		if env["name"] == name {
+			return env, true
+		}
+	}
+	return nil, false
+}
+
+// hookEnvVars is how a hook shows the variables it names: as the variables
+// themselves, less their values.
+func (s *Server) hookEnvVars(names interface{}) []interface{} {
+	list, _ := names.([]interface{})
+	out := []interface{}{}
+	for _, n := range list {
+		if env, ok := s.envVarNamed(n); ok {
+			out = append(out, s.render(SmartHookEnvVars, env))
+		}
+	}
+	return out
+}
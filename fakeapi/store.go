@@ -0,0 +1,389 @@
+package fakeapi
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// object is one stored API object, held as decoded JSON.
+type object = map[string]interface{}
+
+// collection holds one kind of object in creation order, which is the order
+// the API lists them in.
+type collection struct {
+	keys  []string
+	byKey map[string]object
+}
+
+func (s *Server) collection(kind Kind) *collection {
+	c, ok := s.collections[kind]
+	if !ok {
+		c = &collection{byKey: map[string]object{}}
+		s.collections[kind] = c
+	}
+	return c
+}
+
+func (c *collection) put(key string, o object) {
+	if _, ok := c.byKey[key]; !ok {
+		c.keys = append(c.keys, key)
+	}
+	c.byKey[key] = o
+}
+
+func (c *collection) get(key string) (object, bool) {
+	o, ok := c.byKey[key]
+	return o, ok
+}
+
+func (c *collection) remove(key string) bool {
+	if _, ok := c.byKey[key]; !ok {
+		return false
+	}
+	delete(c.byKey, key)
+	for i, k := range c.keys {
+		if k == key {
+			c.keys = append(c.keys[:i], c.keys[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+func (c *collection) all() []object {
+	out := make([]object, 0, len(c.keys))
+	for _, k := range c.keys {
+		out = append(out, c.byKey[k])
+	}
+	return out
+}
+
+// nextID hands out numeric IDs. They are shared across kinds, and start well
+// above 1 so that an ID accidentally used as the wrong kind is unlikely to
+// match anything.
+func (s *Server) nextID() int {
+	if s.lastID == 0 {
+		s.lastID = 100000
+	}
+	s.lastID++
+	return s.lastID
+}
+
+// nextStringID hands out an ID in the UUID shape OneLogin uses for hooks,
+// their environment variables and privileges.
+func (s *Server) nextStringID() string {
+	n := s.nextID()
+	return fmt.Sprintf("%08x-0000-4000-8000-%012x", n, n)
+}
+
+// stamp sets updated_at, and created_at as well for a new object.
+func (s *Server) stamp(o object, created bool) {
+	now := s.now().UTC().Format(time.RFC3339)
+	if created {
+		o["created_at"] = now
+	}
+	o["updated_at"] = now
+}
+
+// clone deep-copies o through JSON, so nothing handed out aliases stored state
+// and every number is a float64 whichever way it came in.
+func clone(o map[string]interface{}) object {
+	raw, err := json.Marshal(o)
+	if err != nil {
+		panic(fmt.Sprintf("fakeapi: object is not JSON: %v", err))
+	}
+	var out object
+	json.Unmarshal(raw, &out)
+	return out
+}
+
+// merge applies an update the way OneLogin's PUTs do: the keys in the body
+// replace what was stored, and keys left out stay as they were.
+func merge(stored, patch object) {
+	for k, v := range patch {
+		stored[k] = v
+	}
+}
+
+// idKey turns a decoded JSON ID into the key it is stored under.
+func idKey(v interface{}) string {
+	switch id := v.(type) {
+	case float64:
+		return strconv.FormatFloat(id, 'f', -1, 64)
+	case string:
+		return id
+	}
+	return fmt.Sprint(v)
+}
+
+// ids reads a list of numeric IDs from a stored object.
+func ids(v interface{}) []float64 {
+	list, _ := v.([]interface{})
+	out := make([]float64, 0, len(list))
+	for _, item := range list {
+		switch id := item.(type) {
+		case float64:
+			out = append(out, id)
+		case string:
+			if n, err := strconv.ParseFloat(id, 64); err == nil {
+				out = append(out, n)
+			}
+		}
+	}
+	return out
+}
+
+func idList(in []float64) []interface{} {
+	out := make([]interface{}, 0, len(in))
+	for _, id := range in {
+		out = append(out, id)
+	}
+	return out
+}
+
+func containsID(list []float64, id float64) bool {
+	for _, v := range list {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func withoutID(list []float64, id float64) []float64 {
+	out := make([]float64, 0, len(list))
+	for _, v := range list {
+		if v != id {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// place puts target at position pos among items, 1-based, and renumbers them
+// all so positions stay contiguous. A position of 0 or past the end appends.
+// Rules and mappings are ordered this way: moving one shifts the rest.
+func place(items []object, target object, pos int) {
+	sort.SliceStable(items, func(i, j int) bool { return position(items[i]) < position(items[j]) })
+
+	ordered := make([]object, 0, len(items)+1)
+	for _, o := range items {
+		if idKey(o["id"]) != idKey(target["id"]) {
+			ordered = append(ordered, o)
+		}
+	}
+	if pos < 1 || pos > len(ordered)+1 {
+		pos = len(ordered) + 1
+	}
+	ordered = append(ordered[:pos-1], append([]object{target}, ordered[pos-1:]...)...)
+
+	for i, o := range ordered {
+		o["position"] = float64(i + 1)
+	}
+}
+
+// renumber closes the gap a removed rule or mapping leaves.
+func renumber(items []object) {
+	sort.SliceStable(items, func(i, j int) bool { return position(items[i]) < position(items[j]) })
+	for i, o := range items {
+		o["position"] = float64(i + 1)
+	}
+}
+
+// sortAll gives each of items the position its ID has in sortIDs, the way
+// the bulk sort endpoints reorder rules and mappings. sortIDs has to name
+// every one of items exactly once; field is what an error names.
+func sortAll(field string, items []object, sortIDs []float64) *apiError {
+	byID := map[string]object{}
+	for _, o := range items {
+		byID[idKey(o["id"])] = o
+	}
+	seen := map[string]bool{}
+	for _, id := range sortIDs {
+		key := idKey(id)
+		if _, ok := byID[key]; !ok {
+			return invalid(field, "%s is not one of the items being sorted", key)
+		}
+		if seen[key] {
+			return invalid(field, "%s is listed more than once", key)
+		}
+		seen[key] = true
+	}
+	for key := range byID {
+		if !seen[key] {
+			return invalid(field, "%s is missing; every item has to be listed", key)
+		}
+	}
+
+	for i, id := range sortIDs {
+		byID[idKey(id)]["position"] = float64(i + 1)
+	}
+	return nil
+}
+
+// before orders positioned objects first, by position, and the rest after
+// them in the order they already had.
+func before(a, b object) bool {
+	pa, pb := position(a), position(b)
+	switch {
+	case pa == 0:
+		return false
+	case pb == 0:
+		return true
+	}
+	return pa < pb
+}
+
+func position(o object) int {
+	if p, ok := o["position"].(float64); ok {
+		return int(p)
+	}
+	return 0
+}
+
+// pagingParams are the query parameters that page a list rather than filter
+// it.
+var pagingParams = map[string]bool{"limit": true, "page": true, "cursor": true, "fields": true, "sort": true}
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 1000
+)
+
+// writeList answers a list request. Every query parameter other than the
+// paging ones is a filter on the field of that name, compared as text by
+// matchesFilter, which covers ?username=, ?email=, ?name= and the like. The
+// result is paged with limit and cursor, and the pagination headers OneLogin
+// sends.
+func writeList(w http.ResponseWriter, r *http.Request, items []object) {
+	q := r.URL.Query()
+
+	matched := make([]object, 0, len(items))
+	for _, o := range items {
+		if matches(o, q) {
+			matched = append(matched, o)
+		}
+	}
+
+	limit := defaultPageLimit
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, badRequest("limit must be a positive integer"))
+			return
+		}
+		limit = n
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+
+	offset := 0
+	if v := q.Get("cursor"); v != "" {
+		// The cursor stands for limit and page both, and OneLogin refuses it
+		// alongside either.
+		if q.Get("limit") != "" || q.Get("page") != "" {
+			writeError(w, badRequest("cursor cannot be combined with limit or page"))
+			return
+		}
+		n, l, ok := decodeCursor(v)
+		if !ok {
+			writeError(w, badRequest("invalid cursor"))
+			return
+		}
+		offset, limit = n, l
+	} else if v := q.Get("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, badRequest("page must be a positive integer"))
+			return
+		}
+		offset = (n - 1) * limit
+	}
+
+	end := offset + limit
+	if end > len(matched) {
+		end = len(matched)
+	}
+	page := []object{}
+	if offset < len(matched) {
+		page = matched[offset:end]
+	}
+
+	h := w.Header()
+	h.Set("Total-Count", strconv.Itoa(len(matched)))
+	h.Set("Total-Pages", strconv.Itoa((len(matched)+limit-1)/limit))
+	h.Set("Current-Page", strconv.Itoa(offset/limit+1))
+	h.Set("Page-Items", strconv.Itoa(limit))
+	if end < len(matched) {
+		h.Set("After-Cursor", encodeCursor(end, limit))
+	}
+	if offset > 0 {
+		before := offset - limit
+		if before < 0 {
+			before = 0
+		}
+		h.Set("Before-Cursor", encodeCursor(before, limit))
+	}
+
+	writeJSON(w, http.StatusOK, page)
+}
+
+func matches(o object, q map[string][]string) bool {
+	for key, values := range q {
+		if pagingParams[key] || len(values) == 0 {
+			continue
+		}
+		v, ok := o[key]
+		if !ok || v == nil || !matchesFilter(fmt.Sprint(v), values[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesFilter compares a field with a filter value as OneLogin does: without
+// regard to case, and with * in the filter standing for any run of characters,
+// so that ?name=Sales* finds every app whose name starts with Sales.
+func matchesFilter(v, filter string) bool {
+	v, filter = strings.ToLower(v), strings.ToLower(filter)
+	parts := strings.Split(filter, "*")
+	if len(parts) == 1 {
+		return v == filter
+	}
+	if !strings.HasPrefix(v, parts[0]) {
+		return false
+	}
+	v = v[len(parts[0]):]
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(v, part)
+		if i < 0 {
+			return false
+		}
+		v = v[i+len(part):]
+	}
+	return len(v) >= len(last) && strings.HasSuffix(v, last)
+}
+
+// Cursors are opaque to clients; these carry the offset and page size.
+func encodeCursor(offset, limit int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d", offset, limit)))
+}
+
+func decodeCursor(c string) (offset, limit int, ok bool) {
+	raw, err := base64.RawURLEncoding.DecodeString(c)
+	if err != nil {
+		return 0, 0, false
+	}
+	if _, err := fmt.Sscanf(string(raw), "%d:%d", &offset, &limit); err != nil || offset < 0 || limit < 1 {
+		return 0, 0, false
+	}
+	return offset, limit, true
+}
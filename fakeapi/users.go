@@ -0,0 +1,120 @@
+package fakeapi
+
+import "net/http"
+
+// writeOnlyUserFields are accepted when a user is written and never returned.
+var writeOnlyUserFields = []string{"password", "password_confirmation", "password_algorithm", "salt"}
+
+func (s *Server) registerUsers(mux *http.ServeMux) {
+	s.register(mux, resource{
+		kind:    Users,
+		path:    "/api/2/users",
+		merge:   mergeUser,
+		prepare: prepareUser,
+		deleted: func(s *Server, key string, o object) {
+			id := o["id"].(float64)
+			for _, role := range s.collection(Roles).all() {
+				role["users"] = idList(withoutID(ids(role["users"]), id))
+				role["admins"] = idList(withoutID(ids(role["admins"]), id))
+			}
+			for _, p := range s.collection(Privileges).all() {
+				if _, ok := p["user_ids"]; ok {
+					p["user_ids"] = idList(withoutID(ids(p["user_ids"]), id))
+				}
+			}
+		},
+	})
+
+	s.register(mux, resource{
+		kind:            CustomAttributes,
+		path:            "/api/2/users/custom_attributes",
+		requestEnvelope: "user_field",
+		prepare: func(s *Server, key string, o object) *apiError {
+			if e := required(o, "name"); e != nil {
+				return e
+			}
+			if e := required(o, "shortname"); e != nil {
+				return e
+			}
+			if _, ok := o["position"]; !ok {
+				o["position"] = nil
+			}
+			return s.unique(CustomAttributes, key, o, "shortname")
+		},
+		deleted: func(s *Server, key string, o object) {
+			shortname, _ := o["shortname"].(string)
+			for _, u := range s.collection(Users).all() {
+				if attrs, ok := u["custom_attributes"].(map[string]interface{}); ok {
+					delete(attrs, shortname)
+				}
+			}
+			for _, p := range s.collection(SelfRegistrationProfiles).all() {
+				p["fields"] = withoutFieldFor(p["fields"], o["id"].(float64))
+			}
+		},
+	})
+}
+
+// mergeUser applies a user update. custom_attributes is merged key by key, as
+// the API does: a body naming one attribute leaves the others alone.
+func mergeUser(stored, patch object) {
+	attrs, _ := stored["custom_attributes"].(map[string]interface{})
+	merge(stored, patch)
+
+	incoming, ok := patch["custom_attributes"].(map[string]interface{})
+	if !ok {
+		stored["custom_attributes"] = attrs
+		return
+	}
+	if attrs == nil {
+		attrs = map[string]interface{}{}
+	}
+	for k, v := range incoming {
+		attrs[k] = v
+	}
+	stored["custom_attributes"] = attrs
+}
+
+func prepareUser(s *Server, key string, o object) *apiError {
+	for _, f := range writeOnlyUserFields {
+		delete(o, f)
+	}
+	// Derived from the roles that list the user; never stored on the user.
+	delete(o, "role_ids")
+
+	username, _ := o["username"].(string)
+	email, _ := o["email"].(string)
+	if username == "" && email == "" {
+		return invalid("username", "username or email is required")
+	}
+	if e := s.unique(Users, key, o, "username"); e != nil {
+		return e
+	}
+	if e := s.unique(Users, key, o, "email"); e != nil {
+		return e
+	}
+
+	for _, f := range []string{"state", "status"} {
+		if v, _ := o[f].(float64); v == 0 {
+			o[f] = float64(1)
+		}
+	}
+	if _, ok := o["custom_attributes"].(map[string]interface{}); !ok {
+		o["custom_attributes"] = map[string]interface{}{}
+	}
+	if _, ok := o["last_login"]; !ok {
+		o["last_login"] = nil
+	}
+	return nil
+}
+
+// rolesOfUser lists the roles a user is a member of.
+func (s *Server) rolesOfUser(id float64) []float64 {
+	out := []float64{}
+	for _, role := range s.collection(Roles).all() {
+		if containsID(ids(role["users"]), id) {
+			out = append(out, role["id"].(float64))
+		}
+	}
+	return out
+}
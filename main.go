@@ -1,11 +1,18 @@
 package main
 
 import (
+	"os"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 	"github.com/onelogin/terraform-provider-onelogin/onelogin"
 )
 
 func main() {
+	// Terraform runs the provider with no arguments. An export is run by hand.
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		os.Exit(runExport(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	plugin.Serve(&plugin.ServeOpts{
 		ProviderFunc: onelogin.Provider,
 	})
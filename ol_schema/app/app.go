@@ -199,3 +199,29 @@ func Inflate(s map[string]interface{}) (models.App, error) {
 
 	return app, nil
 }
+
+// AppQuery implements the Queryable interface for listing apps. The API
+// matches Name against app names, with * as a wildcard, and ConnectorID and
+// AuthMethod exactly; leave them empty to list every app.
+type AppQuery struct {
+	Limit       string `json:"limit,omitempty"`
+	Page        string `json:"page,omitempty"`
+	Name        string `json:"name,omitempty"`
+	ConnectorID string `json:"connector_id,omitempty"`
+	AuthMethod  string `json:"auth_method,omitempty"`
+}
+
+// GetKeyValidators returns the validation functions for the query keys
+func (q *AppQuery) GetKeyValidators() map[string]func(interface{}) bool {
+	isString := func(v interface{}) bool {
+		_, ok := v.(string)
+		return ok
+	}
+	return map[string]func(interface{}) bool{
+		"limit":        isString,
+		"page":         isString,
+		"name":         isString,
+		"connector_id": isString,
+		"auth_method":  isString,
+	}
+}
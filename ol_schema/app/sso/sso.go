@@ -85,3 +85,25 @@ func Flatten(ssoData map[string]interface{}) map[string]interface{} {
 	// Return the flattened map
 	return tfMap
 }
+
+// FlattenCertMap takes the sso map from the API response and returns its
+// certificate node the way FlattenSAMLCert does. Only SAML apps have one, so
+// for any other app the map is empty.
+func FlattenCertMap(ssoData map[string]interface{}) map[string]interface{} {
+	tfMap := map[string]interface{}{}
+
+	cert, ok := ssoData["certificate"].(map[string]interface{})
+	if !ok {
+		return tfMap
+	}
+
+	if name, ok := cert["name"].(string); ok {
+		tfMap["name"] = name
+	}
+
+	if value, ok := cert["value"].(string); ok {
+		tfMap["value"] = value
+	}
+
+	return tfMap
+}
@@ -92,3 +92,37 @@ func TestFlattenSAML(t *testing.T) {
 		})
 	}
 }
+
+func TestFlattenCertMap(t *testing.T) {
+	tests := map[string]struct {
+		InputData      map[string]interface{}
+		ExpectedOutput map[string]interface{}
+	}{
+		"returns the certificate of a SAML app": {
+			InputData: map[string]interface{}{
+				"metadata_url": "test",
+				"certificate": map[string]interface{}{
+					"id":    float64(123),
+					"name":  "test",
+					"value": "-----BEGIN CERTIFICATE-----",
+				},
+			},
+			ExpectedOutput: map[string]interface{}{
+				"name":  "test",
+				"value": "-----BEGIN CERTIFICATE-----",
+			},
+		},
+		"returns an empty map for an app without a certificate": {
+			InputData: map[string]interface{}{
+				"client_id": "test",
+			},
+			ExpectedOutput: map[string]interface{}{},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			subj := FlattenCertMap(test.InputData)
+			assert.Equal(t, test.ExpectedOutput, subj)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package authserverclaimschema
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin/models"
+)
+
+// Schema returns a key/value map of the various fields that make up a custom
+// Claim of a OneLogin AuthServer.
+func Schema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"auth_server_id": {
+			Type:        schema.TypeInt,
+			Required:    true,
+			ForceNew:    true,
+			Description: "ID of the authorization server the claim belongs to",
+		},
+		"claim_id": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "The claim's own ID, unique within its authorization server",
+		},
+		"name": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "Name of the claim in the access token",
+		},
+		"user_attribute_mappings": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The user attribute the claim's value is taken from, for example email or memberOf",
+		},
+		"user_attribute_macros": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "A macro the claim's value is built from, when user_attribute_mappings is _macro_",
+		},
+	}
+}
+
+// Inflate takes a key/value map of interfaces and uses the fields to construct
+// the body the API takes to create or update an AccessTokenClaim.
+func Inflate(s map[string]interface{}) models.AccessTokenClaim {
+	out := models.AccessTokenClaim{}
+	if name, ok := s["name"].(string); ok {
+		out.Name = &name
+	}
+	if mappings, ok := s["user_attribute_mappings"].(string); ok && mappings != "" {
+		out.UserAttributeMappings = &mappings
+	}
+	if macros, ok := s["user_attribute_macros"].(string); ok && macros != "" {
+		out.UserAttributeMacros = &macros
+	}
+	return out
+}
+
+// Flatten takes a claim as the API lists it and returns the fields the
+// resource records. A claim is created with a name but listed with it as its
+// label.
+func Flatten(claim map[string]interface{}) map[string]interface{} {
+	out := map[string]interface{}{"name": "", "user_attribute_mappings": "", "user_attribute_macros": ""}
+	if label, ok := claim["label"].(string); ok {
+		out["name"] = label
+	} else if name, ok := claim["name"].(string); ok {
+		out["name"] = name
+	}
+	for _, k := range []string{"user_attribute_mappings", "user_attribute_macros"} {
+		if v, ok := claim[k].(string); ok {
+			out[k] = v
+		}
+	}
+	return out
+}
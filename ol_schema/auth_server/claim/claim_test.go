@@ -0,0 +1,39 @@
+package authserverclaimschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchema(t *testing.T) {
+	t.Run("creates and returns a map of a Claim Schema", func(t *testing.T) {
+		provSchema := Schema()
+		assert.NotNil(t, provSchema["auth_server_id"])
+		assert.True(t, provSchema["claim_id"].Computed)
+		assert.NotNil(t, provSchema["name"])
+		assert.NotNil(t, provSchema["user_attribute_mappings"])
+		assert.NotNil(t, provSchema["user_attribute_macros"])
+	})
+}
+
+func TestInflate(t *testing.T) {
+	t.Run("carries what is set", func(t *testing.T) {
+		out := Inflate(map[string]interface{}{"name": "groups", "user_attribute_mappings": "memberOf"})
+		if assert.NotNil(t, out.Name) {
+			assert.Equal(t, "groups", *out.Name)
+		}
+		if assert.NotNil(t, out.UserAttributeMappings) {
+			assert.Equal(t, "memberOf", *out.UserAttributeMappings)
+		}
+		assert.Nil(t, out.UserAttributeMacros)
+	})
+}
+
+// TestFlattenReadsTheLabel covers the claim list naming each claim's name
+// label, which would otherwise read back as a name of "" and a diff on every
+// plan.
+func TestFlattenReadsTheLabel(t *testing.T) {
+	out := Flatten(map[string]interface{}{"id": float64(7), "label": "groups", "user_attribute_mappings": "memberOf", "user_attribute_macros": nil})
+	assert.Equal(t, map[string]interface{}{"name": "groups", "user_attribute_mappings": "memberOf", "user_attribute_macros": ""}, out)
+}
@@ -0,0 +1,76 @@
+package authserverclientappschema
+
+import (
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin/models"
+)
+
+// Schema returns a key/value map of the various fields that make up a client
+// app of a OneLogin AuthServer: an app allowed to ask the server for tokens,
+// and the scopes it may ask for.
+func Schema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"auth_server_id": {
+			Type:        schema.TypeInt,
+			Required:    true,
+			ForceNew:    true,
+			Description: "ID of the authorization server",
+		},
+		"app_id": {
+			Type:        schema.TypeInt,
+			Required:    true,
+			ForceNew:    true,
+			Description: "ID of the OpenID Connect app to make a client of the authorization server",
+		},
+		"scope_ids": {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeInt},
+			Description: "IDs of the authorization server's scopes the app may ask for",
+		},
+		"name": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Name of the app",
+		},
+	}
+}
+
+// Inflate takes a key/value map of interfaces and uses the fields to construct
+// the body the API takes to add a ClientApp, or to change its scopes. The app
+// ID is only sent when adding one; afterwards it is in the path.
+func Inflate(s map[string]interface{}) models.ClientApp {
+	out := models.ClientApp{Scopes: []int32{}}
+	if appID, ok := s["app_id"].(int); ok && appID != 0 {
+		id := int32(appID)
+		out.AppID = &id
+	}
+	if scopes, ok := s["scope_ids"].(*schema.Set); ok {
+		for _, id := range scopes.List() {
+			out.Scopes = append(out.Scopes, int32(id.(int)))
+		}
+	}
+	sort.Slice(out.Scopes, func(i, j int) bool { return out.Scopes[i] < out.Scopes[j] })
+	return out
+}
+
+// FlattenScopeIDs reads the IDs of a client app's scopes, which the API lists
+// as the scopes themselves.
+func FlattenScopeIDs(clientApp map[string]interface{}) []int {
+	scopes, _ := clientApp["scopes"].([]interface{})
+	out := make([]int, 0, len(scopes))
+	for _, scope := range scopes {
+		switch s := scope.(type) {
+		case map[string]interface{}:
+			if id, ok := s["id"].(float64); ok {
+				out = append(out, int(id))
+			}
+		case float64:
+			out = append(out, int(s))
+		}
+	}
+	sort.Ints(out)
+	return out
+}
@@ -0,0 +1,51 @@
+package authserverclientappschema
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchema(t *testing.T) {
+	t.Run("creates and returns a map of a ClientApp Schema", func(t *testing.T) {
+		provSchema := Schema()
+		assert.NotNil(t, provSchema["auth_server_id"])
+		assert.NotNil(t, provSchema["app_id"])
+		assert.NotNil(t, provSchema["scope_ids"])
+		assert.True(t, provSchema["name"].Computed)
+	})
+}
+
+func TestInflate(t *testing.T) {
+	t.Run("adding a client app", func(t *testing.T) {
+		out := Inflate(map[string]interface{}{
+			"app_id":    123,
+			"scope_ids": schema.NewSet(schema.HashInt, []interface{}{9, 4}),
+		})
+		if assert.NotNil(t, out.AppID) {
+			assert.Equal(t, int32(123), *out.AppID)
+		}
+		assert.Equal(t, []int32{4, 9}, out.Scopes)
+	})
+
+	// Every scope taken away has to be sent as an empty list, not left out,
+	// or the API leaves the scopes as they were.
+	t.Run("no scopes", func(t *testing.T) {
+		out := Inflate(map[string]interface{}{"scope_ids": schema.NewSet(schema.HashInt, nil)})
+		assert.Nil(t, out.AppID)
+		assert.NotNil(t, out.Scopes)
+		assert.Empty(t, out.Scopes)
+	})
+}
+
+func TestFlattenScopeIDs(t *testing.T) {
+	out := FlattenScopeIDs(map[string]interface{}{
+		"app_id": float64(123),
+		"scopes": []interface{}{
+			map[string]interface{}{"id": float64(9), "value": "write"},
+			map[string]interface{}{"id": float64(4), "value": "read"},
+		},
+	})
+	assert.Equal(t, []int{4, 9}, out)
+}
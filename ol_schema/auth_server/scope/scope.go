@@ -0,0 +1,60 @@
+package authserverscopeschema
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin/models"
+)
+
+// Schema returns a key/value map of the various fields that make up a Scope of a OneLogin AuthServer.
+func Schema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"auth_server_id": {
+			Type:        schema.TypeInt,
+			Required:    true,
+			ForceNew:    true,
+			Description: "ID of the authorization server the scope belongs to",
+		},
+		"scope_id": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "The scope's own ID, unique within its authorization server",
+		},
+		"value": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The scope as a client asks for it, for example read:invoices",
+		},
+		"description": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "What the scope grants, as shown to the user",
+		},
+	}
+}
+
+// Inflate takes a key/value map of interfaces and uses the fields to construct
+// the body the API takes to create or update a Scope. The auth server and
+// scope IDs are not part of it: they are in the path.
+func Inflate(s map[string]interface{}) models.Scope {
+	out := models.Scope{}
+	if value, ok := s["value"].(string); ok {
+		out.Value = &value
+	}
+	if description, ok := s["description"].(string); ok {
+		out.Description = &description
+	}
+	return out
+}
+
+// Flatten takes a scope as the API lists it and returns the fields the
+// resource records.
+func Flatten(scope map[string]interface{}) map[string]interface{} {
+	out := map[string]interface{}{"value": "", "description": ""}
+	if value, ok := scope["value"].(string); ok {
+		out["value"] = value
+	}
+	if description, ok := scope["description"].(string); ok {
+		out["description"] = description
+	}
+	return out
+}
@@ -0,0 +1,36 @@
+package authserverscopeschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchema(t *testing.T) {
+	t.Run("creates and returns a map of a Scope Schema", func(t *testing.T) {
+		provSchema := Schema()
+		assert.NotNil(t, provSchema["auth_server_id"])
+		assert.True(t, provSchema["scope_id"].Computed)
+		assert.NotNil(t, provSchema["value"])
+		assert.NotNil(t, provSchema["description"])
+		assert.True(t, provSchema["auth_server_id"].ForceNew)
+	})
+}
+
+func TestInflate(t *testing.T) {
+	out := Inflate(map[string]interface{}{"auth_server_id": 12, "value": "read:invoices", "description": "Read invoices"})
+
+	if assert.NotNil(t, out.Value) {
+		assert.Equal(t, "read:invoices", *out.Value)
+	}
+	if assert.NotNil(t, out.Description) {
+		assert.Equal(t, "Read invoices", *out.Description)
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	assert.Equal(t,
+		map[string]interface{}{"value": "read:invoices", "description": ""},
+		Flatten(map[string]interface{}{"id": float64(3), "value": "read:invoices", "description": nil}),
+	)
+}
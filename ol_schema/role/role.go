@@ -12,7 +12,8 @@ import (
 // endpoints, and so are the ones worth being able to leave un-refreshed.
 var MembershipAttrs = []string{"apps", "users", "admins"}
 
-func validMembershipAttr(val interface{}, key string) (warns []string, errs []error) {
+// ValidMembershipAttr accepts only the names in MembershipAttrs.
+func ValidMembershipAttr(val interface{}, key string) (warns []string, errs []error) {
 	return utils.OneOfValue(key, val, MembershipAttrs)
 }
 
@@ -21,10 +22,13 @@ func validMembershipAttr(val interface{}, key string) (warns []string, errs []er
 // The V2 API treats cursor and limit/page as mutually exclusive — supplying a
 // cursor alongside either is rejected — so callers clear Limit and Page once
 // they have a cursor from the After-Cursor response header.
+//
+// Name filters the roles list by name. The membership endpoints ignore it.
 type RoleQuery struct {
 	Limit  string `json:"limit,omitempty"`
 	Page   string `json:"page,omitempty"`
 	Cursor string `json:"cursor,omitempty"`
+	Name   string `json:"name,omitempty"`
 }
 
 // GetKeyValidators returns the validation functions for the query keys
@@ -33,6 +37,7 @@ func (r *RoleQuery) GetKeyValidators() map[string]func(interface{}) bool {
 		"limit":  validateString,
 		"page":   validateString,
 		"cursor": validateString,
+		"name":   validateString,
 	}
 }
 
@@ -78,7 +83,7 @@ func Schema() map[string]*schema.Schema {
 			Optional: true,
 			Elem: &schema.Schema{
 				Type:         schema.TypeString,
-				ValidateFunc: validMembershipAttr,
+				ValidateFunc: ValidMembershipAttr,
 			},
 			Description: "Membership attributes to leave un-refreshed: any of apps, users, admins. Their sub-endpoints are not queried during read, and state keeps whatever it already held. Use for membership managed outside Terraform, alongside a lifecycle ignore_changes block for the same attribute.",
 		},
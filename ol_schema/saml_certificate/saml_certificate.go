@@ -0,0 +1,180 @@
+package samlcertificateschema
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/onelogin/terraform-provider-onelogin/utils"
+)
+
+// DefaultKeySize and DefaultValidityYears are what a generated certificate
+// gets when the configuration does not say.
+const (
+	DefaultKeySize       = 2048
+	DefaultValidityYears = 3
+)
+
+// Schema returns a key/value map of the various fields that make up a OneLogin
+// SAML signing certificate.
+func Schema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The certificate's name in OneLogin",
+		},
+		"key_size": {
+			Type:          schema.TypeInt,
+			Optional:      true,
+			Computed:      true,
+			ForceNew:      true,
+			ConflictsWith: []string{"certificate_pem"},
+			ValidateFunc:  validKeySize,
+			Description:   "Size in bits of the RSA key OneLogin generates: 2048, 3072 or 4096. Defaults to 2048. Read from the certificate when it is uploaded",
+		},
+		"validity_years": {
+			Type:          schema.TypeInt,
+			Optional:      true,
+			Computed:      true,
+			ForceNew:      true,
+			ConflictsWith: []string{"certificate_pem"},
+			ValidateFunc:  validValidityYears,
+			Description:   "How many years a generated certificate is valid for, from 1 to 20. Defaults to 3",
+		},
+		"certificate_pem": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			ForceNew:      true,
+			ConflictsWith: []string{"key_size", "validity_years"},
+			RequiredWith:  []string{"private_key_pem"},
+			Description:   "PEM encoded certificate to upload instead of generating one",
+		},
+		"private_key_pem": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Sensitive:    true,
+			ForceNew:     true,
+			RequiredWith: []string{"certificate_pem"},
+			Description:  "PEM encoded private key of certificate_pem. OneLogin never returns it, but it is kept in state",
+		},
+		"certificate": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The certificate as OneLogin holds it, PEM encoded",
+		},
+		"fingerprint": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "SHA-1 fingerprint of the certificate, as OneLogin shows it",
+		},
+		"sha256_fingerprint": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "SHA-256 fingerprint of the certificate",
+		},
+		"not_before": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "When the certificate becomes valid, RFC 3339",
+		},
+		"expires_at": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "When the certificate expires, RFC 3339",
+		},
+	}
+}
+
+func validKeySize(val interface{}, key string) (warns []string, errs []error) {
+	v, ok := val.(int)
+	if !ok || (v != 2048 && v != 3072 && v != 4096) {
+		errs = append(errs, fmt.Errorf("%s must be one of 2048, 3072 or 4096, got: %v", key, val))
+	}
+	return
+}
+
+func validValidityYears(val interface{}, key string) (warns []string, errs []error) {
+	v, ok := val.(int)
+	if !ok || v < 1 || v > 20 {
+		errs = append(errs, fmt.Errorf("%s must be between 1 and 20, got: %v", key, val))
+	}
+	return
+}
+
+// Uploaded reports whether the configuration in s uploads a certificate
+// rather than having OneLogin generate one.
+func Uploaded(s map[string]interface{}) bool {
+	pem, _ := s["certificate_pem"].(string)
+	return pem != ""
+}
+
+// Inflate takes a key/value map of interfaces and uses the fields to construct
+// the body the API takes to create a certificate: to generate one, its name,
+// key size and validity; to upload one, its name, PEM and private key.
+func Inflate(s map[string]interface{}) map[string]interface{} {
+	out := map[string]interface{}{"name": s["name"]}
+	if Uploaded(s) {
+		out["value"] = s["certificate_pem"]
+		out["private_key"] = s["private_key_pem"]
+		return out
+	}
+	out["key_size"] = DefaultKeySize
+	if v, ok := s["key_size"].(int); ok && v != 0 {
+		out["key_size"] = v
+	}
+	out["validity_years"] = DefaultValidityYears
+	if v, ok := s["validity_years"].(int); ok && v != 0 {
+		out["validity_years"] = v
+	}
+	return out
+}
+
+// validityTolerance is how far a certificate's dates can be from a whole
+// number of years apart and still be read as valid for that many. Some issuers
+// count a year as 365 days, which over 20 years is 5 leap days short.
+const validityTolerance = 6 * 24 * time.Hour
+
+// validityYears is how many years a certificate valid from notBefore to
+// notAfter was generated for, or 0 when its dates are not a whole number of
+// years apart, as an uploaded certificate's need not be.
+func validityYears(notBefore, notAfter time.Time) int {
+	years := int(math.Round(notAfter.Sub(notBefore).Hours() / 24 / 365.25))
+	if years < 1 || years > 20 {
+		return 0
+	}
+	off := notAfter.Sub(notBefore.AddDate(years, 0, 0))
+	if off < -validityTolerance || off > validityTolerance {
+		return 0
+	}
+	return years
+}
+
+// Flatten takes a certificate as the API returns it and returns the fields the
+// resource records. Fingerprints and dates are read from the certificate
+// itself, and so is validity_years, since the API does not say how long a
+// certificate was generated for.
+func Flatten(cert map[string]interface{}) (map[string]interface{}, error) {
+	value, _ := cert["value"].(string)
+	parsed, err := utils.ParseCertificate(value)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]interface{}{
+		"name":               cert["name"],
+		"certificate":        value,
+		"fingerprint":        parsed.Fingerprint,
+		"sha256_fingerprint": parsed.SHA256Fingerprint,
+		"not_before":         parsed.NotBefore.Format(time.RFC3339),
+		"expires_at":         parsed.NotAfter.Format(time.RFC3339),
+	}
+	if parsed.KeySize != 0 {
+		out["key_size"] = parsed.KeySize
+	}
+	if years := validityYears(parsed.NotBefore, parsed.NotAfter); years != 0 {
+		out["validity_years"] = years
+	}
+	return out, nil
+}
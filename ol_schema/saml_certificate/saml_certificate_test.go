@@ -0,0 +1,102 @@
+package samlcertificateschema
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchema(t *testing.T) {
+	t.Run("creates and returns a map of a SAML certificate Schema", func(t *testing.T) {
+		provSchema := Schema()
+		assert.NotNil(t, provSchema["name"])
+		assert.True(t, provSchema["key_size"].ForceNew)
+		assert.True(t, provSchema["certificate_pem"].ForceNew)
+		assert.True(t, provSchema["private_key_pem"].Sensitive)
+		assert.True(t, provSchema["fingerprint"].Computed)
+		assert.True(t, provSchema["expires_at"].Computed)
+	})
+}
+
+func TestInflate(t *testing.T) {
+	tests := map[string]struct {
+		ResourceData   map[string]interface{}
+		ExpectedOutput map[string]interface{}
+	}{
+		"generates with the defaults": {
+			ResourceData:   map[string]interface{}{"name": "Signing", "key_size": 0, "validity_years": 0, "certificate_pem": ""},
+			ExpectedOutput: map[string]interface{}{"name": "Signing", "key_size": 2048, "validity_years": 3},
+		},
+		"generates as configured": {
+			ResourceData:   map[string]interface{}{"name": "Signing", "key_size": 4096, "validity_years": 5},
+			ExpectedOutput: map[string]interface{}{"name": "Signing", "key_size": 4096, "validity_years": 5},
+		},
+		"uploads": {
+			ResourceData:   map[string]interface{}{"name": "Signing", "key_size": 0, "certificate_pem": "CERT", "private_key_pem": "KEY"},
+			ExpectedOutput: map[string]interface{}{"name": "Signing", "value": "CERT", "private_key": "KEY"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.ExpectedOutput, Inflate(test.ResourceData))
+		})
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notBefore := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "Signing"},
+		NotBefore:    notBefore,
+		NotAfter:     notBefore.AddDate(3, 0, 0),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+
+	out, err := Flatten(map[string]interface{}{"id": float64(1), "name": "Signing", "value": value})
+	assert.NoError(t, err)
+	assert.Equal(t, "Signing", out["name"])
+	assert.Equal(t, value, out["certificate"])
+	assert.Equal(t, 2048, out["key_size"])
+	assert.Equal(t, "2025-01-01T00:00:00Z", out["not_before"])
+	assert.Equal(t, "2028-01-01T00:00:00Z", out["expires_at"])
+	assert.Equal(t, 3, out["validity_years"])
+	assert.NotEmpty(t, out["fingerprint"])
+
+	_, err = Flatten(map[string]interface{}{"name": "Signing", "value": "not a certificate"})
+	assert.Error(t, err)
+}
+
+func TestValidityYears(t *testing.T) {
+	notBefore := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	for name, tt := range map[string]struct {
+		notAfter time.Time
+		want     int
+	}{
+		"whole years":               {notBefore.AddDate(3, 0, 0), 3},
+		"years of 365 days":         {notBefore.Add(20 * 365 * 24 * time.Hour), 20},
+		"a second short":            {notBefore.AddDate(1, 0, 0).Add(-time.Second), 1},
+		"not a whole number":        {notBefore.AddDate(0, 0, 825), 0},
+		"less than a year":          {notBefore.AddDate(0, 3, 0), 0},
+		"longer than OneLogin does": {notBefore.AddDate(30, 0, 0), 0},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, validityYears(notBefore, tt.notAfter))
+		})
+	}
+}
@@ -0,0 +1,319 @@
+package onelogin
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
+
+	"github.com/onelogin/terraform-provider-onelogin/utils"
+)
+
+// A rule catalogue is what OneLogin lists as allowed in the conditions and
+// actions of an app's rules, or of user mappings: the sources a condition can
+// check, the operators each source takes, the actions, and, for conditions and
+// actions limited to a set of values, those values. The schemas take any
+// string for each of them, because the catalogue differs from one connector to
+// the next; these are the lists to look them up in.
+
+// catalogueEntry is one entry in a catalogue list: a name to show and the
+// value a condition or action takes.
+type catalogueEntry struct {
+	name, value string
+}
+
+// ruleCatalogue fetches the parts of one catalogue. Each returns the list as
+// the API does, and gives up when ctx does.
+type ruleCatalogue struct {
+	conditions      func(ctx context.Context) (interface{}, error)
+	operators       func(ctx context.Context, condition string) (interface{}, error)
+	conditionValues func(ctx context.Context, condition string) (interface{}, error)
+	actions         func(ctx context.Context) (interface{}, error)
+	actionValues    func(ctx context.Context, action string) (interface{}, error)
+}
+
+// parseCatalogue reads a catalogue list, [{"name": ..., "value": ...}, ...].
+// Values are strings in conditions and actions, so a numeric one, a role ID for
+// instance, is read as the string a rule would carry.
+func parseCatalogue(result interface{}) ([]catalogueEntry, error) {
+	if result == nil {
+		return []catalogueEntry{}, nil
+	}
+	items, ok := result.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected list response: want a JSON array, got %T", result)
+	}
+	entries := make([]catalogueEntry, 0, len(items))
+	for _, item := range items {
+		o, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := o["name"].(string)
+		entries = append(entries, catalogueEntry{name: name, value: catalogueValue(o["value"])})
+	}
+	return entries, nil
+}
+
+func catalogueValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+func catalogueValues(entries []catalogueEntry) []string {
+	values := make([]string, len(entries))
+	for i, e := range entries {
+		values[i] = e.value
+	}
+	return values
+}
+
+// catalogueListSchema is the computed list a catalogue data source sets.
+func catalogueListSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: description,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name":  {Type: schema.TypeString, Computed: true},
+				"value": {Type: schema.TypeString, Computed: true},
+			},
+		},
+	}
+}
+
+// catalogueDataSource returns a data source that lists one part of a
+// catalogue as attr. list fetches it for the configuration in d, and returns
+// the ID the data source is given.
+func catalogueDataSource(attr, description string, args map[string]*schema.Schema, list func(ctx context.Context, client *onelogin.OneloginSDK, d *schema.ResourceData) (interface{}, string, error)) *schema.Resource {
+	s := map[string]*schema.Schema{attr: catalogueListSchema(description)}
+	for k, v := range args {
+		s[k] = v
+	}
+	return &schema.Resource{
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			client := m.(*onelogin.OneloginSDK)
+
+			tflog.Info(ctx, "[READ] Reading rule catalogue", map[string]interface{}{
+				"list": attr,
+			})
+
+			result, id, err := list(ctx, client, d)
+			if err != nil {
+				return utils.HandleAPIError(ctx, err, utils.ErrorCategoryRead, "Rule catalogue", id)
+			}
+			entries, err := parseCatalogue(result)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+
+			flat := make([]map[string]interface{}, len(entries))
+			for i, e := range entries {
+				flat[i] = map[string]interface{}{"name": e.name, "value": e.value}
+			}
+			d.SetId(id)
+			if err := d.Set(attr, flat); err != nil {
+				return diag.FromErr(err)
+			}
+			return nil
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(5 * time.Minute),
+		},
+		Schema: s,
+	}
+}
+
+// configuredCondition is a condition as configured. An empty field is one not
+// known yet at plan time, or not set.
+type configuredCondition struct {
+	source, operator, value string
+}
+
+// configuredAction is an action as configured. values is nil when they are not
+// known yet, and checkValues says whether they are values from the action's
+// catalogue at all rather than, say, attribute names an expression reads.
+type configuredAction struct {
+	action      string
+	values      []string
+	checkValues bool
+}
+
+// checkCatalogue checks conditions and actions against cat and returns what is
+// wrong with them, one problem to an entry, naming the block it is in. Lists
+// are fetched once each, as they are first needed.
+func checkCatalogue(ctx context.Context, cat ruleCatalogue, conditions []configuredCondition, actions []configuredAction) ([]string, error) {
+	cache := map[string][]catalogueEntry{}
+	fetch := func(key string, call func() (interface{}, error)) ([]catalogueEntry, error) {
+		if entries, ok := cache[key]; ok {
+			return entries, nil
+		}
+		result, err := call()
+		if err != nil {
+			// A source or action with no list of values has none to fetch.
+			if utils.IsNotFoundError(err) && strings.HasSuffix(key, "/values") {
+				result, err = nil, nil
+			} else {
+				return nil, fmt.Errorf("listing %s: %w", key, err)
+			}
+		}
+		entries, err := parseCatalogue(result)
+		if err != nil {
+			return nil, err
+		}
+		cache[key] = entries
+		return entries, nil
+	}
+	oneOf := func(what, value string, entries []catalogueEntry) string {
+		for _, e := range entries {
+			if e.value == value {
+				return ""
+			}
+		}
+		return fmt.Sprintf("%s %q is not one of: %s", what, value, strings.Join(catalogueValues(entries), ", "))
+	}
+
+	var problems []string
+	report := func(block string, i int, problem string) {
+		if problem != "" {
+			problems = append(problems, fmt.Sprintf("%s.%d: %s", block, i, problem))
+		}
+	}
+
+	if len(conditions) > 0 {
+		sources, err := fetch("conditions", func() (interface{}, error) { return cat.conditions(ctx) })
+		if err != nil {
+			return nil, err
+		}
+		for i, c := range conditions {
+			if c.source == "" {
+				continue
+			}
+			if p := oneOf("source", c.source, sources); p != "" {
+				report("conditions", i, p)
+				continue
+			}
+			if c.operator != "" {
+				operators, err := fetch("conditions/"+c.source+"/operators", func() (interface{}, error) { return cat.operators(ctx, c.source) })
+				if err != nil {
+					return nil, err
+				}
+				report("conditions", i, oneOf("operator", c.operator, operators))
+			}
+			if c.value != "" {
+				values, err := fetch("conditions/"+c.source+"/values", func() (interface{}, error) { return cat.conditionValues(ctx, c.source) })
+				if err != nil {
+					return nil, err
+				}
+				if len(values) > 0 {
+					report("conditions", i, oneOf("value", c.value, values))
+				}
+			}
+		}
+	}
+
+	if len(actions) > 0 {
+		known, err := fetch("actions", func() (interface{}, error) { return cat.actions(ctx) })
+		if err != nil {
+			return nil, err
+		}
+		for i, a := range actions {
+			if a.action == "" {
+				continue
+			}
+			if p := oneOf("action", a.action, known); p != "" {
+				report("actions", i, p)
+				continue
+			}
+			if !a.checkValues || len(a.values) == 0 {
+				continue
+			}
+			values, err := fetch("actions/"+a.action+"/values", func() (interface{}, error) { return cat.actionValues(ctx, a.action) })
+			if err != nil {
+				return nil, err
+			}
+			if len(values) == 0 {
+				continue
+			}
+			for _, v := range a.values {
+				report("actions", i, oneOf("value", v, values))
+			}
+		}
+	}
+	return problems, nil
+}
+
+// validateAgainstCatalogue runs checkCatalogue for a CustomizeDiff. A
+// catalogue that cannot be read is logged and the plan goes ahead: the API
+// checks the rule again when it is applied, and a plan should not fail for
+// want of a list it only reads to be helpful.
+func validateAgainstCatalogue(ctx context.Context, noun string, cat ruleCatalogue, conditions []configuredCondition, actions []configuredAction) error {
+	problems, err := checkCatalogue(ctx, cat, conditions, actions)
+	if err != nil {
+		tflog.Warn(ctx, "[PLAN] Could not read the rule catalogue; leaving the conditions and actions for the API to check", map[string]interface{}{
+			"rules": noun,
+			"error": err.Error(),
+		})
+		return nil
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%s does not allow:\n  %s", noun, strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// knownString reads a string from raw configuration: ok is false if it is
+// null or not known yet.
+func knownString(v cty.Value) (string, bool) {
+	if v.IsNull() || !v.IsKnown() || v.Type() != cty.String {
+		return "", false
+	}
+	return v.AsString(), true
+}
+
+// knownStrings reads a list or set of strings from raw configuration: ok is
+// false if it, or any of it, is not known yet.
+func knownStrings(v cty.Value) ([]string, bool) {
+	if v.IsNull() || !v.IsWhollyKnown() || !(v.CanIterateElements()) {
+		return nil, false
+	}
+	var out []string
+	for it := v.ElementIterator(); it.Next(); {
+		_, e := it.Element()
+		s, ok := knownString(e)
+		if !ok {
+			return nil, false
+		}
+		out = append(out, s)
+	}
+	return out, true
+}
+
+// configuredBlocks returns the elements of a list of blocks in raw
+// configuration, or nothing if the list is not known yet.
+func configuredBlocks(v cty.Value) []cty.Value {
+	if v.IsNull() || !v.IsKnown() || !v.CanIterateElements() {
+		return nil
+	}
+	var out []cty.Value
+	for it := v.ElementIterator(); it.Next(); {
+		_, e := it.Element()
+		out = append(out, e)
+	}
+	return out
+}
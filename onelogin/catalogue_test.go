@@ -0,0 +1,128 @@
+package onelogin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// staticCatalogue serves a catalogue from fixed lists, and counts the requests
+// made of it.
+func staticCatalogue(calls *int) ruleCatalogue {
+	list := func(values ...interface{}) []interface{} {
+		out := make([]interface{}, len(values))
+		for i, v := range values {
+			out[i] = map[string]interface{}{"name": fmt.Sprint(v), "value": v}
+		}
+		return out
+	}
+	operators := map[string][]interface{}{
+		"last_login": list(">", "<"),
+		"has_role":   list("ri", "!ri"),
+	}
+	return ruleCatalogue{
+		conditions: func(context.Context) (interface{}, error) {
+			*calls++
+			return list("last_login", "has_role"), nil
+		},
+		operators: func(_ context.Context, condition string) (interface{}, error) {
+			*calls++
+			return operators[condition], nil
+		},
+		conditionValues: func(_ context.Context, condition string) (interface{}, error) {
+			*calls++
+			if condition == "has_role" {
+				return list(float64(123456), float64(789)), nil
+			}
+			return nil, errors.New("status: 404, body: not found")
+		},
+		actions: func(context.Context) (interface{}, error) {
+			*calls++
+			return list("set_role", "set_status"), nil
+		},
+		actionValues: func(_ context.Context, action string) (interface{}, error) {
+			*calls++
+			if action == "set_status" {
+				return list("1", "3"), nil
+			}
+			return []interface{}{}, nil
+		},
+	}
+}
+
+func TestCheckCatalogue(t *testing.T) {
+	for name, tt := range map[string]struct {
+		conditions []configuredCondition
+		actions    []configuredAction
+		problems   []string
+	}{
+		"all allowed": {
+			conditions: []configuredCondition{{"last_login", ">", "90"}, {"has_role", "ri", "123456"}},
+			actions:    []configuredAction{{"set_status", []string{"3"}, true}, {"set_role", []string{"anything"}, true}},
+		},
+		"nothing known yet": {
+			conditions: []configuredCondition{{}},
+			actions:    []configuredAction{{}},
+		},
+		"a mistyped source": {
+			conditions: []configuredCondition{{"last_login", ">", "90"}, {"has_rol", "ri", "123456"}},
+			problems:   []string{`conditions.1: source "has_rol" is not one of: last_login, has_role`},
+		},
+		"another source's operator": {
+			conditions: []configuredCondition{{"has_role", ">", ""}},
+			problems:   []string{`conditions.0: operator ">" is not one of: ri, !ri`},
+		},
+		"a value not in the list": {
+			conditions: []configuredCondition{{"has_role", "ri", "42"}},
+			problems:   []string{`conditions.0: value "42" is not one of: 123456, 789`},
+		},
+		"an unknown action and a bad value": {
+			actions: []configuredAction{{"set_rol", []string{"x"}, true}, {"set_status", []string{"1", "2"}, true}},
+			problems: []string{
+				`actions.0: action "set_rol" is not one of: set_role, set_status`,
+				`actions.1: value "2" is not one of: 1, 3`,
+			},
+		},
+		"values an expression reads": {
+			actions: []configuredAction{{"set_status", []string{"member_of"}, false}},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			problems, err := checkCatalogue(context.Background(), staticCatalogue(&calls), tt.conditions, tt.actions)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if fmt.Sprint(problems) != fmt.Sprint(tt.problems) {
+				t.Fatalf("expected problems %q, got %q", tt.problems, problems)
+			}
+		})
+	}
+
+	t.Run("each list is fetched once", func(t *testing.T) {
+		calls := 0
+		conditions := []configuredCondition{{"last_login", ">", "1"}, {"last_login", "<", "2"}, {"last_login", ">", "3"}}
+		if _, err := checkCatalogue(context.Background(), staticCatalogue(&calls), conditions, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// The conditions, last_login's operators and its (missing) values.
+		if calls != 3 {
+			t.Fatalf("expected 3 requests, got %d", calls)
+		}
+	})
+
+	t.Run("a catalogue that cannot be read", func(t *testing.T) {
+		calls := 0
+		cat := staticCatalogue(&calls)
+		cat.conditions = func(context.Context) (interface{}, error) { return nil, errors.New("status: 403, body: forbidden") }
+		_, err := checkCatalogue(context.Background(), cat, []configuredCondition{{"last_login", "", ""}}, nil)
+		if err == nil || !strings.Contains(err.Error(), "listing conditions") {
+			t.Fatalf("expected the failed list to be named, got %v", err)
+		}
+		if err := validateAgainstCatalogue(context.Background(), "The rules", cat, []configuredCondition{{"last_login", "", ""}}, nil); err != nil {
+			t.Fatalf("expected the plan to go ahead without the catalogue, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package onelogin
+
+import (
+	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
+	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin/api"
+	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin/authentication"
+
+	"github.com/onelogin/terraform-provider-onelogin/apiclient"
+)
+
+// newOneloginSDK builds an SDK client that belongs to one provider instance.
+//
+// onelogin.NewOneloginSDK is not used because it reads its credentials, URL and
+// timeout from ONELOGIN_* environment variables, and the environment is shared
+// by every provider block in the plugin process. Instead the SDK's api.Client is
+// assembled here around an HTTP client from apiclient, which holds this
+// instance's credentials and attaches its own access token to every request.
+// The SDK's authenticator is never asked for a token, so it never looks at the
+// environment either.
+//
+// subdomain is the provider's subdomain argument, empty when it is not set.
+func newOneloginSDK(cfg apiclient.Config, subdomain string) (*onelogin.OneloginSDK, error) {
+	// Normalised here as well as inside NewHTTPClient, because the SDK is
+	// handed the URL separately and has to see the same one.
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	httpClient, err := apiclient.NewHTTPClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// The authenticator still wants a subdomain to be constructed, even
+	// though it is never used to fetch anything.
+	subdomain, err = tenantSubdomain(cfg.URL, subdomain)
+	if err != nil {
+		return nil, err
+	}
+
+	return &onelogin.OneloginSDK{
+		Client: &api.Client{
+			HttpClient: httpClient,
+			Auth:       authentication.NewAuthenticator(subdomain),
+			OLdomain:   cfg.URL,
+			Timeout:    httpClient.Timeout,
+		},
+	}, nil
+}
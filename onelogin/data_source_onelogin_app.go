@@ -0,0 +1,178 @@
+package onelogin
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
+	appschema "github.com/onelogin/terraform-provider-onelogin/ol_schema/app"
+	appconfigurationschema "github.com/onelogin/terraform-provider-onelogin/ol_schema/app/configuration"
+	appparametersschema "github.com/onelogin/terraform-provider-onelogin/ol_schema/app/parameters"
+	appprovisioningschema "github.com/onelogin/terraform-provider-onelogin/ol_schema/app/provisioning"
+	appssoschema "github.com/onelogin/terraform-provider-onelogin/ol_schema/app/sso"
+	"github.com/onelogin/terraform-provider-onelogin/utils"
+)
+
+// dataSourceApp returns the onelogin_app data source, which reads one app of
+// any kind by ID or by exact name, along with the details a service provider
+// needs to trust it.
+func dataSourceApp() *schema.Resource {
+	appSchema := utils.ComputedSchema(appschema.Schema())
+	appSchema["id"] = &schema.Schema{
+		Type:         schema.TypeInt,
+		Optional:     true,
+		Computed:     true,
+		ExactlyOneOf: []string{"id", "name"},
+		Description:  "ID of the app to read",
+	}
+	appSchema["name"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		ExactlyOneOf: []string{"id", "name"},
+		Description:  "Name of the app to read. It must match exactly, and only one app may have it",
+	}
+	appSchema["configuration"] = &schema.Schema{
+		Type:     schema.TypeMap,
+		Computed: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+	}
+	for _, field := range appSSOFields {
+		appSchema[field.attr] = &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: field.description,
+		}
+	}
+	appSchema["certificate"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The X.509 certificate a SAML app signs with, PEM encoded",
+	}
+	appSchema["certificate_name"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The name of the certificate a SAML app signs with",
+	}
+
+	return &schema.Resource{
+		ReadContext: dataSourceAppRead,
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(20 * time.Minute),
+		},
+		Schema: appSchema,
+	}
+}
+
+// appSSOFields are the sso attributes the onelogin_app data source exposes at
+// its top level. Which are set depends on the app: the URLs and issuer for a
+// SAML app, client_id for an OIDC one.
+var appSSOFields = []struct {
+	attr, description string
+}{
+	{"metadata_url", "URL of a SAML app's IdP metadata"},
+	{"acs_url", "The SAML 2.0 endpoint a SAML app's identity provider receives AuthnRequests on"},
+	{"sls_url", "The SAML 2.0 single logout endpoint of a SAML app"},
+	{"issuer", "The issuer URL of a SAML app's identity provider"},
+	{"client_id", "The client ID of an OIDC app"},
+}
+
+func dataSourceAppRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*onelogin.OneloginSDK)
+
+	aid := d.Get("id").(int)
+	if aid == 0 {
+		name := d.Get("name").(string)
+		tflog.Info(ctx, "[READ] Looking up OneLogin app", map[string]interface{}{
+			"name": name,
+		})
+
+		matches, err := appLookup()(ctx, client, "name", name)
+		if err != nil {
+			return utils.HandleAPIError(ctx, err, utils.ErrorCategoryRead, "Apps", "")
+		}
+		switch len(matches) {
+		case 0:
+			return diag.Errorf("no app with name %q was found", name)
+		case 1:
+			aid, _ = strconv.Atoi(objectID(matches[0]))
+		default:
+			ids := make([]string, len(matches))
+			for i, match := range matches {
+				ids[i] = objectID(match)
+			}
+			sort.Strings(ids)
+			return diag.Errorf("%d apps have name %q (IDs %s); read the one you mean by its id instead", len(matches), name, strings.Join(ids, ", "))
+		}
+	}
+
+	tflog.Info(ctx, "[READ] Reading OneLogin app", map[string]interface{}{
+		"id": aid,
+	})
+
+	// The list endpoint leaves out configuration, parameters and sso, so an
+	// app found by name is read again in full.
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetAppByID(aid, nil) })
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return diag.Errorf("no app with ID %d was found", aid)
+		}
+		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryRead, "App", strconv.Itoa(aid))
+	}
+	if result == nil {
+		return diag.Errorf("no app with ID %d was found", aid)
+	}
+	appMap, ok := result.(map[string]interface{})
+	if !ok {
+		return diag.Errorf("failed to parse app response")
+	}
+
+	d.SetId(strconv.Itoa(aid))
+
+	basicFields := []string{
+		"name", "visible", "description", "notes", "icon_url",
+		"auth_method", "policy_id", "allow_assumed_signin", "tab_id",
+		"brand_id", "connector_id", "created_at", "updated_at",
+	}
+	utils.SetResourceFields(d, appMap, basicFields)
+
+	// Unlike the app resources, every parameter and configuration value is
+	// recorded: there is no configuration here to narrow them to.
+	if params, ok := appMap["parameters"].(map[string]interface{}); ok {
+		if err := d.Set("parameters", appparametersschema.FlattenV4(params)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if provData, ok := appMap["provisioning"].(map[string]interface{}); ok {
+		if err := d.Set("provisioning", appprovisioningschema.FlattenMap(provData)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	configData, _ := appMap["configuration"].(map[string]interface{})
+	if err := d.Set("configuration", appconfigurationschema.Flatten(configData)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	ssoData, _ := appMap["sso"].(map[string]interface{})
+	sso := appssoschema.Flatten(ssoData)
+	for _, field := range appSSOFields {
+		if err := d.Set(field.attr, sso[field.attr]); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	cert := appssoschema.FlattenCertMap(ssoData)
+	if err := d.Set("certificate", cert["value"]); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("certificate_name", cert["name"]); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
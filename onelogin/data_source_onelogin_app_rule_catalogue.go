@@ -0,0 +1,131 @@
+package onelogin
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
+
+	appruleactionsschema "github.com/onelogin/terraform-provider-onelogin/ol_schema/rules/actions"
+	"github.com/onelogin/terraform-provider-onelogin/utils"
+)
+
+// appRuleCatalogue is the catalogue of app appID's rules. Each call gives up
+// when ctx does, so a read or plan is held to its timeout.
+func appRuleCatalogue(client *onelogin.OneloginSDK, appID int) ruleCatalogue {
+	return ruleCatalogue{
+		conditions: func(ctx context.Context) (interface{}, error) {
+			return utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetAppRuleConditions(appID, nil) })
+		},
+		operators: func(ctx context.Context, condition string) (interface{}, error) {
+			return utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetAppRuleConditionOperators(appID, condition, nil) })
+		},
+		conditionValues: func(ctx context.Context, condition string) (interface{}, error) {
+			return utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetAppRuleConditionValues(appID, condition, nil) })
+		},
+		actions: func(ctx context.Context) (interface{}, error) {
+			return utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetAppRuleActions(appID, nil) })
+		},
+		actionValues: func(ctx context.Context, action string) (interface{}, error) {
+			return utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetAppRuleActionValues(appID, action, nil) })
+		},
+	}
+}
+
+// appRuleCatalogueArgs are the arguments of an app rule catalogue data source:
+// app_id, and key, if set, described by description.
+func appRuleCatalogueArgs(key, description string) map[string]*schema.Schema {
+	args := map[string]*schema.Schema{
+		"app_id": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "ID of the app whose rules the list is for. What rules can do differs from one connector to the next",
+		},
+	}
+	if key != "" {
+		args[key] = &schema.Schema{Type: schema.TypeString, Required: true, Description: description}
+	}
+	return args
+}
+
+// appRuleCatalogueList wraps a fetch of part of an app's rule catalogue for
+// catalogueDataSource. key, if set, is the argument naming the condition or
+// action the list belongs to; the ID is the app's ID, then the list, then the
+// argument.
+func appRuleCatalogueList(list, key string, fetch func(ctx context.Context, cat ruleCatalogue, arg string) (interface{}, error)) func(context.Context, *onelogin.OneloginSDK, *schema.ResourceData) (interface{}, string, error) {
+	return func(ctx context.Context, client *onelogin.OneloginSDK, d *schema.ResourceData) (interface{}, string, error) {
+		appIDStr := d.Get("app_id").(string)
+		appID, err := strconv.Atoi(appIDStr)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid app_id %q: %v", appIDStr, err)
+		}
+		id := appIDStr + ":" + list
+		var arg string
+		if key != "" {
+			arg = d.Get(key).(string)
+			// onelogin_app_rules writes an action without an expression with
+			// a suffix, so the value may have been copied from there.
+			if key == "action" {
+				arg = strings.TrimSuffix(arg, appruleactionsschema.NO_EXPRESSION_SUFFIX)
+			}
+			id += ":" + arg
+		}
+		result, err := fetch(ctx, appRuleCatalogue(client, appID), arg)
+		return result, id, err
+	}
+}
+
+// dataSourceAppRuleConditions returns the onelogin_app_rule_conditions data
+// source, which lists the sources an app's rule conditions can check.
+func dataSourceAppRuleConditions() *schema.Resource {
+	return catalogueDataSource("conditions", "The sources a condition can check",
+		appRuleCatalogueArgs("", ""),
+		appRuleCatalogueList("conditions", "", func(ctx context.Context, cat ruleCatalogue, _ string) (interface{}, error) {
+			return cat.conditions(ctx)
+		}))
+}
+
+// dataSourceAppRuleConditionOperators returns the
+// onelogin_app_rule_condition_operators data source, which lists the
+// operators one condition source takes.
+func dataSourceAppRuleConditionOperators() *schema.Resource {
+	return catalogueDataSource("operators", "The operators the condition takes",
+		appRuleCatalogueArgs("condition", "The condition's source, a value from onelogin_app_rule_conditions"),
+		appRuleCatalogueList("operators", "condition", func(ctx context.Context, cat ruleCatalogue, condition string) (interface{}, error) {
+			return cat.operators(ctx, condition)
+		}))
+}
+
+// dataSourceAppRuleConditionValues returns the
+// onelogin_app_rule_condition_values data source, which lists the values one
+// condition source is limited to.
+func dataSourceAppRuleConditionValues() *schema.Resource {
+	return catalogueDataSource("values", "The values the condition can compare against. Empty for a condition that takes any value",
+		appRuleCatalogueArgs("condition", "The condition's source, a value from onelogin_app_rule_conditions"),
+		appRuleCatalogueList("condition_values", "condition", func(ctx context.Context, cat ruleCatalogue, condition string) (interface{}, error) {
+			return cat.conditionValues(ctx, condition)
+		}))
+}
+
+// dataSourceAppRuleActions returns the onelogin_app_rule_actions data source,
+// which lists the actions an app's rules can take.
+func dataSourceAppRuleActions() *schema.Resource {
+	return catalogueDataSource("actions", "The actions a rule can take",
+		appRuleCatalogueArgs("", ""),
+		appRuleCatalogueList("actions", "", func(ctx context.Context, cat ruleCatalogue, _ string) (interface{}, error) {
+			return cat.actions(ctx)
+		}))
+}
+
+// dataSourceAppRuleActionValues returns the onelogin_app_rule_action_values
+// data source, which lists the values one action is limited to.
+func dataSourceAppRuleActionValues() *schema.Resource {
+	return catalogueDataSource("values", "The values the action can set. Empty for an action that takes any value",
+		appRuleCatalogueArgs("action", "The action, a value from onelogin_app_rule_actions"),
+		appRuleCatalogueList("action_values", "action", func(ctx context.Context, cat ruleCatalogue, action string) (interface{}, error) {
+			return cat.actionValues(ctx, action)
+		}))
+}
@@ -0,0 +1,106 @@
+package onelogin
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/onelogin/terraform-provider-onelogin/fakeapi"
+)
+
+func TestDataSourceAppRuleCatalogue(t *testing.T) {
+	srv := fakeapi.NewServer()
+	defer srv.Close()
+	meta := fakeAPIMeta(t, srv)
+
+	app := srv.Seed(fakeapi.Apps, map[string]interface{}{"name": "Rules", "connector_id": float64(50534)})
+	role := srv.Seed(fakeapi.Roles, map[string]interface{}{"name": "Admins"})
+
+	values := func(d *schema.ResourceData, attr string) []string {
+		var out []string
+		for _, e := range d.Get(attr).([]interface{}) {
+			out = append(out, e.(map[string]interface{})["value"].(string))
+		}
+		return out
+	}
+
+	for name, tt := range map[string]struct {
+		r      *schema.Resource
+		config map[string]interface{}
+		attr   string
+		want   []string
+	}{
+		"conditions":           {dataSourceAppRuleConditions(), map[string]interface{}{"app_id": app}, "conditions", []string{"last_login", "has_role", "email", "member_of"}},
+		"operators":            {dataSourceAppRuleConditionOperators(), map[string]interface{}{"app_id": app, "condition": "has_role"}, "operators", []string{"ri", "!ri"}},
+		"condition values":     {dataSourceAppRuleConditionValues(), map[string]interface{}{"app_id": app, "condition": "has_role"}, "values", []string{role}},
+		"actions":              {dataSourceAppRuleActions(), map[string]interface{}{"app_id": app}, "actions", []string{"set_amazonusername", "set_role"}},
+		"action values":        {dataSourceAppRuleActionValues(), map[string]interface{}{"app_id": app, "action": "set_role"}, "values", []string{role}},
+		"action without value": {dataSourceAppRuleActionValues(), map[string]interface{}{"app_id": app, "action": "set_role_from_existing"}, "values", []string{role}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			d, err := readDataSource(t, tt.r, meta, tt.config)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := values(d, tt.attr); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Fatalf("expected %s %v, got %v", tt.attr, tt.want, got)
+			}
+			if d.Id() == "" {
+				t.Fatal("expected an ID to be set")
+			}
+		})
+	}
+
+	t.Run("a condition that does not exist", func(t *testing.T) {
+		if _, err := readDataSource(t, dataSourceAppRuleConditionOperators(), meta, map[string]interface{}{"app_id": app, "condition": "nothing"}); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
+
+func TestAppRuleCustomizeDiff(t *testing.T) {
+	srv := fakeapi.NewServer()
+	defer srv.Close()
+	meta := fakeAPIMeta(t, srv)
+
+	app := srv.Seed(fakeapi.Apps, map[string]interface{}{"name": "Rules", "connector_id": float64(50534)})
+	role := srv.Seed(fakeapi.Roles, map[string]interface{}{"name": "Admins"})
+
+	rule := func(source, operator, value, action string) map[string]interface{} {
+		return map[string]interface{}{
+			"app_id": app, "name": "rule", "match": "all",
+			"conditions": []interface{}{map[string]interface{}{"source": source, "operator": operator, "value": value}},
+			"actions": []interface{}{map[string]interface{}{
+				"action": action, "expression": ".*", "value": []interface{}{"member_of"},
+			}},
+		}
+	}
+
+	for name, tt := range map[string]struct {
+		config  map[string]interface{}
+		unknown []string
+		err     string
+	}{
+		"allowed":           {config: rule("has_role", "ri", role, "set_amazonusername")},
+		"mistyped source":   {config: rule("has_rol", "ri", role, "set_amazonusername"), err: `conditions.0: source "has_rol" is not one of: last_login, has_role, email, member_of`},
+		"wrong operator":    {config: rule("last_login", "ri", "90", "set_amazonusername"), err: `conditions.0: operator "ri" is not one of: >, <`},
+		"role not there":    {config: rule("has_role", "ri", "999999", "set_amazonusername"), err: `conditions.0: value "999999"`},
+		"mistyped action":   {config: rule("last_login", ">", "90", "set_amazonuser"), err: `actions.0: action "set_amazonuser"`},
+		"app not known yet": {config: rule("has_rol", "ri", role, "set_amazonusername"), unknown: []string{"app_id"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := planResource(t, AppRules(), meta, tt.config, tt.unknown...)
+			if tt.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("expected an error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
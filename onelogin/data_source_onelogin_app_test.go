@@ -0,0 +1,193 @@
+package onelogin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+
+	"github.com/onelogin/terraform-provider-onelogin/fakeapi"
+)
+
+func TestAccDataSourceApp(t *testing.T) {
+	name := newFixtureSuffix() + "-app-ds"
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { TestAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAppConfig(name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.onelogin_app.by_name", "id", "onelogin_saml_apps.saml", "id"),
+					resource.TestCheckResourceAttrPair("data.onelogin_app.by_name", "metadata_url", "onelogin_saml_apps.saml", "sso.metadata_url"),
+					resource.TestCheckResourceAttrSet("data.onelogin_app.by_name", "certificate"),
+					resource.TestCheckResourceAttr("data.onelogin_apps.saml", "apps.#", "1"),
+					resource.TestCheckResourceAttrPair("data.onelogin_apps.saml", "ids.0", "onelogin_saml_apps.saml", "id"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceAppConfig(name string) string {
+	return fmt.Sprintf(`
+resource "onelogin_saml_apps" "saml" {
+  name         = %q
+  connector_id = 50534
+
+  configuration = {
+    signature_algorithm = "SHA-256"
+  }
+}
+
+data "onelogin_app" "by_name" {
+  name = onelogin_saml_apps.saml.name
+}
+
+data "onelogin_apps" "saml" {
+  name        = onelogin_saml_apps.saml.name
+  auth_method = 2
+}
+`, name)
+}
+
+func TestDataSourceAppRead(t *testing.T) {
+	srv := fakeapi.NewServer()
+	defer srv.Close()
+	meta := fakeAPIMeta(t, srv)
+
+	saml := srv.Seed(fakeapi.Apps, map[string]interface{}{
+		"name": "Payroll", "connector_id": float64(50534), "auth_method": float64(authMethodSAML),
+		"configuration": map[string]interface{}{"signature_algorithm": "SHA-256"},
+		"parameters": map[string]interface{}{
+			"email": map[string]interface{}{"id": float64(1), "label": "Email", "include_in_saml_assertion": true},
+		},
+		"sso": map[string]interface{}{
+			"metadata_url": "https://example.onelogin.com/saml/metadata/1",
+			"acs_url":      "https://example.onelogin.com/trust/saml2/http-post/sso/1",
+			"issuer":       "https://app.onelogin.com/saml/metadata/1",
+			"certificate": map[string]interface{}{
+				"id": float64(9), "name": "Standard OneLogin Certificate", "value": "-----BEGIN CERTIFICATE-----",
+			},
+		},
+	})
+	oidc := srv.Seed(fakeapi.Apps, map[string]interface{}{
+		"name": "Portal", "connector_id": float64(108419), "auth_method": float64(authMethodOIDC),
+		"sso": map[string]interface{}{"client_id": "abc123", "client_secret": "shh"},
+	})
+	oidcID, _ := strconv.Atoi(oidc)
+	first := srv.Seed(fakeapi.Apps, map[string]interface{}{"name": "Duplicate", "connector_id": float64(1)})
+	second := srv.Seed(fakeapi.Apps, map[string]interface{}{"name": "Duplicate", "connector_id": float64(1)})
+
+	t.Run("SAML app by name", func(t *testing.T) {
+		d, err := readDataSource(t, dataSourceApp(), meta, map[string]interface{}{"name": "Payroll"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if d.Id() != saml {
+			t.Fatalf("expected app %s, got %s", saml, d.Id())
+		}
+		for attr, want := range map[string]string{
+			"metadata_url":                      "https://example.onelogin.com/saml/metadata/1",
+			"acs_url":                           "https://example.onelogin.com/trust/saml2/http-post/sso/1",
+			"issuer":                            "https://app.onelogin.com/saml/metadata/1",
+			"certificate":                       "-----BEGIN CERTIFICATE-----",
+			"certificate_name":                  "Standard OneLogin Certificate",
+			"client_id":                         "",
+			"connector_id":                      "50534",
+			"configuration.signature_algorithm": "SHA-256",
+			"parameters.#":                      "1",
+		} {
+			if got := fmt.Sprint(d.Get(attr)); got != want {
+				t.Errorf("expected %s to be %q, got %q", attr, want, got)
+			}
+		}
+	})
+
+	t.Run("OIDC app by id", func(t *testing.T) {
+		d, err := readDataSource(t, dataSourceApp(), meta, map[string]interface{}{"id": oidcID})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := d.Get("name").(string); got != "Portal" {
+			t.Errorf("expected the name to be read, got %q", got)
+		}
+		if got := d.Get("client_id").(string); got != "abc123" {
+			t.Errorf("expected the client ID, got %q", got)
+		}
+		if got := d.Get("certificate").(string); got != "" {
+			t.Errorf("expected no certificate for an OIDC app, got %q", got)
+		}
+	})
+
+	for name, tt := range map[string]struct {
+		config map[string]interface{}
+		err    []string
+	}{
+		"missing name": {map[string]interface{}{"name": "Nothing"}, []string{`no app with name "Nothing" was found`}},
+		"missing id":   {map[string]interface{}{"id": 999999}, []string{"no app with ID 999999 was found"}},
+		"ambiguous":    {map[string]interface{}{"name": "Duplicate"}, []string{`2 apps have name "Duplicate"`, first, second}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := readDataSource(t, dataSourceApp(), meta, tt.config)
+			for _, want := range tt.err {
+				if err == nil || !strings.Contains(err.Error(), want) {
+					t.Errorf("expected an error containing %q, got %v", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestDataSourceAppsRead(t *testing.T) {
+	srv := fakeapi.NewServer()
+	defer srv.Close()
+	meta := fakeAPIMeta(t, srv)
+
+	const filler = 150
+	for i := 0; i < filler; i++ {
+		srv.Seed(fakeapi.Apps, map[string]interface{}{"name": fmt.Sprintf("app %d", i), "connector_id": float64(1), "auth_method": float64(1)})
+	}
+	salesSAML := srv.Seed(fakeapi.Apps, map[string]interface{}{"name": "Sales SAML", "connector_id": float64(50534), "auth_method": float64(authMethodSAML)})
+	salesOIDC := srv.Seed(fakeapi.Apps, map[string]interface{}{"name": "Sales OIDC", "connector_id": float64(108419), "auth_method": float64(authMethodOIDC)})
+	password := srv.Seed(fakeapi.Apps, map[string]interface{}{"name": "Intranet", "connector_id": float64(7), "auth_method": float64(0)})
+
+	for name, tt := range map[string]struct {
+		config map[string]interface{}
+		want   []string
+		count  int
+	}{
+		"every page":          {config: map[string]interface{}{}, count: filler + 3},
+		"name pattern":        {config: map[string]interface{}{"name": "Sales*"}, want: []string{salesSAML, salesOIDC}},
+		"connector":           {config: map[string]interface{}{"connector_id": 108419}, want: []string{salesOIDC}},
+		"auth method":         {config: map[string]interface{}{"name": "Sales*", "auth_method": authMethodSAML}, want: []string{salesSAML}},
+		"auth method of zero": {config: map[string]interface{}{"auth_method": 0}, want: []string{password}},
+		"nothing matches":     {config: map[string]interface{}{"name": "Nothing*"}, want: []string{}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			d, err := readDataSource(t, dataSourceApps(), meta, tt.config)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			ids := d.Get("ids").([]interface{})
+			if tt.want == nil {
+				if len(ids) != tt.count {
+					t.Fatalf("expected %d apps, got %d", tt.count, len(ids))
+				}
+				return
+			}
+			got := make([]string, len(ids))
+			for i, id := range ids {
+				got[i] = strconv.Itoa(id.(int))
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Fatalf("expected apps %v, got %v", tt.want, got)
+			}
+			if len(got) > 0 && d.Get("apps.0.name").(string) == "" {
+				t.Error("expected each app's name to be recorded")
+			}
+		})
+	}
+}
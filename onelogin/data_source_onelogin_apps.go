@@ -0,0 +1,150 @@
+package onelogin
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
+	appschema "github.com/onelogin/terraform-provider-onelogin/ol_schema/app"
+	"github.com/onelogin/terraform-provider-onelogin/utils"
+)
+
+// dataSourceApps returns the onelogin_apps data source, which lists apps,
+// optionally filtered by name, connector and auth method.
+func dataSourceApps() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAppsRead,
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(20 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only list apps with this name. A * stands for any run of characters, so Sales* lists every app whose name starts with Sales",
+			},
+			"connector_id": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Only list apps made from this connector",
+			},
+			"auth_method": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Only list apps that sign in this way, for example 2 for SAML or 8 for OpenID Connect",
+			},
+			"ids": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "IDs of the apps listed, in ascending order",
+			},
+			"apps": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id":           {Type: schema.TypeInt, Computed: true},
+						"name":         {Type: schema.TypeString, Computed: true},
+						"connector_id": {Type: schema.TypeInt, Computed: true},
+						"auth_method":  {Type: schema.TypeInt, Computed: true},
+						"description":  {Type: schema.TypeString, Computed: true},
+						"visible":      {Type: schema.TypeBool, Computed: true},
+						"policy_id":    {Type: schema.TypeInt, Computed: true},
+						"icon_url":     {Type: schema.TypeString, Computed: true},
+						"created_at":   {Type: schema.TypeString, Computed: true},
+						"updated_at":   {Type: schema.TypeString, Computed: true},
+					},
+				},
+			},
+		},
+	}
+}
+
+// appListFields are the fields of each app in the list response that
+// onelogin_apps records. Configuration, parameters and sso are only returned
+// for one app at a time; onelogin_app reads those.
+var appListFields = []string{
+	"name", "connector_id", "auth_method", "description", "visible",
+	"policy_id", "icon_url", "created_at", "updated_at",
+}
+
+func dataSourceAppsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*onelogin.OneloginSDK)
+
+	query := appschema.AppQuery{Name: d.Get("name").(string)}
+	if v, ok := d.GetOk("connector_id"); ok {
+		query.ConnectorID = strconv.Itoa(v.(int))
+	}
+	// auth_method 0 is password sign-in, so whether it was set is told from
+	// the configuration rather than from the value.
+	if !d.GetRawConfig().GetAttr("auth_method").IsNull() {
+		query.AuthMethod = strconv.Itoa(d.Get("auth_method").(int))
+	}
+
+	tflog.Info(ctx, "[READ] Reading OneLogin Apps", map[string]interface{}{
+		"name":         query.Name,
+		"connector_id": query.ConnectorID,
+		"auth_method":  query.AuthMethod,
+	})
+
+	results, err := listPages(ctx, func(page string) (interface{}, error) {
+		q := query
+		q.Limit = strconv.Itoa(importLookupPageLimit)
+		q.Page = page
+		return client.GetApps(&q)
+	})
+	if err != nil {
+		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryRead, "Apps", "")
+	}
+
+	apps := make([]map[string]interface{}, 0, len(results))
+	for _, result := range results {
+		app, ok := result.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, ok := app["id"].(float64)
+		if !ok {
+			continue
+		}
+		flat := map[string]interface{}{"id": int(id)}
+		for _, field := range appListFields {
+			if v, ok := app[field]; ok && v != nil {
+				flat[field] = v
+			}
+		}
+		apps = append(apps, flat)
+	}
+	sort.Slice(apps, func(i, j int) bool { return apps[i]["id"].(int) < apps[j]["id"].(int) })
+
+	ids := make([]int, len(apps))
+	for i, app := range apps {
+		ids[i] = app["id"].(int)
+	}
+
+	tflog.Info(ctx, "[READ] Read OneLogin Apps", map[string]interface{}{
+		"count": len(apps),
+	})
+
+	// The ID stands for the filter, so that it stays put across reads while
+	// the apps it lists change.
+	queryBytes, _ := json.Marshal(query)
+	d.SetId(fmt.Sprintf("%x", md5.Sum(queryBytes)))
+
+	if err := d.Set("ids", ids); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("apps", apps); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
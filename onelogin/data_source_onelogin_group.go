@@ -3,6 +3,7 @@ package onelogin
 import (
 	"context"
 	"strconv"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -15,6 +16,9 @@ import (
 func dataSourceOneLoginGroup() *schema.Resource {
 	return &schema.Resource{
 		ReadContext: dataSourceOneLoginGroupRead,
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Schema: map[string]*schema.Schema{
 			"id": {
 				Type:     schema.TypeInt,
@@ -41,7 +45,7 @@ func dataSourceOneLoginGroupRead(ctx context.Context, d *schema.ResourceData, m
 		"id": groupID,
 	})
 
-	resp, err := client.GetGroupByID(groupID)
+	resp, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetGroupByID(groupID) })
 	if err != nil {
 		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryRead, "OneLogin Group", strconv.Itoa(groupID))
 	}
@@ -3,6 +3,7 @@ package onelogin
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -16,6 +17,9 @@ import (
 func dataSourceOneLoginGroups() *schema.Resource {
 	return &schema.Resource{
 		ReadContext: dataSourceOneLoginGroupsRead,
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Schema: map[string]*schema.Schema{
 			"groups": {
 				Type:     schema.TypeList,
@@ -46,7 +50,7 @@ func dataSourceOneLoginGroupsRead(ctx context.Context, d *schema.ResourceData, m
 	client := m.(*onelogin.OneloginSDK)
 
 	tflog.Info(ctx, "[READ] Reading OneLogin Groups")
-	resp, err := client.GetGroups()
+	resp, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetGroups() })
 	if err != nil {
 		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryRead, "OneLogin Groups", "")
 	}
@@ -0,0 +1,158 @@
+package onelogin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
+	roleschema "github.com/onelogin/terraform-provider-onelogin/ol_schema/role"
+	"github.com/onelogin/terraform-provider-onelogin/utils"
+)
+
+// dataSourceRole returns the onelogin_role data source, which reads one role
+// by ID or by exact name.
+func dataSourceRole() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceRoleRead,
+		// Reading a role walks its users, admins and apps a page at a time, as
+		// the roles resource does.
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(40 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "name"},
+				Description:  "ID of the role to read",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "name"},
+				Description:  "Name of the role to read. It must match exactly, and only one role may have it",
+			},
+			"skip_membership": skipMembershipSchema(),
+			"apps":            roleMemberIDsSchema("IDs of the apps the role grants"),
+			"users":           roleMemberIDsSchema("IDs of the users assigned the role"),
+			"admins":          roleMemberIDsSchema("IDs of the users who administer the role"),
+		},
+	}
+}
+
+// skipMembershipSchema lets a role data source leave out membership it does
+// not need. Each attribute is a walk of every page of its sub-endpoint, which
+// for a role with thousands of users is most of the cost of the read.
+func skipMembershipSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		Elem: &schema.Schema{
+			Type:         schema.TypeString,
+			ValidateFunc: roleschema.ValidMembershipAttr,
+		},
+		Description: "Membership attributes not to read: any of apps, users, admins. Their sub-endpoints are not queried, and the attributes are left empty",
+	}
+}
+
+func roleMemberIDsSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Computed:    true,
+		Elem:        &schema.Schema{Type: schema.TypeInt},
+		Description: description,
+	}
+}
+
+func dataSourceRoleRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*onelogin.OneloginSDK)
+
+	var role map[string]interface{}
+	if id, ok := d.GetOk("id"); ok {
+		rid := id.(int)
+		tflog.Info(ctx, "[READ] Reading OneLogin Role", map[string]interface{}{
+			"id": rid,
+		})
+
+		result, err := client.GetRoleByIDWithContext(ctx, rid, nil)
+		if err != nil {
+			if utils.IsNotFoundError(err) {
+				return diag.Errorf("no role with ID %d was found", rid)
+			}
+			return utils.HandleAPIError(ctx, err, utils.ErrorCategoryRead, "Role", strconv.Itoa(rid))
+		}
+		if role, ok = result.(map[string]interface{}); !ok {
+			return diag.Errorf("failed to parse role response")
+		}
+	} else {
+		name := d.Get("name").(string)
+		tflog.Info(ctx, "[READ] Reading OneLogin Role", map[string]interface{}{
+			"name": name,
+		})
+
+		matches, err := roleLookup(ctx, client, "name", name)
+		if err != nil {
+			return utils.HandleAPIError(ctx, err, utils.ErrorCategoryRead, "Roles", "")
+		}
+		switch len(matches) {
+		case 0:
+			return diag.Errorf("no role with name %q was found", name)
+		case 1:
+			role = matches[0]
+		default:
+			ids := make([]string, len(matches))
+			for i, match := range matches {
+				ids[i] = objectID(match)
+			}
+			sort.Strings(ids)
+			return diag.Errorf("%d roles have name %q (IDs %s); read the one you mean by its id instead", len(matches), name, strings.Join(ids, ", "))
+		}
+	}
+
+	id, ok := role["id"].(float64)
+	if !ok {
+		return diag.Errorf("failed to extract role ID from response")
+	}
+	rid := int(id)
+	d.SetId(strconv.Itoa(rid))
+	if err := d.Set("name", role["name"]); err != nil {
+		return diag.FromErr(err)
+	}
+
+	members, diags := roleMembership(ctx, client, rid, membershipAttrsIn(d, "skip_membership"))
+	if diags.HasError() {
+		return diags
+	}
+	for _, attr := range roleschema.MembershipAttrs {
+		if err := d.Set(attr, members[attr]); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return nil
+}
+
+// roleMembership reads every page of role rid's membership attributes, other
+// than those in skip. A skipped attribute is missing from the result.
+func roleMembership(ctx context.Context, client *onelogin.OneloginSDK, rid int, skip map[string]bool) (map[string][]int, diag.Diagnostics) {
+	members := map[string][]int{}
+	for _, member := range roleMembers(client, rid) {
+		if skip[member.attr] {
+			continue
+		}
+		ids, err := fetchAllMemberIDs(ctx, member.fetch)
+		if err != nil {
+			return nil, utils.HandleAPIError(ctx, err, utils.ErrorCategoryRead, fmt.Sprintf("Role %s", member.attr), strconv.Itoa(rid))
+		}
+		members[member.attr] = ids
+	}
+	return members, nil
+}
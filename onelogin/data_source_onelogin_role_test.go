@@ -0,0 +1,205 @@
+package onelogin
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/onelogin/terraform-provider-onelogin/fakeapi"
+)
+
+func TestAccDataSourceRole(t *testing.T) {
+	name := newFixtureSuffix() + "-role-ds"
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { TestAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceRoleConfig(name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.onelogin_role.by_id", "name", "onelogin_roles.role", "name"),
+					resource.TestCheckResourceAttrPair("data.onelogin_role.by_name", "id", "onelogin_roles.role", "id"),
+					resource.TestCheckResourceAttr("data.onelogin_roles.named", "roles.#", "1"),
+					resource.TestCheckResourceAttrPair("data.onelogin_roles.named", "ids.0", "onelogin_roles.role", "id"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceRoleConfig(name string) string {
+	return fmt.Sprintf(`
+resource "onelogin_roles" "role" {
+  name = %q
+}
+
+data "onelogin_role" "by_id" {
+  id = onelogin_roles.role.id
+}
+
+data "onelogin_role" "by_name" {
+  name = onelogin_roles.role.name
+}
+
+data "onelogin_roles" "named" {
+  name            = onelogin_roles.role.name
+  skip_membership = ["users", "admins"]
+}
+`, name)
+}
+
+func intSet(t *testing.T, d *schema.ResourceData, key string) []int {
+	t.Helper()
+	var out []int
+	for _, v := range d.Get(key).(*schema.Set).List() {
+		out = append(out, v.(int))
+	}
+	sort.Ints(out)
+	return out
+}
+
+func TestDataSourceRoleRead(t *testing.T) {
+	srv := fakeapi.NewServer()
+	defer srv.Close()
+	meta := fakeAPIMeta(t, srv)
+
+	alice := srv.Seed(fakeapi.Users, map[string]interface{}{"username": "alice"})
+	bob := srv.Seed(fakeapi.Users, map[string]interface{}{"username": "bob"})
+	aliceID, _ := strconv.Atoi(alice)
+	bobID, _ := strconv.Atoi(bob)
+
+	engineering := srv.Seed(fakeapi.Roles, map[string]interface{}{
+		"name":   "Engineering",
+		"users":  []interface{}{float64(aliceID), float64(bobID)},
+		"admins": []interface{}{float64(bobID)},
+	})
+	engineeringID, _ := strconv.Atoi(engineering)
+	app := srv.Seed(fakeapi.Apps, map[string]interface{}{"name": "Web", "connector_id": float64(1), "role_ids": []interface{}{float64(engineeringID)}})
+	appID, _ := strconv.Atoi(app)
+
+	// The API's name filter ignores case; the data source does not.
+	srv.Seed(fakeapi.Roles, map[string]interface{}{"name": "engineering"})
+	first := srv.Seed(fakeapi.Roles, map[string]interface{}{"name": "Duplicate"})
+	second := srv.Seed(fakeapi.Roles, map[string]interface{}{"name": "Duplicate"})
+
+	t.Run("by name", func(t *testing.T) {
+		d, err := readDataSource(t, dataSourceRole(), meta, map[string]interface{}{"name": "Engineering"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if d.Id() != engineering {
+			t.Fatalf("expected role %s, got %s", engineering, d.Id())
+		}
+		if got := intSet(t, d, "users"); fmt.Sprint(got) != fmt.Sprint([]int{aliceID, bobID}) {
+			t.Errorf("expected users %d and %d, got %v", aliceID, bobID, got)
+		}
+		if got := intSet(t, d, "admins"); fmt.Sprint(got) != fmt.Sprint([]int{bobID}) {
+			t.Errorf("expected admin %d, got %v", bobID, got)
+		}
+		if got := intSet(t, d, "apps"); fmt.Sprint(got) != fmt.Sprint([]int{appID}) {
+			t.Errorf("expected app %d, got %v", appID, got)
+		}
+	})
+
+	t.Run("by id", func(t *testing.T) {
+		d, err := readDataSource(t, dataSourceRole(), meta, map[string]interface{}{"id": engineeringID})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if name := d.Get("name").(string); name != "Engineering" {
+			t.Fatalf("expected the name to be read, got %q", name)
+		}
+	})
+
+	t.Run("skip membership", func(t *testing.T) {
+		d, err := readDataSource(t, dataSourceRole(), meta, map[string]interface{}{
+			"id":              engineeringID,
+			"skip_membership": []interface{}{"users", "admins"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n := d.Get("users").(*schema.Set).Len(); n != 0 {
+			t.Errorf("expected skipped users to be left empty, got %d", n)
+		}
+		if got := intSet(t, d, "apps"); fmt.Sprint(got) != fmt.Sprint([]int{appID}) {
+			t.Errorf("expected apps still to be read, got %v", got)
+		}
+	})
+
+	for name, tt := range map[string]struct {
+		config map[string]interface{}
+		err    []string
+	}{
+		"missing name": {map[string]interface{}{"name": "Marketing"}, []string{`no role with name "Marketing" was found`}},
+		"missing id":   {map[string]interface{}{"id": 999999}, []string{"no role with ID 999999 was found"}},
+		"ambiguous":    {map[string]interface{}{"name": "Duplicate"}, []string{`2 roles have name "Duplicate"`, first, second}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := readDataSource(t, dataSourceRole(), meta, tt.config)
+			for _, want := range tt.err {
+				if err == nil || !strings.Contains(err.Error(), want) {
+					t.Errorf("expected an error containing %q, got %v", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestDataSourceRolesRead(t *testing.T) {
+	srv := fakeapi.NewServer()
+	defer srv.Close()
+	meta := fakeAPIMeta(t, srv)
+
+	alice := srv.Seed(fakeapi.Users, map[string]interface{}{"username": "alice"})
+	aliceID, _ := strconv.Atoi(alice)
+	const roles = 120
+	for i := 0; i < roles; i++ {
+		srv.Seed(fakeapi.Roles, map[string]interface{}{"name": fmt.Sprintf("role %d", i), "users": []interface{}{float64(aliceID)}})
+	}
+	support := srv.Seed(fakeapi.Roles, map[string]interface{}{"name": "Support"})
+
+	t.Run("every page", func(t *testing.T) {
+		d, err := readDataSource(t, dataSourceRoles(), meta, map[string]interface{}{
+			"skip_membership": []interface{}{"apps", "users", "admins"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n := len(d.Get("ids").([]interface{})); n != roles+1 {
+			t.Fatalf("expected %d roles, got %d", roles+1, n)
+		}
+		if n := d.Get("roles.0.users").(*schema.Set).Len(); n != 0 {
+			t.Errorf("expected skipped users to be left empty, got %d", n)
+		}
+	})
+
+	t.Run("filtered by name", func(t *testing.T) {
+		d, err := readDataSource(t, dataSourceRoles(), meta, map[string]interface{}{"name": "role 7"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n := d.Get("roles.#").(int); n != 1 {
+			t.Fatalf("expected one role, got %d", n)
+		}
+		if got := intSet(t, d, "roles.0.users"); fmt.Sprint(got) != fmt.Sprint([]int{aliceID}) {
+			t.Errorf("expected user %d, got %v", aliceID, got)
+		}
+
+		other, err := readDataSource(t, dataSourceRoles(), meta, map[string]interface{}{"name": "Support"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if other.Id() == d.Id() {
+			t.Error("expected different filters to give different IDs")
+		}
+		if got := fmt.Sprint(other.Get("roles.0.id")); got != support {
+			t.Errorf("expected role %s, got %s", support, got)
+		}
+	})
+}
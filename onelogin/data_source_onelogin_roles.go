@@ -0,0 +1,126 @@
+package onelogin
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
+	roleschema "github.com/onelogin/terraform-provider-onelogin/ol_schema/role"
+	"github.com/onelogin/terraform-provider-onelogin/utils"
+)
+
+// dataSourceRoles returns the onelogin_roles data source, which lists roles,
+// optionally filtered by name.
+func dataSourceRoles() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceRolesRead,
+		// Membership is walked for every role listed, unless it is skipped.
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(40 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only list roles with this name, as the API matches it",
+			},
+			"skip_membership": skipMembershipSchema(),
+			"ids": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "IDs of the roles listed, in ascending order",
+			},
+			"roles": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"apps":   roleMemberIDsSchema("IDs of the apps the role grants"),
+						"users":  roleMemberIDsSchema("IDs of the users assigned the role"),
+						"admins": roleMemberIDsSchema("IDs of the users who administer the role"),
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceRolesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*onelogin.OneloginSDK)
+	name := d.Get("name").(string)
+	skip := membershipAttrsIn(d, "skip_membership")
+
+	tflog.Info(ctx, "[READ] Reading OneLogin Roles", map[string]interface{}{
+		"name": name,
+	})
+
+	results, err := listPages(ctx, func(page string) (interface{}, error) {
+		return client.GetRoles(&roleschema.RoleQuery{Name: name, Limit: strconv.Itoa(importLookupPageLimit), Page: page})
+	})
+	if err != nil {
+		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryRead, "Roles", "")
+	}
+
+	roles := make([]map[string]interface{}, 0, len(results))
+	for _, result := range results {
+		role, ok := result.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, ok := role["id"].(float64)
+		if !ok {
+			continue
+		}
+		roles = append(roles, map[string]interface{}{"id": int(id), "name": role["name"]})
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i]["id"].(int) < roles[j]["id"].(int) })
+
+	ids := make([]int, len(roles))
+	for i, role := range roles {
+		ids[i] = role["id"].(int)
+		members, diags := roleMembership(ctx, client, ids[i], skip)
+		if diags.HasError() {
+			return diags
+		}
+		for attr, memberIDs := range members {
+			role[attr] = memberIDs
+		}
+	}
+
+	tflog.Info(ctx, "[READ] Read OneLogin Roles", map[string]interface{}{
+		"count": len(roles),
+	})
+
+	// The ID stands for the filter, so that it stays put across reads while
+	// the roles it lists change.
+	filterBytes, _ := json.Marshal(struct {
+		Name string
+		Skip map[string]bool
+	}{name, skip})
+	d.SetId(fmt.Sprintf("%x", md5.Sum(filterBytes)))
+
+	if err := d.Set("ids", ids); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("roles", roles); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package onelogin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
+
+	"github.com/onelogin/terraform-provider-onelogin/utils"
+)
+
+// maxSAMLMetadataSize caps how much of a metadata document is read. OneLogin's
+// are a few kilobytes; anything near this is not metadata.
+const maxSAMLMetadataSize = 1 << 20
+
+// dataSourceSAMLAppMetadata returns the onelogin_saml_app_metadata data
+// source, which fetches a SAML app's IdP metadata and reads out what a service
+// provider is configured with: the entity ID, the endpoints and the signing
+// certificate.
+func dataSourceSAMLAppMetadata() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceSAMLAppMetadataRead,
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(5 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"app_id": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ExactlyOneOf: []string{"app_id", "url"},
+				Description:  "ID of the SAML app whose metadata to fetch, from the metadata_url in its sso",
+			},
+			"url": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"app_id", "url"},
+				Description:  "URL to fetch the metadata from instead",
+			},
+			"xml": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The metadata document as served",
+			},
+			"entity_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The identity provider's entity ID, the issuer of its assertions",
+			},
+			"sso_urls": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Single sign-on endpoints by binding, e.g. HTTP-Redirect and HTTP-POST",
+			},
+			"slo_url": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The single logout endpoint, if there is one",
+			},
+			"certificate": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The signing certificate, PEM encoded",
+			},
+			"fingerprint": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "SHA-1 fingerprint of the signing certificate, as OneLogin shows it",
+			},
+		},
+	}
+}
+
+func dataSourceSAMLAppMetadataRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*onelogin.OneloginSDK)
+	url := d.Get("url").(string)
+	if aid := d.Get("app_id").(int); aid != 0 {
+		var diags diag.Diagnostics
+		if url, diags = samlAppMetadataURL(ctx, client, aid); diags.HasError() {
+			return diags
+		}
+	}
+
+	tflog.Info(ctx, "[READ] Fetching SAML metadata", map[string]interface{}{
+		"url": url,
+	})
+
+	raw, err := fetchSAMLMetadata(ctx, client.Client.HttpClient, url)
+	if err != nil {
+		return diag.Errorf("fetching SAML metadata from %s: %v", url, err)
+	}
+	md, err := utils.ParseSAMLMetadata(raw)
+	if err != nil {
+		return diag.Errorf("reading SAML metadata from %s: %v", url, err)
+	}
+
+	d.SetId(url)
+	for k, v := range map[string]interface{}{
+		"xml":         string(raw),
+		"entity_id":   md.EntityID,
+		"sso_urls":    md.SSOURLs,
+		"slo_url":     md.SLOURL,
+		"certificate": md.Certificate.PEM,
+		"fingerprint": md.Certificate.Fingerprint,
+	} {
+		if err := d.Set(k, v); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return nil
+}
+
+// samlAppMetadataURL is where the app with ID aid serves its metadata.
+func samlAppMetadataURL(ctx context.Context, client *onelogin.OneloginSDK, aid int) (string, diag.Diagnostics) {
+	tflog.Info(ctx, "[READ] Reading OneLogin app", map[string]interface{}{
+		"id": aid,
+	})
+
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetAppByID(aid, nil) })
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return "", diag.Errorf("no app with ID %d was found", aid)
+		}
+		return "", utils.HandleAPIError(ctx, err, utils.ErrorCategoryRead, "App", strconv.Itoa(aid))
+	}
+	appMap, ok := result.(map[string]interface{})
+	if !ok {
+		return "", diag.Errorf("failed to parse app response")
+	}
+	sso, _ := appMap["sso"].(map[string]interface{})
+	url, _ := sso["metadata_url"].(string)
+	if url == "" {
+		return "", diag.Errorf("app %d has no metadata_url; only SAML apps serve metadata", aid)
+	}
+	return url, nil
+}
+
+// fetchSAMLMetadata GETs a metadata document through the provider's own HTTP
+// client, so it goes by way of the same proxy and trusts the same CAs as every
+// API call. The client only sends its token to the API host, and metadata is
+// public, so anywhere else gets no credentials.
+func fetchSAMLMetadata(ctx context.Context, hc *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/samlmetadata+xml, application/xml, text/xml")
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server returned %s", resp.Status)
+	}
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxSAMLMetadataSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) > maxSAMLMetadataSize {
+		return nil, fmt.Errorf("document is larger than %d bytes", maxSAMLMetadataSize)
+	}
+	return raw, nil
+}
@@ -0,0 +1,199 @@
+package onelogin
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/onelogin/terraform-provider-onelogin/fakeapi"
+	"github.com/onelogin/terraform-provider-onelogin/utils"
+)
+
+func TestAccDataSourceSAMLAppMetadata(t *testing.T) {
+	name := newFixtureSuffix() + "-saml-metadata"
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { TestAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceSAMLAppMetadataConfig(name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.onelogin_saml_app_metadata.saml", "entity_id", "onelogin_saml_apps.saml", "sso.issuer"),
+					resource.TestCheckResourceAttrPair("data.onelogin_saml_app_metadata.saml", "sso_urls.HTTP-POST", "onelogin_saml_apps.saml", "sso.acs_url"),
+					resource.TestCheckResourceAttrPair("data.onelogin_saml_app_metadata.saml", "slo_url", "onelogin_saml_apps.saml", "sso.sls_url"),
+					resource.TestCheckResourceAttrSet("data.onelogin_saml_app_metadata.saml", "certificate"),
+					resource.TestCheckResourceAttrSet("data.onelogin_saml_app_metadata.saml", "fingerprint"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceSAMLAppMetadataConfig(name string) string {
+	return fmt.Sprintf(`
+resource "onelogin_saml_apps" "saml" {
+  name         = %q
+  connector_id = 50534
+
+  configuration = {
+    signature_algorithm = "SHA-256"
+  }
+}
+
+data "onelogin_saml_app_metadata" "saml" {
+  app_id = onelogin_saml_apps.saml.id
+}
+`, name)
+}
+
+func TestDataSourceSAMLAppMetadataRead(t *testing.T) {
+	certPEM, _ := testCertificatePEM(t, time.Now().AddDate(1, 0, 0))
+	block, _ := pem.Decode([]byte(certPEM))
+	metadata := fmt.Sprintf(`<?xml version="1.0"?>
+<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" entityID="https://app.onelogin.com/saml/metadata/1">
+  <md:IDPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+    <md:KeyDescriptor use="signing">
+      <ds:KeyInfo xmlns:ds="http://www.w3.org/2000/09/xmldsig#">
+        <ds:X509Data><ds:X509Certificate>%s</ds:X509Certificate></ds:X509Data>
+      </ds:KeyInfo>
+    </md:KeyDescriptor>
+    <md:SingleLogoutService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://example.onelogin.com/trust/saml2/http-redirect/slo/1"/>
+    <md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://example.onelogin.com/trust/saml2/http-redirect/sso/1"/>
+    <md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="https://example.onelogin.com/trust/saml2/http-post/sso/1"/>
+  </md:IDPSSODescriptor>
+</md:EntityDescriptor>`, base64.StdEncoding.EncodeToString(block.Bytes))
+
+	idp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/saml/metadata/1":
+			w.Header().Set("Content-Type", "application/xml")
+			fmt.Fprint(w, metadata)
+		case "/login":
+			fmt.Fprint(w, "<html><body>Sign in</body></html>")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer idp.Close()
+
+	srv := fakeapi.NewServer()
+	defer srv.Close()
+	meta := fakeAPIMeta(t, srv)
+
+	saml, _ := strconv.Atoi(srv.Seed(fakeapi.Apps, map[string]interface{}{
+		"name": "Payroll", "connector_id": float64(50534), "auth_method": float64(authMethodSAML),
+		"sso": map[string]interface{}{"metadata_url": idp.URL + "/saml/metadata/1"},
+	}))
+	oidc, _ := strconv.Atoi(srv.Seed(fakeapi.Apps, map[string]interface{}{
+		"name": "Portal", "connector_id": float64(108419), "auth_method": float64(authMethodOIDC),
+		"sso": map[string]interface{}{"client_id": "abc123"},
+	}))
+	want, _ := utils.ParseCertificate(certPEM)
+
+	for name, config := range map[string]map[string]interface{}{
+		"by app": {"app_id": saml},
+		"by url": {"url": idp.URL + "/saml/metadata/1"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			d, err := readDataSource(t, dataSourceSAMLAppMetadata(), meta, config)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if d.Id() != idp.URL+"/saml/metadata/1" {
+				t.Errorf("expected the metadata URL as the ID, got %q", d.Id())
+			}
+			for attr, want := range map[string]string{
+				"xml":                    metadata,
+				"entity_id":              "https://app.onelogin.com/saml/metadata/1",
+				"sso_urls.%":             "2",
+				"sso_urls.HTTP-Redirect": "https://example.onelogin.com/trust/saml2/http-redirect/sso/1",
+				"sso_urls.HTTP-POST":     "https://example.onelogin.com/trust/saml2/http-post/sso/1",
+				"slo_url":                "https://example.onelogin.com/trust/saml2/http-redirect/slo/1",
+				"certificate":            certPEM,
+				"fingerprint":            want.Fingerprint,
+			} {
+				if got := fmt.Sprint(d.Get(attr)); got != want {
+					t.Errorf("expected %s to be %q, got %q", attr, want, got)
+				}
+			}
+		})
+	}
+
+	for name, tt := range map[string]struct {
+		config map[string]interface{}
+		err    string
+	}{
+		"not a SAML app": {map[string]interface{}{"app_id": oidc}, "only SAML apps serve metadata"},
+		"missing app":    {map[string]interface{}{"app_id": 999999}, "no app with ID 999999 was found"},
+		"missing page":   {map[string]interface{}{"url": idp.URL + "/saml/metadata/2"}, "404 Not Found"},
+		"not metadata":   {map[string]interface{}{"url": idp.URL + "/login"}, "reading SAML metadata"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := readDataSource(t, dataSourceSAMLAppMetadata(), meta, tt.config)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("expected an error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+// TestDataSourceSAMLAppMetadataTrustsProviderCA checks the metadata is fetched
+// with the provider's network settings rather than Go's defaults: served over
+// TLS with a certificate only ca_cert_pem vouches for, it is read with that
+// setting and refused without.
+func TestDataSourceSAMLAppMetadataTrustsProviderCA(t *testing.T) {
+	certPEM, _ := testCertificatePEM(t, time.Now().AddDate(1, 0, 0))
+	block, _ := pem.Decode([]byte(certPEM))
+	idp := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<EntityDescriptor xmlns="urn:oasis:names:tc:SAML:2.0:metadata" entityID="https://idp.example.com">
+  <IDPSSODescriptor>
+    <KeyDescriptor><KeyInfo><X509Data><X509Certificate>%s</X509Certificate></X509Data></KeyInfo></KeyDescriptor>
+  </IDPSSODescriptor>
+</EntityDescriptor>`, base64.StdEncoding.EncodeToString(block.Bytes))
+	}))
+	defer idp.Close()
+
+	srv := fakeapi.NewServer()
+	defer srv.Close()
+	idpCA := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: idp.Certificate().Raw}))
+
+	configure := func(extra map[string]interface{}) interface{} {
+		raw := map[string]interface{}{
+			"client_id":     fakeapi.ClientID,
+			"client_secret": fakeapi.ClientSecret,
+			"url":           srv.URL,
+			// A refused certificate is not worth retrying.
+			"max_retries": 0,
+		}
+		for k, v := range extra {
+			raw[k] = v
+		}
+		p := Provider()
+		if diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(raw)); diags.HasError() {
+			t.Fatalf("unexpected error configuring the provider: %v", diags)
+		}
+		return p.Meta()
+	}
+	config := map[string]interface{}{"url": idp.URL}
+
+	if _, err := readDataSource(t, dataSourceSAMLAppMetadata(), configure(nil), config); err == nil || !strings.Contains(err.Error(), "certificate") {
+		t.Fatalf("expected an untrusted certificate to be refused, got %v", err)
+	}
+	d, err := readDataSource(t, dataSourceSAMLAppMetadata(), configure(map[string]interface{}{"ca_cert_pem": idpCA}), config)
+	if err != nil {
+		t.Fatalf("expected the metadata to be fetched trusting ca_cert_pem, got %v", err)
+	}
+	if got := d.Get("entity_id"); got != "https://idp.example.com" {
+		t.Fatalf("expected the entity ID to be read, got %v", got)
+	}
+}
@@ -1,25 +1,31 @@
 package onelogin
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
 	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin/models"
 	userschema "github.com/onelogin/terraform-provider-onelogin/ol_schema/user"
+	"github.com/onelogin/terraform-provider-onelogin/utils"
 )
 
 // Users returns a resource with the CRUD methods and Terraform Schema defined
 func dataSourceUser() *schema.Resource {
 	return &schema.Resource{
-		Read:   dataSourceUserRead,
+		ReadContext: utils.ContextOperation(utils.ErrorCategoryRead, "User", dataSourceUserRead),
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Schema: userschema.ReadSchema(),
 	}
 }
 
-func dataSourceUserRead(d *schema.ResourceData, m interface{}) error {
+func dataSourceUserRead(ctx context.Context, d *schema.ResourceData, m interface{}) error {
 	client := m.(*onelogin.OneloginSDK)
 	query, _ := userschema.QueryInflate(map[string]interface{}{
 		"username": d.Get("username"),
@@ -50,7 +56,7 @@ func dataSourceUserRead(d *schema.ResourceData, m interface{}) error {
 		UserIDs:  userIDs,
 	}
 
-	result, err := client.GetUsers(sdkQuery)
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetUsers(sdkQuery) })
 	if err != nil {
 		log.Printf("[ERROR] There was a problem reading the user!")
 		log.Println(err)
@@ -0,0 +1,112 @@
+package onelogin
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
+
+	"github.com/onelogin/terraform-provider-onelogin/utils"
+)
+
+// userMappingCatalogue is the catalogue of the tenant's user mappings. Unlike an
+// app's rules, there is one for the whole tenant. Each call gives up when ctx
+// does, so a read or plan is held to its timeout.
+func userMappingCatalogue(client *onelogin.OneloginSDK) ruleCatalogue {
+	return ruleCatalogue{
+		conditions: func(ctx context.Context) (interface{}, error) {
+			return utils.CallWithContext(ctx, func() (interface{}, error) { return client.ListConditions() })
+		},
+		operators: func(ctx context.Context, condition string) (interface{}, error) {
+			return utils.CallWithContext(ctx, func() (interface{}, error) { return client.ListConditionOperators(condition) })
+		},
+		conditionValues: func(ctx context.Context, condition string) (interface{}, error) {
+			return utils.CallWithContext(ctx, func() (interface{}, error) { return client.ListConditionValues(condition) })
+		},
+		actions: func(ctx context.Context) (interface{}, error) {
+			return utils.CallWithContext(ctx, func() (interface{}, error) { return client.ListActions() })
+		},
+		actionValues: func(ctx context.Context, action string) (interface{}, error) {
+			return utils.CallWithContext(ctx, func() (interface{}, error) { return client.ListActionValues(action) })
+		},
+	}
+}
+
+// userMappingCatalogueArgs are the arguments of a user mapping catalogue data
+// source: key, if set, described by description.
+func userMappingCatalogueArgs(key, description string) map[string]*schema.Schema {
+	args := map[string]*schema.Schema{}
+	if key != "" {
+		args[key] = &schema.Schema{Type: schema.TypeString, Required: true, Description: description}
+	}
+	return args
+}
+
+// userMappingCatalogueList wraps a fetch of part of the user mapping catalogue
+// for catalogueDataSource. key, if set, is the argument naming the condition or
+// action the list belongs to; the ID is the list, then the argument.
+func userMappingCatalogueList(list, key string, fetch func(ctx context.Context, cat ruleCatalogue, arg string) (interface{}, error)) func(context.Context, *onelogin.OneloginSDK, *schema.ResourceData) (interface{}, string, error) {
+	return func(ctx context.Context, client *onelogin.OneloginSDK, d *schema.ResourceData) (interface{}, string, error) {
+		id := "user_mappings:" + list
+		var arg string
+		if key != "" {
+			arg = d.Get(key).(string)
+			id += ":" + arg
+		}
+		result, err := fetch(ctx, userMappingCatalogue(client), arg)
+		return result, id, err
+	}
+}
+
+// dataSourceUserMappingConditions returns the onelogin_user_mapping_conditions
+// data source, which lists the sources a mapping's conditions can check.
+func dataSourceUserMappingConditions() *schema.Resource {
+	return catalogueDataSource("conditions", "The sources a condition can check",
+		userMappingCatalogueArgs("", ""),
+		userMappingCatalogueList("conditions", "", func(ctx context.Context, cat ruleCatalogue, _ string) (interface{}, error) {
+			return cat.conditions(ctx)
+		}))
+}
+
+// dataSourceUserMappingConditionOperators returns the
+// onelogin_user_mapping_condition_operators data source, which lists the
+// operators one condition source takes.
+func dataSourceUserMappingConditionOperators() *schema.Resource {
+	return catalogueDataSource("operators", "The operators the condition takes",
+		userMappingCatalogueArgs("condition", "The condition's source, a value from onelogin_user_mapping_conditions"),
+		userMappingCatalogueList("operators", "condition", func(ctx context.Context, cat ruleCatalogue, condition string) (interface{}, error) {
+			return cat.operators(ctx, condition)
+		}))
+}
+
+// dataSourceUserMappingConditionValues returns the
+// onelogin_user_mapping_condition_values data source, which lists the values
+// one condition source is limited to.
+func dataSourceUserMappingConditionValues() *schema.Resource {
+	return catalogueDataSource("values", "The values the condition can compare against. Empty for a condition that takes any value",
+		userMappingCatalogueArgs("condition", "The condition's source, a value from onelogin_user_mapping_conditions"),
+		userMappingCatalogueList("condition_values", "condition", func(ctx context.Context, cat ruleCatalogue, condition string) (interface{}, error) {
+			return cat.conditionValues(ctx, condition)
+		}))
+}
+
+// dataSourceUserMappingActions returns the onelogin_user_mapping_actions data
+// source, which lists the actions a mapping can take.
+func dataSourceUserMappingActions() *schema.Resource {
+	return catalogueDataSource("actions", "The actions a mapping can take",
+		userMappingCatalogueArgs("", ""),
+		userMappingCatalogueList("actions", "", func(ctx context.Context, cat ruleCatalogue, _ string) (interface{}, error) {
+			return cat.actions(ctx)
+		}))
+}
+
+// dataSourceUserMappingActionValues returns the
+// onelogin_user_mapping_action_values data source, which lists the values one
+// action is limited to.
+func dataSourceUserMappingActionValues() *schema.Resource {
+	return catalogueDataSource("values", "The values the action can set. Empty for an action that takes any value",
+		userMappingCatalogueArgs("action", "The action, a value from onelogin_user_mapping_actions"),
+		userMappingCatalogueList("action_values", "action", func(ctx context.Context, cat ruleCatalogue, action string) (interface{}, error) {
+			return cat.actionValues(ctx, action)
+		}))
+}
@@ -0,0 +1,98 @@
+package onelogin
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/onelogin/terraform-provider-onelogin/fakeapi"
+)
+
+func TestDataSourceUserMappingCatalogue(t *testing.T) {
+	srv := fakeapi.NewServer()
+	defer srv.Close()
+	meta := fakeAPIMeta(t, srv)
+
+	role := srv.Seed(fakeapi.Roles, map[string]interface{}{"name": "Admins"})
+
+	values := func(d *schema.ResourceData, attr string) []string {
+		var out []string
+		for _, e := range d.Get(attr).([]interface{}) {
+			out = append(out, e.(map[string]interface{})["value"].(string))
+		}
+		return out
+	}
+
+	for name, tt := range map[string]struct {
+		r      *schema.Resource
+		config map[string]interface{}
+		attr   string
+		want   []string
+	}{
+		"conditions":       {dataSourceUserMappingConditions(), map[string]interface{}{}, "conditions", []string{"email", "department", "title", "last_login", "has_role"}},
+		"operators":        {dataSourceUserMappingConditionOperators(), map[string]interface{}{"condition": "last_login"}, "operators", []string{">", "<"}},
+		"condition values": {dataSourceUserMappingConditionValues(), map[string]interface{}{"condition": "has_role"}, "values", []string{role}},
+		"actions":          {dataSourceUserMappingActions(), map[string]interface{}{}, "actions", []string{"add_role", "set_role", "set_status", "set_userprincipalname"}},
+		"action values":    {dataSourceUserMappingActionValues(), map[string]interface{}{"action": "add_role"}, "values", []string{role}},
+		"any value":        {dataSourceUserMappingActionValues(), map[string]interface{}{"action": "set_userprincipalname"}, "values", nil},
+	} {
+		t.Run(name, func(t *testing.T) {
+			d, err := readDataSource(t, tt.r, meta, tt.config)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := values(d, tt.attr); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Fatalf("expected %s %v, got %v", tt.attr, tt.want, got)
+			}
+			if d.Id() == "" {
+				t.Fatal("expected an ID to be set")
+			}
+		})
+	}
+}
+
+func TestUserMappingCustomizeDiff(t *testing.T) {
+	srv := fakeapi.NewServer()
+	defer srv.Close()
+	meta := fakeAPIMeta(t, srv)
+
+	role := srv.Seed(fakeapi.Roles, map[string]interface{}{"name": "Admins"})
+
+	mapping := func(validate bool, source, operator, action, value string) map[string]interface{} {
+		return map[string]interface{}{
+			"name": "mapping", "match": "all", "enabled": true, "validate_catalogue": validate,
+			"conditions": []interface{}{map[string]interface{}{"source": source, "operator": operator, "value": "Engineering"}},
+			"actions":    []interface{}{map[string]interface{}{"action": action, "value": []interface{}{value}}},
+		}
+	}
+
+	for name, tt := range map[string]struct {
+		config  map[string]interface{}
+		unknown []string
+		err     string
+	}{
+		"allowed":            {config: mapping(true, "department", "=", "add_role", role)},
+		"a macro":            {config: mapping(true, "title", "~", "set_userprincipalname", "${user.email}")},
+		"mistyped source":    {config: mapping(true, "departmnet", "=", "add_role", role), err: `conditions.0: source "departmnet" is not one of: email, department, title, last_login, has_role`},
+		"wrong operator":     {config: mapping(true, "department", ">", "add_role", role), err: `conditions.0: operator ">" is not one of: =, !=, ~, !~`},
+		"role not there":     {config: mapping(true, "department", "=", "add_role", "999999"), err: `actions.0: value "999999"`},
+		"bad status":         {config: mapping(true, "department", "=", "set_status", "9"), err: `actions.0: value "9" is not one of: 0, 1, 2, 3`},
+		"not asked to check": {config: mapping(false, "departmnet", "=", "add_rol", role)},
+		"actions not known":  {config: mapping(true, "department", "=", "add_rol", role), unknown: []string{"actions"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := planResource(t, UserMappings(), meta, tt.config, tt.unknown...)
+			if tt.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("expected an error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,343 @@
+package onelogin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
+
+	usermappingschema "github.com/onelogin/terraform-provider-onelogin/ol_schema/user_mapping"
+	usermappingactionsschema "github.com/onelogin/terraform-provider-onelogin/ol_schema/user_mapping/actions"
+	usermappingconditionsschema "github.com/onelogin/terraform-provider-onelogin/ol_schema/user_mapping/conditions"
+	"github.com/onelogin/terraform-provider-onelogin/utils"
+)
+
+// dryRunMappingPrefix names the disabled mapping an inline dry run creates for
+// the length of the read. The onelogin_user_mappings sweeper deletes any left
+// over by it.
+const dryRunMappingPrefix = "terraform-dry-run-"
+
+// dryRunCleanupTimeout bounds deleting an inline dry run's mapping. The delete
+// does not share the read's deadline, which has often passed by then.
+const dryRunCleanupTimeout = time.Minute
+
+// dataSourceUserMappingDryRun returns the onelogin_user_mapping_dry_run data
+// source, which reports what a mapping would change for a list of users
+// without changing it.
+func dataSourceUserMappingDryRun() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceUserMappingDryRunRead,
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(5 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"mapping_id": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ExactlyOneOf: []string{"mapping_id", "actions"},
+				Description:  "ID of the mapping to run, enabled or not",
+			},
+			"match": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"mapping_id"},
+				ValidateFunc: func(val interface{}, key string) ([]string, []error) {
+					return utils.OneOfValue(key, val, []string{"all", "any"})
+				},
+				Description: "How an inline mapping's conditions are matched: all or any. Defaults to all",
+			},
+			"conditions": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				ConflictsWith: []string{"mapping_id"},
+				Elem:          &schema.Resource{Schema: usermappingconditionsschema.Schema()},
+				Description:   "Conditions of an inline mapping, as in onelogin_user_mappings",
+			},
+			"actions": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				ExactlyOneOf: []string{"mapping_id", "actions"},
+				Elem:         &schema.Resource{Schema: usermappingactionsschema.Schema()},
+				Description:  "Actions of an inline mapping, as in onelogin_user_mappings. The dry run needs a mapping to run, so a disabled one is created from these for the length of the read and deleted after it",
+			},
+			"user_ids": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "IDs of the users to run the mapping against",
+			},
+			"results": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "What the mapping would do, one entry per user in the order of user_ids",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"user_id": {Type: schema.TypeInt, Computed: true},
+						"email":   {Type: schema.TypeString, Computed: true},
+						"mapped": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the user meets the mapping's conditions",
+						},
+						"changes": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "Each action's effect on the user, in the order of the mapping's actions. Empty when the user is not mapped",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"action": {Type: schema.TypeString, Computed: true},
+									"value":  {Type: schema.TypeList, Computed: true, Elem: &schema.Schema{Type: schema.TypeString}},
+									"before": {
+										Type:        schema.TypeList,
+										Computed:    true,
+										Elem:        &schema.Schema{Type: schema.TypeString},
+										Description: "The user's value for what the action sets, such as their role IDs or status, as it is",
+									},
+									"after": {
+										Type:        schema.TypeList,
+										Computed:    true,
+										Elem:        &schema.Schema{Type: schema.TypeString},
+										Description: "The same value as the action would leave it",
+									},
+									"changed": {
+										Type:        schema.TypeBool,
+										Computed:    true,
+										Description: "Whether before and after differ, ignoring order",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"affected_user_ids": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "IDs of the users the mapping would change, in the order of user_ids",
+			},
+		},
+	}
+}
+
+func dataSourceUserMappingDryRunRead(ctx context.Context, d *schema.ResourceData, m interface{}) (diags diag.Diagnostics) {
+	client := m.(*onelogin.OneloginSDK)
+
+	var userIDs []int32
+	var idParts []string
+	for _, v := range d.Get("user_ids").([]interface{}) {
+		userIDs = append(userIDs, int32(v.(int)))
+		idParts = append(idParts, strconv.Itoa(v.(int)))
+	}
+
+	id := "inline"
+	var mappingID int32
+	if v, ok := d.GetOk("mapping_id"); ok {
+		mappingID = int32(v.(int))
+		id = strconv.Itoa(v.(int))
+	} else {
+		tempID, createDiags := createDryRunMapping(ctx, client, d)
+		if createDiags != nil {
+			return createDiags
+		}
+		defer func() {
+			diags = append(diags, deleteDryRunMapping(ctx, client, tempID)...)
+		}()
+		mappingID = tempID
+	}
+	id += ":" + strings.Join(idParts, ",")
+
+	tflog.Info(ctx, "[READ] Dry running user mapping", map[string]interface{}{
+		"mapping_id": mappingID,
+		"users":      len(userIDs),
+	})
+
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) {
+		return client.DryRunUserMapping(mappingID, userIDs)
+	})
+	if err != nil {
+		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryRead, "User Mapping Dry Run", id)
+	}
+	results, err := parseDryRun(result)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	flat := make([]map[string]interface{}, len(results))
+	affected := []int{}
+	for i, r := range results {
+		changes := make([]map[string]interface{}, len(r.changes))
+		changed := false
+		for j, c := range r.changes {
+			changes[j] = map[string]interface{}{
+				"action":  c.action,
+				"value":   c.value,
+				"before":  c.before,
+				"after":   c.after,
+				"changed": c.changed,
+			}
+			changed = changed || c.changed
+		}
+		flat[i] = map[string]interface{}{
+			"user_id": r.userID,
+			"email":   r.email,
+			"mapped":  r.mapped,
+			"changes": changes,
+		}
+		if r.mapped && changed {
+			affected = append(affected, r.userID)
+		}
+	}
+
+	d.SetId(id)
+	if err := d.Set("results", flat); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("affected_user_ids", affected); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+// createDryRunMapping creates the disabled mapping an inline dry run runs.
+// Disabled, it is never applied to anyone, and it takes no position among the
+// enabled mappings.
+func createDryRunMapping(ctx context.Context, client *onelogin.OneloginSDK, d *schema.ResourceData) (int32, diag.Diagnostics) {
+	match := d.Get("match").(string)
+	if match == "" {
+		match = "all"
+	}
+	mapping, err := usermappingschema.Inflate(map[string]interface{}{
+		"name":       fmt.Sprintf("%s%d", dryRunMappingPrefix, time.Now().UnixNano()),
+		"match":      match,
+		"enabled":    false,
+		"conditions": d.Get("conditions"),
+		"actions":    d.Get("actions"),
+	})
+	if err != nil {
+		return 0, utils.HandleSchemaError(ctx, err, utils.ErrorCategoryRead, "User Mapping Dry Run", "")
+	}
+
+	result, err := createUserMapping(ctx, client, mapping)
+	if err != nil {
+		return 0, utils.HandleAPIError(ctx, err, utils.ErrorCategoryCreate, "User Mapping Dry Run", "")
+	}
+	if result == nil || result.ID == nil {
+		return 0, diag.Errorf("failed to extract user mapping ID from response")
+	}
+	return *result.ID, nil
+}
+
+// deleteDryRunMapping deletes the mapping createDryRunMapping created. A
+// failure does not fail the read, whose result is good, but is returned as a
+// warning naming the mapping: it is disabled, so it is left over rather than
+// doing harm, but it is left over in the tenant until someone deletes it.
+//
+// A read that timed out or was cancelled is exactly the one that most needs
+// cleaning up after, so the delete is given a context of its own, bounded by
+// dryRunCleanupTimeout, instead of the read's, which is done by then.
+func deleteDryRunMapping(ctx context.Context, client *onelogin.OneloginSDK, id int32) diag.Diagnostics {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), dryRunCleanupTimeout)
+	defer cancel()
+
+	_, err := utils.CallWithContext(ctx, func() (interface{}, error) { return nil, client.DeleteUserMapping(id) })
+	if err == nil || utils.IsNotFoundError(err) {
+		return nil
+	}
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Could not delete user mapping %d, created for a dry run", id),
+		Detail: fmt.Sprintf("The dry run's result is good, but the disabled mapping it ran, ID %d, is still in OneLogin: %v. It is never applied to anyone; delete it by hand.",
+			id, err),
+	}}
+}
+
+type dryRunResult struct {
+	userID  int
+	email   string
+	mapped  bool
+	changes []dryRunChange
+}
+
+type dryRunChange struct {
+	action               string
+	value, before, after []string
+	changed              bool
+}
+
+// parseDryRun reads a dry run's response: for each user, the user, whether
+// they were mapped, and for each action its value and what it would change,
+//
+//	[{"user": {"id": ..., "email": ...}, "mapped": true,
+//	  "changes": [{"action": ..., "value": [...], "before": [...], "after": [...]}]}]
+func parseDryRun(result interface{}) ([]dryRunResult, error) {
+	items, ok := result.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected dry run response: want a JSON array, got %T", result)
+	}
+	out := make([]dryRunResult, 0, len(items))
+	for _, item := range items {
+		o, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var r dryRunResult
+		if user, ok := o["user"].(map[string]interface{}); ok {
+			if id, ok := user["id"].(float64); ok {
+				r.userID = int(id)
+			}
+			r.email, _ = user["email"].(string)
+		}
+		r.mapped, _ = o["mapped"].(bool)
+		changes, _ := o["changes"].([]interface{})
+		for _, c := range changes {
+			co, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			change := dryRunChange{
+				value:  dryRunStrings(co["value"]),
+				before: dryRunStrings(co["before"]),
+				after:  dryRunStrings(co["after"]),
+			}
+			change.action, _ = co["action"].(string)
+			change.changed = !sameStrings(change.before, change.after)
+			r.changes = append(r.changes, change)
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+func dryRunStrings(v interface{}) []string {
+	list, _ := v.([]interface{})
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		out = append(out, catalogueValue(item))
+	}
+	return out
+}
+
+// sameStrings reports whether a and b hold the same strings, in any order.
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = append([]string(nil), a...), append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,212 @@
+package onelogin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/onelogin/terraform-provider-onelogin/fakeapi"
+)
+
+func TestDataSourceUserMappingDryRun(t *testing.T) {
+	srv := fakeapi.NewServer()
+	defer srv.Close()
+	meta := fakeAPIMeta(t, srv)
+
+	eng := srv.Seed(fakeapi.Users, map[string]interface{}{"email": "eng@example.com", "department": "Engineering", "status": float64(1)})
+	ops := srv.Seed(fakeapi.Users, map[string]interface{}{"email": "ops@example.com", "department": "Operations", "status": float64(1)})
+	engID, _ := strconv.Atoi(eng)
+	opsID, _ := strconv.Atoi(ops)
+	role := srv.Seed(fakeapi.Roles, map[string]interface{}{"name": "Engineers", "users": []interface{}{float64(engID)}})
+
+	conditions := []interface{}{map[string]interface{}{"source": "department", "operator": "=", "value": "Engineering"}}
+	mapping := srv.Seed(fakeapi.Mappings, map[string]interface{}{
+		"name": "Engineers", "match": "all", "enabled": false, "conditions": conditions,
+		"actions": []interface{}{
+			map[string]interface{}{"action": "add_role", "value": []interface{}{role}},
+			map[string]interface{}{"action": "set_status", "value": []interface{}{"2"}},
+		},
+	})
+	mappingID, _ := strconv.Atoi(mapping)
+
+	t.Run("an existing mapping", func(t *testing.T) {
+		d, err := readDataSource(t, dataSourceUserMappingDryRun(), meta, map[string]interface{}{
+			"mapping_id": mappingID,
+			"user_ids":   []interface{}{engID, opsID},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if d.Get("results.0.mapped") != true || d.Get("results.1.mapped") != false {
+			t.Fatalf("expected only the engineer to be mapped, got %v", d.Get("results"))
+		}
+		// The engineer has the role already, so only the status changes.
+		if d.Get("results.0.changes.0.changed") != false || d.Get("results.0.changes.1.changed") != true {
+			t.Fatalf("expected only set_status to change anything, got %v", d.Get("results.0.changes"))
+		}
+		if got := d.Get("results.0.changes.1.after"); fmt.Sprint(got) != "[2]" {
+			t.Fatalf("expected the status to become 2, got %v", got)
+		}
+		if got := d.Get("affected_user_ids"); fmt.Sprint(got) != fmt.Sprintf("[%d]", engID) {
+			t.Fatalf("expected only the engineer to be affected, got %v", got)
+		}
+	})
+
+	t.Run("an inline mapping", func(t *testing.T) {
+		before := len(srv.Objects(fakeapi.Mappings))
+		d, err := readDataSource(t, dataSourceUserMappingDryRun(), meta, map[string]interface{}{
+			"conditions": conditions,
+			"actions":    []interface{}{map[string]interface{}{"action": "set_userprincipalname", "value": []interface{}{"${user.email}"}}},
+			"user_ids":   []interface{}{engID},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := d.Get("results.0.changes.0.after"); fmt.Sprint(got) != "[eng@example.com]" {
+			t.Fatalf("expected the user principal name to be set from the email, got %v", got)
+		}
+		if after := len(srv.Objects(fakeapi.Mappings)); after != before {
+			t.Fatalf("expected the mapping created for the dry run to be deleted, have %d mappings, had %d", after, before)
+		}
+	})
+
+	t.Run("a user that does not exist", func(t *testing.T) {
+		if _, err := readDataSource(t, dataSourceUserMappingDryRun(), meta, map[string]interface{}{
+			"mapping_id": mappingID,
+			"user_ids":   []interface{}{999999},
+		}); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
+
+func TestSameStrings(t *testing.T) {
+	for _, tt := range []struct {
+		a, b []string
+		want bool
+	}{
+		{[]string{"1", "2"}, []string{"2", "1"}, true},
+		{[]string{"1"}, []string{"1", "2"}, false},
+		{[]string{}, []string{}, true},
+		{[]string{"1", "1"}, []string{"1", "2"}, false},
+	} {
+		if got := sameStrings(tt.a, tt.b); got != tt.want {
+			t.Errorf("sameStrings(%v, %v): expected %v, got %v", tt.a, tt.b, tt.want, got)
+		}
+	}
+}
+
+// TestDataSourceUserMappingDryRunCancelled checks an inline dry run's mapping
+// is deleted even when the read is cancelled while the dry run is under way.
+// A proxy in front of the fake cancels the read when the dry run arrives, and
+// only lets it through once the delete has come, so the read has certainly
+// given up first.
+func TestDataSourceUserMappingDryRunCancelled(t *testing.T) {
+	srv := fakeapi.NewServer()
+	defer srv.Close()
+	user, _ := strconv.Atoi(srv.Seed(fakeapi.Users, map[string]interface{}{"email": "eng@example.com", "status": float64(1)}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	deleted := make(chan struct{})
+	var once sync.Once
+	upstream, _ := url.Parse(srv.URL)
+	forward := httputil.NewSingleHostReverseProxy(upstream)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/dryrun"):
+			cancel()
+			select {
+			case <-deleted:
+			case <-time.After(10 * time.Second):
+			}
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/api/2/mappings/"):
+			defer once.Do(func() { close(deleted) })
+		}
+		forward.ServeHTTP(w, r)
+	}))
+	defer proxy.Close()
+
+	p := Provider()
+	if diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"client_id":     fakeapi.ClientID,
+		"client_secret": fakeapi.ClientSecret,
+		"url":           proxy.URL,
+	})); diags.HasError() {
+		t.Fatalf("unexpected error configuring the provider: %v", diags)
+	}
+
+	r := dataSourceUserMappingDryRun()
+	d := r.Data(nil)
+	d.Set("actions", []interface{}{map[string]interface{}{"action": "set_status", "value": []interface{}{"2"}}})
+	d.Set("user_ids", []interface{}{user})
+	if diags := r.ReadContext(ctx, d, p.Meta()); !diags.HasError() {
+		t.Fatal("expected the cancelled read to fail")
+	}
+
+	select {
+	case <-deleted:
+	case <-time.After(10 * time.Second):
+		t.Fatal("expected the mapping created for the dry run to be deleted")
+	}
+	if left := srv.Objects(fakeapi.Mappings); len(left) != 0 {
+		t.Fatalf("expected no mappings left behind, got %v", left)
+	}
+}
+
+// TestDataSourceUserMappingDryRunCleanupFails checks that a mapping the read
+// could not delete is reported where it will be seen, not only in the log.
+func TestDataSourceUserMappingDryRunCleanupFails(t *testing.T) {
+	srv := fakeapi.NewServer()
+	defer srv.Close()
+	user, _ := strconv.Atoi(srv.Seed(fakeapi.Users, map[string]interface{}{"email": "eng@example.com", "status": float64(1)}))
+
+	upstream, _ := url.Parse(srv.URL)
+	forward := httputil.NewSingleHostReverseProxy(upstream)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/api/2/mappings/") {
+			http.Error(w, `{"message": "Forbidden"}`, http.StatusForbidden)
+			return
+		}
+		forward.ServeHTTP(w, r)
+	}))
+	defer proxy.Close()
+
+	p := Provider()
+	if diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"client_id":     fakeapi.ClientID,
+		"client_secret": fakeapi.ClientSecret,
+		"url":           proxy.URL,
+	})); diags.HasError() {
+		t.Fatalf("unexpected error configuring the provider: %v", diags)
+	}
+
+	r := dataSourceUserMappingDryRun()
+	d := r.Data(nil)
+	d.Set("actions", []interface{}{map[string]interface{}{"action": "set_status", "value": []interface{}{"2"}}})
+	d.Set("user_ids", []interface{}{user})
+	diags := r.ReadContext(context.Background(), d, p.Meta())
+	if diags.HasError() {
+		t.Fatalf("expected the read to succeed, got %v", diags)
+	}
+
+	left := srv.Objects(fakeapi.Mappings)
+	if len(left) != 1 {
+		t.Fatalf("expected the mapping to be left behind, got %v", left)
+	}
+	id := sweepID(left[0]["id"])
+	if len(diags) != 1 || diags[0].Severity != diag.Warning || !strings.Contains(diags[0].Summary, id) {
+		t.Fatalf("expected a warning naming mapping %s, got %v", id, diags)
+	}
+}
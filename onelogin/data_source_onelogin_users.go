@@ -1,6 +1,7 @@
 package onelogin
 
 import (
+	"context"
 	"crypto/md5"
 	"encoding/json"
 	"fmt"
@@ -12,17 +13,22 @@ import (
 	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
 	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin/models"
 	userschema "github.com/onelogin/terraform-provider-onelogin/ol_schema/user"
+	"github.com/onelogin/terraform-provider-onelogin/utils"
 )
 
 // Users returns a resource with the CRUD methods and Terraform Schema defined
 func dataSourceUsers() *schema.Resource {
 	return &schema.Resource{
-		Read:   dataSourceUsersRead,
+		ReadContext: utils.ContextOperation(utils.ErrorCategoryRead, "Users", dataSourceUsersRead),
+		// A broad query pages through every matching user, once per email.
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(40 * time.Minute),
+		},
 		Schema: userschema.QuerySchema(),
 	}
 }
 
-func dataSourceUsersRead(d *schema.ResourceData, m interface{}) error {
+func dataSourceUsersRead(ctx context.Context, d *schema.ResourceData, m interface{}) error {
 	client := m.(*onelogin.OneloginSDK)
 	query, _ := userschema.QueryInflate(map[string]interface{}{
 		"username":       d.Get("username"),
@@ -91,7 +97,7 @@ func dataSourceUsersRead(d *schema.ResourceData, m interface{}) error {
 	data := make([]interface{}, 0)
 	seen := make(map[int]bool)
 	for _, q := range queries {
-		result, err := client.GetUsers(q)
+		result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetUsers(q) })
 		if err != nil {
 			log.Printf("[ERROR] There was a problem reading the users!")
 			log.Println(err)
@@ -0,0 +1,292 @@
+package onelogin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
+	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin/models"
+	appschema "github.com/onelogin/terraform-provider-onelogin/ol_schema/app"
+	roleschema "github.com/onelogin/terraform-provider-onelogin/ol_schema/role"
+	"github.com/onelogin/terraform-provider-onelogin/utils"
+)
+
+// importLookup finds the objects an import ID such as "name=Engineering"
+// names, so that importing does not start with a trip to the admin UI to look
+// up a numeric ID. It returns every object the key and value match, as raw API
+// maps, and importer decides what to do with none or several.
+type importLookup func(ctx context.Context, client *onelogin.OneloginSDK, key, value string) ([]map[string]interface{}, error)
+
+// importer returns a ResourceImporter that accepts the resource's own ID, as
+// before, or key=value for any of keys, resolved through lookup. kind names
+// the resource in errors ("role", "user"), and id turns a matched object into
+// the resource's ID.
+func importer(kind string, lookup importLookup, id func(map[string]interface{}) string, keys ...string) *schema.ResourceImporter {
+	return &schema.ResourceImporter{
+		StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+			key, value, ok, err := parseImportLookup(d.Id(), kind, keys)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				return []*schema.ResourceData{d}, nil
+			}
+
+			tflog.Info(ctx, "[IMPORT] Looking up OneLogin "+kind, map[string]interface{}{key: value})
+			matches, err := lookup(ctx, m.(*onelogin.OneloginSDK), key, value)
+			if err != nil {
+				return nil, fmt.Errorf("looking up %s with %s %q: %v", kind, key, value, err)
+			}
+			resolved, err := resolveImportLookup(kind, key, value, matches, id)
+			if err != nil {
+				return nil, err
+			}
+			d.SetId(resolved)
+			return []*schema.ResourceData{d}, nil
+		},
+	}
+}
+
+// parseImportLookup splits an import ID into key and value. ok is false for an
+// ID that names no key, which is imported as it is. An ID that names a key the
+// resource does not look up by is an error rather than an ID: nothing OneLogin
+// issues has an "=" in it, so it is a typo, and saying so beats a 404.
+func parseImportLookup(id, kind string, keys []string) (key, value string, ok bool, err error) {
+	key, value, found := strings.Cut(id, "=")
+	if !found {
+		return "", "", false, nil
+	}
+	for _, k := range keys {
+		if key != k {
+			continue
+		}
+		if value == "" {
+			return "", "", false, fmt.Errorf("import ID %q gives no %s to look the %s up by", id, key, kind)
+		}
+		return key, value, true, nil
+	}
+	return "", "", false, fmt.Errorf("unsupported import ID %q: a %s is imported by its ID or by %s", id, kind, lookupKeysList(keys))
+}
+
+func lookupKeysList(keys []string) string {
+	forms := make([]string, len(keys))
+	for i, k := range keys {
+		forms[i] = k + "=<" + k + ">"
+	}
+	return strings.Join(forms, " or ")
+}
+
+// resolveImportLookup picks the ID to import from what a lookup matched. None
+// and several are both errors; with several, the IDs are listed so the right
+// one can be imported directly.
+func resolveImportLookup(kind, key, value string, matches []map[string]interface{}, id func(map[string]interface{}) string) (string, error) {
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no %s with %s %q was found", kind, key, value)
+	case 1:
+		return id(matches[0]), nil
+	}
+	ids := make([]string, len(matches))
+	for i, o := range matches {
+		ids[i] = id(o)
+	}
+	sort.Strings(ids)
+	return "", fmt.Errorf("%d %ss have %s %q (IDs %s); import the one you mean by its ID instead", len(matches), kind, key, value, strings.Join(ids, ", "))
+}
+
+// objectID is the id of an API object as a string, whether the API gave it as
+// a number or a UUID.
+func objectID(o map[string]interface{}) string {
+	switch v := o["id"].(type) {
+	case float64:
+		return strconv.FormatInt(int64(v), 10)
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// matching keeps the objects whose key equals value. The API's own filters
+// are not relied on to be exact, if they are applied at all.
+func matching(items []interface{}, key, value string, equal func(a, b string) bool) []map[string]interface{} {
+	var out []map[string]interface{}
+	for _, item := range items {
+		o, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if v, ok := o[key].(string); ok && equal(v, value) {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+func exactly(a, b string) bool { return a == b }
+
+// importLookupPageLimit and importLookupMaxPages bound a list walk. The lists
+// are filtered by the API, so one page is the norm; the limit on pages only
+// stops a server that never runs out from hanging the import.
+const (
+	importLookupPageLimit = 100
+	importLookupMaxPages  = 500
+)
+
+// listPages walks a list endpoint by page number until a page comes back
+// short.
+func listPages(ctx context.Context, fetch func(page string) (interface{}, error)) ([]interface{}, error) {
+	var all []interface{}
+	for page := 1; page <= importLookupMaxPages; page++ {
+		p := strconv.Itoa(page)
+		result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return fetch(p) })
+		if err != nil {
+			return nil, err
+		}
+		if result == nil {
+			return all, nil
+		}
+		items, ok := result.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected list response: want a JSON array, got %T", result)
+		}
+		all = append(all, items...)
+		if len(items) < importLookupPageLimit {
+			return all, nil
+		}
+	}
+	return nil, fmt.Errorf("the list exceeded %d pages", importLookupMaxPages)
+}
+
+// roleLookup finds roles by name.
+func roleLookup(ctx context.Context, client *onelogin.OneloginSDK, key, value string) ([]map[string]interface{}, error) {
+	roles, err := listPages(ctx, func(page string) (interface{}, error) {
+		return client.GetRoles(&roleschema.RoleQuery{Name: value, Limit: strconv.Itoa(importLookupPageLimit), Page: page})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return matching(roles, key, value, exactly), nil
+}
+
+// appLookup finds apps by name. authMethods, if given, keeps only apps that
+// sign in with one of them, so that importing a SAML app by name does not
+// pick up an OIDC app that happens to share it.
+func appLookup(authMethods ...int) importLookup {
+	return func(ctx context.Context, client *onelogin.OneloginSDK, key, value string) ([]map[string]interface{}, error) {
+		apps, err := listPages(ctx, func(page string) (interface{}, error) {
+			return client.GetApps(&appschema.AppQuery{Name: value, Limit: strconv.Itoa(importLookupPageLimit), Page: page})
+		})
+		if err != nil {
+			return nil, err
+		}
+		matched := matching(apps, key, value, exactly)
+		if len(authMethods) == 0 {
+			return matched, nil
+		}
+		var out []map[string]interface{}
+		for _, app := range matched {
+			method, ok := app["auth_method"].(float64)
+			if !ok {
+				continue
+			}
+			for _, want := range authMethods {
+				if int(method) == want {
+					out = append(out, app)
+				}
+			}
+		}
+		return out, nil
+	}
+}
+
+// OneLogin's auth_method values for the app resources that are one kind only.
+const (
+	authMethodSAML = 2
+	authMethodOIDC = 8
+)
+
+// userLookup finds users by username or email. OneLogin treats both without
+// regard to case, and so does the match.
+func userLookup(ctx context.Context, client *onelogin.OneloginSDK, key, value string) ([]map[string]interface{}, error) {
+	query := &models.UserQuery{}
+	switch key {
+	case "username":
+		query.Username = &value
+	case "email":
+		query.Email = &value
+	}
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetUsers(query) })
+	if err != nil {
+		return nil, err
+	}
+	users, err := usersFromResponse(result)
+	if err != nil {
+		return nil, err
+	}
+	return matching(users, key, value, strings.EqualFold), nil
+}
+
+// privilegeLookup finds privileges by name.
+func privilegeLookup(ctx context.Context, client *onelogin.OneloginSDK, key, value string) ([]map[string]interface{}, error) {
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.ListPrivileges() })
+	if err != nil {
+		return nil, err
+	}
+	privileges, ok := result.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected privileges response: want a JSON array, got %T", result)
+	}
+	return matching(privileges, key, value, exactly), nil
+}
+
+// environmentVariableLookup finds smart hook environment variables by name.
+func environmentVariableLookup(ctx context.Context, client *onelogin.OneloginSDK, key, value string) ([]map[string]interface{}, error) {
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.ListEnvironmentVariables() })
+	if err != nil {
+		return nil, err
+	}
+	vars, ok := result.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected environment variables response: want a JSON array, got %T", result)
+	}
+	return matching(vars, key, value, exactly), nil
+}
+
+// certificateLookup finds SAML certificates by name.
+func certificateLookup(ctx context.Context, client *onelogin.OneloginSDK, key, value string) ([]map[string]interface{}, error) {
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.ListCertificates() })
+	if err != nil {
+		return nil, err
+	}
+	certs, ok := result.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected certificates response: want a JSON array, got %T", result)
+	}
+	return matching(certs, key, value, exactly), nil
+}
+
+// customAttributeLookup finds custom attribute definitions by shortname.
+func customAttributeLookup(ctx context.Context, client *onelogin.OneloginSDK, key, value string) ([]map[string]interface{}, error) {
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetCustomAttributes() })
+	if err != nil {
+		return nil, err
+	}
+	attrs, ok := result.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected custom attributes response: want a JSON array, got %T", result)
+	}
+	return matching(attrs, key, value, exactly), nil
+}
+
+// customAttributeDefinitionID is the ID a definition is kept under, which
+// distinguishes it from a value set on one user.
+func customAttributeDefinitionID(o map[string]interface{}) string {
+	return "attr_" + objectID(o)
+}
@@ -0,0 +1,123 @@
+package onelogin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/onelogin/terraform-provider-onelogin/fakeapi"
+)
+
+func TestParseImportLookup(t *testing.T) {
+	keys := []string{"username", "email"}
+	tests := []struct {
+		id         string
+		key, value string
+		ok         bool
+		err        string
+	}{
+		{id: "12345"},
+		{id: "attr_12"},
+		{id: "username=jdoe", key: "username", value: "jdoe", ok: true},
+		{id: "email=jdoe@example.com", key: "email", value: "jdoe@example.com", ok: true},
+		// Only the first "=" separates; the rest is the value.
+		{id: "username=a=b", key: "username", value: "a=b", ok: true},
+		{id: "username=", err: "gives no username"},
+		{id: "name=jdoe", err: "username=<username> or email=<email>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			key, value, ok, err := parseImportLookup(tt.id, "user", keys)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected an error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if key != tt.key || value != tt.value || ok != tt.ok {
+				t.Fatalf("expected (%q, %q, %v), got (%q, %q, %v)", tt.key, tt.value, tt.ok, key, value, ok)
+			}
+		})
+	}
+}
+
+// TestImportByLookup imports each resource that supports it by the key it
+// supports, against the fake API.
+func TestImportByLookup(t *testing.T) {
+	srv := fakeapi.NewServer()
+	defer srv.Close()
+	meta := fakeAPIMeta(t, srv)
+
+	engineering := srv.Seed(fakeapi.Roles, map[string]interface{}{"name": "Engineering"})
+	// The API's name filter ignores case; the import does not.
+	srv.Seed(fakeapi.Roles, map[string]interface{}{"name": "engineering"})
+	first := srv.Seed(fakeapi.Roles, map[string]interface{}{"name": "Duplicate"})
+	second := srv.Seed(fakeapi.Roles, map[string]interface{}{"name": "Duplicate"})
+
+	web := srv.Seed(fakeapi.Apps, map[string]interface{}{"name": "Web", "connector_id": float64(1), "auth_method": float64(0)})
+	saml := srv.Seed(fakeapi.Apps, map[string]interface{}{"name": "Shared", "connector_id": float64(2), "auth_method": float64(authMethodSAML)})
+	oidc := srv.Seed(fakeapi.Apps, map[string]interface{}{"name": "Shared", "connector_id": float64(3), "auth_method": float64(authMethodOIDC)})
+
+	jdoe := srv.Seed(fakeapi.Users, map[string]interface{}{"username": "jdoe", "email": "jdoe@example.com"})
+	srv.Seed(fakeapi.Users, map[string]interface{}{"username": "asmith", "email": "asmith@example.com"})
+
+	privilege := srv.Seed(fakeapi.Privileges, map[string]interface{}{"name": "Auditors"})
+	envVar := srv.Seed(fakeapi.SmartHookEnvVars, map[string]interface{}{"name": "API_KEY", "value": "secret"})
+	attr := srv.Seed(fakeapi.CustomAttributes, map[string]interface{}{"name": "Employee ID", "shortname": "employee_id"})
+
+	tests := []struct {
+		name     string
+		resource *schema.Resource
+		id       string
+		want     string
+		err      string
+	}{
+		{name: "role by name", resource: Roles(), id: "name=Engineering", want: engineering},
+		{name: "role by ID", resource: Roles(), id: engineering, want: engineering},
+		{name: "role not found", resource: Roles(), id: "name=Marketing", err: `no role with name "Marketing" was found`},
+		{name: "role ambiguous", resource: Roles(), id: "name=Duplicate", err: fmt.Sprintf("2 roles have name %q", "Duplicate")},
+		{name: "role ambiguous lists IDs", resource: Roles(), id: "name=Duplicate", err: first},
+		{name: "role ambiguous lists both IDs", resource: Roles(), id: "name=Duplicate", err: second},
+		{name: "role by unsupported key", resource: Roles(), id: "email=Engineering", err: "by its ID or by name=<name>"},
+
+		{name: "app by name", resource: Apps(), id: "name=Web", want: web},
+		{name: "app ambiguous across kinds", resource: Apps(), id: "name=Shared", err: "2 apps"},
+		{name: "saml app by name", resource: SAMLApps(), id: "name=Shared", want: saml},
+		{name: "oidc app by name", resource: OIDCApps(), id: "name=Shared", want: oidc},
+		{name: "saml app of another kind", resource: SAMLApps(), id: "name=Web", err: `no SAML app with name "Web"`},
+
+		{name: "user by username", resource: Users(), id: "username=jdoe", want: jdoe},
+		{name: "user by email", resource: Users(), id: "email=JDoe@example.com", want: jdoe},
+		{name: "user not found", resource: Users(), id: "username=nobody", err: "no user with username"},
+
+		{name: "privilege by name", resource: Privileges(), id: "name=Auditors", want: privilege},
+		{name: "environment variable by name", resource: SmarthookEnvironmentVariables(), id: "name=API_KEY", want: envVar},
+		{name: "custom attribute by shortname", resource: UserCustomAttributes(), id: "shortname=employee_id", want: "attr_" + attr},
+		{name: "custom attribute value by ID", resource: UserCustomAttributes(), id: jdoe + "_employee_id", want: jdoe + "_employee_id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := tt.resource.Data(nil)
+			d.SetId(tt.id)
+			out, err := tt.resource.Importer.StateContext(context.Background(), d, meta)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected an error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(out) != 1 || out[0].Id() != tt.want {
+				t.Fatalf("expected ID %q, got %v", tt.want, out)
+			}
+		})
+	}
+}
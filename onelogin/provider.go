@@ -2,20 +2,18 @@ package onelogin
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"os"
-	"strconv"
+	"net"
+	"net/url"
+	"regexp"
 	"strings"
-
-	ol "github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-)
 
-var (
-	errClientCredentials = errors.New("client_id or client_secret missing")
+	"github.com/onelogin/terraform-provider-onelogin/apiclient"
+	"github.com/onelogin/terraform-provider-onelogin/utils"
 )
 
 // Provider creates a new provider with all the neccessary configurations.
@@ -37,7 +35,14 @@ func Provider() *schema.Provider {
 				Type:        schema.TypeString,
 				DefaultFunc: schema.EnvDefaultFunc("ONELOGIN_API_URL", nil),
 				Required:    true,
-				Description: "OneLogin API URL (e.g. https://api.us.onelogin.com or https://api.eu.onelogin.com)",
+				Description: "OneLogin API URL: a regional API host such as https://api.us.onelogin.com, a tenant's https://<subdomain>.onelogin.com, http://localhost for a local stand-in, or any other host together with subdomain.",
+			},
+			"subdomain": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ONELOGIN_SUBDOMAIN", ""),
+				ValidateFunc: validSubdomain,
+				Description:  "The tenant's subdomain, the first part of <subdomain>.onelogin.com. Only needed when url is a host the subdomain cannot be read from, such as a proxy or a private API endpoint; url is still required.",
 			},
 			"timeout": {
 				Type:        schema.TypeInt,
@@ -45,23 +50,133 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("ONELOGIN_TIMEOUT", 180),
 				Description: "Timeout in seconds for API operations. Defaults to 180 seconds if not specified.",
 			},
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ONELOGIN_MAX_RETRIES", 3),
+				ValidateFunc: validNonNegativeInt,
+				Description:  "How many times a request that failed with a 429, a 5xx or a dropped connection is retried before the error is reported. POST requests are only retried on a 429. Set to 0 to disable retries. Defaults to 3.",
+			},
+			"min_backoff": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ONELOGIN_MIN_BACKOFF", 1),
+				ValidateFunc: validNonNegativeInt,
+				Description:  "Shortest wait in seconds before a retry. Each further retry waits roughly twice as long. Defaults to 1.",
+			},
+			"max_backoff": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ONELOGIN_MAX_BACKOFF", 30),
+				ValidateFunc: validNonNegativeInt,
+				Description:  "Longest wait in seconds before a retry. A Retry-After header from the API takes precedence. Defaults to 30.",
+			},
+			"max_requests_per_second": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ONELOGIN_MAX_REQUESTS_PER_SECOND", 0),
+				ValidateFunc: validNonNegativeInt,
+				Description:  "Cap on requests per second across everything this provider instance does. Independently of it, requests are paced from OneLogin's X-RateLimit headers once less than half the window's budget remains. Defaults to 0, no cap.",
+			},
+			"token_cache_dir": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ONELOGIN_TOKEN_CACHE_DIR", ""),
+				Description: "Directory the access token is kept in between runs, so that parallel and successive runs with the same client ID and API host share one token. The file is readable by its owner only. Unset by default: the token is then shared only within one run.",
+			},
+			"proxy_url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ONELOGIN_PROXY_URL", ""),
+				Description: "Proxy every request is sent through, as an http, https or socks5 URL. When unset, HTTPS_PROXY, HTTP_PROXY and NO_PROXY apply.",
+			},
+			"ca_cert_pem": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ONELOGIN_CA_CERT_PEM", ""),
+				Description: "PEM-encoded CA certificates to trust in addition to the system's, such as a TLS-inspecting proxy's.",
+			},
+			"ca_cert_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ONELOGIN_CA_CERT_FILE", ""),
+				Description: "Path to a file of PEM-encoded CA certificates to trust in addition to the system's.",
+			},
+			"client_cert_pem": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("ONELOGIN_CLIENT_CERT_PEM", ""),
+				ConflictsWith: []string{"client_cert_file"},
+				Description:   "PEM-encoded client certificate presented to a server that asks for one. Requires a client key.",
+			},
+			"client_key_pem": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				DefaultFunc:   schema.EnvDefaultFunc("ONELOGIN_CLIENT_KEY_PEM", ""),
+				ConflictsWith: []string{"client_key_file"},
+				Description:   "PEM-encoded private key of the client certificate.",
+			},
+			"client_cert_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ONELOGIN_CLIENT_CERT_FILE", ""),
+				Description: "Path to a PEM-encoded client certificate. Requires a client key.",
+			},
+			"client_key_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ONELOGIN_CLIENT_KEY_FILE", ""),
+				Description: "Path to the PEM-encoded private key of the client certificate.",
+			},
+			"insecure_skip_verify": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ONELOGIN_INSECURE_SKIP_VERIFY", false),
+				Description: "Accept any certificate the API presents. For labs with self-signed endpoints only: credentials and tokens are sent to whoever answers.",
+			},
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"onelogin_user":   dataSourceUser(),
-			"onelogin_users":  dataSourceUsers(),
-			"onelogin_group":  dataSourceOneLoginGroup(),
-			"onelogin_groups": dataSourceOneLoginGroups(),
+			"onelogin_user":                             dataSourceUser(),
+			"onelogin_users":                            dataSourceUsers(),
+			"onelogin_group":                            dataSourceOneLoginGroup(),
+			"onelogin_groups":                           dataSourceOneLoginGroups(),
+			"onelogin_role":                             dataSourceRole(),
+			"onelogin_roles":                            dataSourceRoles(),
+			"onelogin_app":                              dataSourceApp(),
+			"onelogin_apps":                             dataSourceApps(),
+			"onelogin_saml_app_metadata":                dataSourceSAMLAppMetadata(),
+			"onelogin_app_rule_conditions":              dataSourceAppRuleConditions(),
+			"onelogin_app_rule_condition_operators":     dataSourceAppRuleConditionOperators(),
+			"onelogin_app_rule_condition_values":        dataSourceAppRuleConditionValues(),
+			"onelogin_app_rule_actions":                 dataSourceAppRuleActions(),
+			"onelogin_app_rule_action_values":           dataSourceAppRuleActionValues(),
+			"onelogin_user_mapping_conditions":          dataSourceUserMappingConditions(),
+			"onelogin_user_mapping_condition_operators": dataSourceUserMappingConditionOperators(),
+			"onelogin_user_mapping_condition_values":    dataSourceUserMappingConditionValues(),
+			"onelogin_user_mapping_actions":             dataSourceUserMappingActions(),
+			"onelogin_user_mapping_action_values":       dataSourceUserMappingActionValues(),
+			"onelogin_user_mapping_dry_run":             dataSourceUserMappingDryRun(),
 		},
 		ResourcesMap: map[string]*schema.Resource{
 			"onelogin_app_role_attachments":            AppRoleAttachment(),
 			"onelogin_apps":                            Apps(),
 			"onelogin_oidc_apps":                       OIDCApps(),
 			"onelogin_saml_apps":                       SAMLApps(),
+			"onelogin_saml_certificate":                SAMLCertificate(),
 			"onelogin_app_rules":                       AppRules(),
+			"onelogin_app_rules_order":                 AppRulesOrder(),
 			"onelogin_user_mappings":                   UserMappings(),
+			"onelogin_user_mappings_order":             UserMappingsOrder(),
 			"onelogin_users":                           Users(),
 			"onelogin_auth_servers":                    AuthServers(),
+			"onelogin_auth_server_scope":               AuthServerScope(),
+			"onelogin_auth_server_claim":               AuthServerClaim(),
+			"onelogin_auth_server_client_app":          AuthServerClientApp(),
 			"onelogin_roles":                           Roles(),
+			"onelogin_role_user":                       RoleUser(),
+			"onelogin_role_admin":                      RoleAdmin(),
+			"onelogin_role_app":                        RoleApp(),
 			"onelogin_smarthooks":                      SmartHooks(),
 			"onelogin_smarthook_environment_variables": SmarthookEnvironmentVariables(),
 			"onelogin_privileges":                      Privileges(),
@@ -73,67 +188,167 @@ func Provider() *schema.Provider {
 	}
 }
 
+// placeholderSubdomain is handed to the SDK for an API host that has no
+// tenant subdomain in it.
+const placeholderSubdomain = "dummy"
+
+// dnsLabel is one label of a host name, as OneLogin subdomains and region
+// names are.
+var dnsLabel = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// acceptedURLForms is the end of every error about a url the provider cannot
+// read a subdomain from.
+const acceptedURLForms = "expected https://<subdomain>.onelogin.com, https://api.<region>.onelogin.com " +
+	"or http://localhost[:port]; for any other host, such as a proxy or a private endpoint, set subdomain as well"
+
+// tenantSubdomain is the subdomain the SDK is given for this provider
+// instance: the subdomain argument when it is set, and otherwise whatever
+// subdomainFromURL reads from the url.
+//
+// Only the SDK's authenticator wants a subdomain, to be constructed; every
+// call goes to the url regardless. So an explicit one makes any url
+// acceptable -- a proxy in front of the API, a private endpoint -- but one that
+// contradicts a subdomain in the url is a mistake in one or the other.
+func tenantSubdomain(rawURL, subdomain string) (string, error) {
+	if subdomain == "" {
+		return subdomainFromURL(rawURL)
+	}
+	if !dnsLabel.MatchString(subdomain) {
+		return "", fmt.Errorf("invalid subdomain %q: expected the first part of <subdomain>.onelogin.com, letters, digits and hyphens only", subdomain)
+	}
+	if fromURL, err := subdomainFromURL(rawURL); err == nil && fromURL != placeholderSubdomain && fromURL != subdomain {
+		return "", fmt.Errorf("subdomain %q does not match url %q, which is for the %q subdomain", subdomain, rawURL, fromURL)
+	}
+	return subdomain, nil
+}
+
 // subdomainFromURL pulls the tenant subdomain out of the configured API URL.
 //
-// The SDK still asks for ONELOGIN_SUBDOMAIN even though every call goes to
-// ONELOGIN_API_URL, so this exists to satisfy it. A regional API host has no
-// tenant subdomain in it at all, hence the placeholder.
-func subdomainFromURL(url string) (string, error) {
-	parts := strings.Split(strings.TrimPrefix(strings.TrimPrefix(url, "https://"), "http://"), ".")
+// It understands three kinds of host. <subdomain>.onelogin.com is a tenant's
+// own, and gives its subdomain. api.<region>.onelogin.com is a regional API
+// host, for any region, and localhost is a stand-in for the API in tests;
+// neither carries a subdomain, so the SDK is given a placeholder. Any other
+// host is refused with the forms that are accepted, because nothing can be
+// read from it: a subdomain argument is needed instead.
+func subdomainFromURL(rawURL string) (string, error) {
+	if rawURL == "" {
+		return "", fmt.Errorf("url is required: %s", acceptedURLForms)
+	}
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid url %q: %v", rawURL, err)
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return "", fmt.Errorf("invalid url %q: the scheme must be https or http", rawURL)
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" {
+		return placeholderSubdomain, nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return placeholderSubdomain, nil
+	}
 
+	labels := strings.Split(strings.TrimSuffix(host, ".onelogin.com"), ".")
 	switch {
-	case len(parts) > 0 && parts[0] != "" && parts[0] != "api":
-		// Direct subdomain URL (e.g., company.onelogin.com)
-		return parts[0], nil
-	case len(parts) > 1 && parts[0] == "api":
-		// API URL format (e.g., api.us.onelogin.com or api.eu.onelogin.com)
-		if region := parts[1]; region != "us" && region != "eu" {
-			return "", fmt.Errorf("invalid API URL format, expected api.us.onelogin.com or api.eu.onelogin.com, got %q", url)
-		}
-		return "dummy", nil
+	case !strings.HasSuffix(host, ".onelogin.com"):
+		return "", fmt.Errorf("cannot tell the subdomain from url %q: %s", rawURL, acceptedURLForms)
+	case len(labels) == 1 && labels[0] != "api" && dnsLabel.MatchString(labels[0]):
+		return labels[0], nil
+	case len(labels) == 2 && labels[0] == "api" && dnsLabel.MatchString(labels[1]):
+		return placeholderSubdomain, nil
 	default:
-		return "", fmt.Errorf("could not extract a subdomain from %q, please provide a valid OneLogin URL", url)
+		return "", fmt.Errorf("invalid OneLogin url %q: %s", rawURL, acceptedURLForms)
+	}
+}
+
+// validSubdomain checks the shape of the subdomain argument at plan time.
+func validSubdomain(val interface{}, key string) (warns []string, errs []error) {
+	v, ok := val.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("%s: expected a string, got %T", key, val)}
 	}
+	if v != "" && !dnsLabel.MatchString(v) {
+		errs = append(errs, fmt.Errorf("%s: %q is not a subdomain; expected the first part of <subdomain>.onelogin.com, letters, digits and hyphens only", key, v))
+	}
+	return warns, errs
+}
+
+// validNonNegativeInt rejects a negative count or duration at plan time, rather
+// than leaving it to surface as a configuration error once the provider starts.
+func validNonNegativeInt(val interface{}, key string) (warns []string, errs []error) {
+	v, ok := val.(int)
+	if !ok {
+		return nil, []error{fmt.Errorf("%s: expected an integer, got %T", key, val)}
+	}
+	if v < 0 {
+		errs = append(errs, fmt.Errorf("%s must be 0 or greater, got %d", key, v))
+	}
+	return warns, errs
 }
 
 // configProvider configures the provider, and if successful, it returns
 // an interface containing the api client.
+//
+// Nothing here touches the process environment. Every aliased provider block
+// is configured in the same plugin process, so credentials written to
+// ONELOGIN_* variables were shared between them, and two blocks for two
+// tenants overwrote each other. Each instance now builds a client of its own.
 func configProvider(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
-	clientID := d.Get("client_id").(string)
-	clientSecret := d.Get("client_secret").(string)
-
-	// Set client credentials environment variables for the SDK
-	os.Setenv("ONELOGIN_CLIENT_ID", clientID)
-	os.Setenv("ONELOGIN_CLIENT_SECRET", clientSecret)
-
-	// Set a longer timeout for API operations
-	timeout := d.Get("timeout").(int)
-	os.Setenv("ONELOGIN_TIMEOUT", strconv.Itoa(timeout))
-	// Keep setting the old env var for backward compatibility
-	os.Setenv("ONELOGIN_CLIENT_TIMEOUT", strconv.Itoa(timeout))
-
-	// Set the API URL
 	url := d.Get("url").(string)
 	if url == "" {
 		return nil, diag.Errorf("OneLogin API URL is required. Please set the ONELOGIN_API_URL environment variable.")
 	}
 
-	// Set API URL for SDK
-	os.Setenv("ONELOGIN_API_URL", url)
+	cfg := apiclient.Config{
+		ClientID:     d.Get("client_id").(string),
+		ClientSecret: d.Get("client_secret").(string),
+		URL:          url,
+		Timeout:      time.Duration(d.Get("timeout").(int)) * time.Second,
+		MaxRetries:   d.Get("max_retries").(int),
+		MinBackoff:   time.Duration(d.Get("min_backoff").(int)) * time.Second,
+		MaxBackoff:   time.Duration(d.Get("max_backoff").(int)) * time.Second,
 
-	// Extract subdomain from URL for backward compatibility with SDK's internals
-	// Most SDK functions still use the subdomain internally
-	subdomain, err := subdomainFromURL(url)
-	if err != nil {
-		return nil, diag.FromErr(err)
+		MaxRequestsPerSecond: float64(d.Get("max_requests_per_second").(int)),
+
+		TokenCacheDir: d.Get("token_cache_dir").(string),
+
+		ProxyURL:           d.Get("proxy_url").(string),
+		CACertPEM:          d.Get("ca_cert_pem").(string),
+		CACertFile:         d.Get("ca_cert_file").(string),
+		ClientCertPEM:      d.Get("client_cert_pem").(string),
+		ClientKeyPEM:       d.Get("client_key_pem").(string),
+		ClientCertFile:     d.Get("client_cert_file").(string),
+		ClientKeyFile:      d.Get("client_key_file").(string),
+		InsecureSkipVerify: d.Get("insecure_skip_verify").(bool),
+	}
+
+	// Skipping verification is allowed, not quietly accepted.
+	var diags diag.Diagnostics
+	if cfg.InsecureSkipVerify {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "TLS certificate verification is disabled",
+			Detail:   "insecure_skip_verify is set, so the OneLogin API's certificate is not checked and the client secret and access tokens are sent to whichever server answers. Use ca_cert_pem or ca_cert_file to trust a private CA instead.",
+		})
+	}
+
+	// With TF_LOG at DEBUG or TRACE every request and response is logged,
+	// redacted, so a rejected payload can be seen rather than guessed at.
+	// ctx is the one place a logger that lasts as long as the client is
+	// available.
+	if utils.DebugLoggingEnabled() {
+		cfg.LogContext = ctx
 	}
-	os.Setenv("ONELOGIN_SUBDOMAIN", subdomain)
 
-	// Initialize the SDK
-	client, err := ol.NewOneloginSDK()
+	client, err := newOneloginSDK(cfg, d.Get("subdomain").(string))
 	if err != nil {
-		return nil, diag.FromErr(err)
+		return nil, append(diags, diag.FromErr(err)...)
 	}
 
-	return client, nil
+	return client, diags
 }
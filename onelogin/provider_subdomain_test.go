@@ -1,6 +1,9 @@
 package onelogin
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 // TestSubdomainFromURL pins the shapes the provider accepts for ONELOGIN_API_URL.
 // The SDK still asks for a subdomain even though every call goes to the URL, and
@@ -13,10 +16,18 @@ func TestSubdomainFromURL(t *testing.T) {
 		{"https://chicken.onelogin.com", "chicken"},
 		{"http://chicken.onelogin.com", "chicken"},
 		{"chicken.onelogin.com", "chicken"},
+		{"https://Chicken.OneLogin.com/", "chicken"},
 		// A regional API host carries no tenant subdomain, so the SDK gets a
-		// placeholder to satisfy its own requirement.
+		// placeholder to satisfy its own requirement. Any region: the
+		// provider does not keep a list of OneLogin's shards.
 		{"https://api.us.onelogin.com", "dummy"},
 		{"https://api.eu.onelogin.com", "dummy"},
+		{"https://api.ap.onelogin.com", "dummy"},
+		{"https://api.us2.onelogin.com:443", "dummy"},
+		// Local stand-ins for the API in tests.
+		{"http://localhost:8080", "dummy"},
+		{"http://127.0.0.1:41234", "dummy"},
+		{"http://[::1]:41234", "dummy"},
 	} {
 		got, err := subdomainFromURL(tc.url)
 		if err != nil {
@@ -27,9 +38,58 @@ func TestSubdomainFromURL(t *testing.T) {
 		}
 	}
 
-	for _, url := range []string{"https://api.ap.onelogin.com", ""} {
-		if got, err := subdomainFromURL(url); err == nil {
+	for _, url := range []string{
+		"",
+		"https://onelogin.com",
+		"https://api.onelogin.com",
+		"https://a.b.onelogin.com",
+		"https://api.us.onelogin.com.evil.example",
+		"ftp://chicken.onelogin.com",
+		// Fine as a url, but only with a subdomain alongside.
+		"https://onelogin-proxy.corp.example.com",
+	} {
+		got, err := subdomainFromURL(url)
+		if err == nil {
 			t.Fatalf("%q: expected an error, got %q", url, got)
 		}
+		if url != "" && !strings.Contains(err.Error(), url) {
+			t.Fatalf("%q: expected the error to name the url, got %v", url, err)
+		}
 	}
 }
+
+func TestTenantSubdomain(t *testing.T) {
+	t.Run("the argument makes any host acceptable", func(t *testing.T) {
+		got, err := tenantSubdomain("https://onelogin-proxy.corp.example.com/api", "chicken")
+		if err != nil || got != "chicken" {
+			t.Fatalf("expected chicken, got %q, %v", got, err)
+		}
+	})
+
+	t.Run("the argument replaces a placeholder", func(t *testing.T) {
+		got, err := tenantSubdomain("https://api.eu.onelogin.com", "chicken")
+		if err != nil || got != "chicken" {
+			t.Fatalf("expected chicken, got %q, %v", got, err)
+		}
+	})
+
+	t.Run("the url is read when the argument is unset", func(t *testing.T) {
+		got, err := tenantSubdomain("https://chicken.onelogin.com", "")
+		if err != nil || got != "chicken" {
+			t.Fatalf("expected chicken, got %q, %v", got, err)
+		}
+	})
+
+	t.Run("an argument contradicting the url is refused", func(t *testing.T) {
+		_, err := tenantSubdomain("https://chicken.onelogin.com", "duck")
+		if err == nil || !strings.Contains(err.Error(), "duck") {
+			t.Fatalf("expected the mismatch reported, got %v", err)
+		}
+	})
+
+	t.Run("a subdomain that is not one is refused", func(t *testing.T) {
+		if _, err := tenantSubdomain("https://api.us.onelogin.com", "chicken.onelogin.com"); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package onelogin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
+)
+
+// tenantServer stands in for one OneLogin account. It issues a token only for
+// its own credentials, answers only requests carrying that token, and counts
+// anything that arrives with another tenant's.
+func tenantServer(t *testing.T, name string, foreign *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/auth/oauth2/v2/token" {
+			id, secret, _ := r.BasicAuth()
+			if id != name+"-id" || secret != name+"-secret" {
+				foreign.Add(1)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"access_token": name + "-token", "expires_in": 36000})
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer "+name+"-token" {
+			foreign.Add(1)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if strings.HasPrefix(r.URL.Path, "/api/2/roles/") {
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": 1, "name": name})
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// TestProviderInstancesKeepTheirOwnTenant configures two providers at once,
+// the way Terraform does for aliased blocks in one root module, and checks that
+// every request each makes reaches its own tenant with its own credentials.
+//
+// Configuration used to export client_id, client_secret and url to ONELOGIN_*
+// environment variables for the SDK to read back. The plugin process is shared
+// by every provider block, so whichever configured last supplied credentials
+// for all of them.
+func TestProviderInstancesKeepTheirOwnTenant(t *testing.T) {
+	var foreign atomic.Int32
+	tenants := map[string]*httptest.Server{
+		"us": tenantServer(t, "us", &foreign),
+		"eu": tenantServer(t, "eu", &foreign),
+	}
+
+	envBefore := os.Environ()
+
+	providers := map[string]*schema.Provider{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, srv := range tenants {
+		wg.Add(1)
+		go func(name, url string) {
+			defer wg.Done()
+			p := Provider()
+			diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+				"client_id":     name + "-id",
+				"client_secret": name + "-secret",
+				"url":           url,
+			}))
+			if diags.HasError() {
+				t.Errorf("configuring %s: %v", name, diags)
+				return
+			}
+			mu.Lock()
+			providers[name] = p
+			mu.Unlock()
+		}(name, srv.URL)
+	}
+	wg.Wait()
+	if t.Failed() {
+		t.FailNow()
+	}
+
+	if strings.Join(envBefore, "\n") != strings.Join(os.Environ(), "\n") {
+		t.Fatal("expected configuring providers to leave the process environment unchanged")
+	}
+
+	for round := 0; round < 10; round++ {
+		for name, p := range providers {
+			wg.Add(1)
+			go func(name string, client *onelogin.OneloginSDK) {
+				defer wg.Done()
+				result, err := client.GetRoleByIDWithContext(context.Background(), 1, nil)
+				if err != nil {
+					t.Errorf("%s: %v", name, err)
+					return
+				}
+				role, _ := result.(map[string]interface{})
+				if role["name"] != name {
+					t.Errorf("%s provider read a role from %v", name, role["name"])
+				}
+			}(name, p.Meta().(*onelogin.OneloginSDK))
+		}
+	}
+	wg.Wait()
+
+	if n := foreign.Load(); n != 0 {
+		t.Fatalf("%d requests reached a tenant with another tenant's credentials", n)
+	}
+}
@@ -1,16 +1,34 @@
 package onelogin
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"os"
+	"strings"
+	"sync"
 	"testing"
 
+	"github.com/hashicorp/go-cty/cty"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/onelogin/terraform-provider-onelogin/fakeapi"
 )
 
 var testAccProviders map[string]*schema.Provider
 var testAccProvider *schema.Provider
 var testAccProviderFactories map[string]func() (*schema.Provider, error)
 
+// testAccFakeAPI is the in-process tenant acceptance tests run against when
+// ONELOGIN_FAKE_API is set. It is started by the first TestAccPreCheck and
+// shared by every test in the run, as a real tenant would be.
+var (
+	testAccFakeAPI     *fakeapi.Server
+	testAccFakeAPIOnce sync.Once
+)
+
 func init() {
 	testAccProvider = Provider()
 	testAccProviders = map[string]*schema.Provider{
@@ -31,6 +49,104 @@ func TestProvider(t *testing.T) {
 	}
 }
 
+// TestEveryResourceHasTimeouts keeps a new resource or data source from
+// shipping without a timeouts block, or with operations the SDK would not give
+// a deadline. The plain Create/Read/Update/Delete signatures get none, so a
+// timeouts block on such a resource would be accepted and then ignored.
+func TestEveryResourceHasTimeouts(t *testing.T) {
+	p := Provider()
+
+	for name, r := range p.ResourcesMap {
+		if r.Timeouts == nil {
+			t.Errorf("%s has no timeouts", name)
+		}
+		if r.Create != nil || r.Read != nil || r.Update != nil || r.Delete != nil {
+			t.Errorf("%s uses an operation without a context, which gets no deadline", name)
+		}
+	}
+
+	for name, r := range p.DataSourcesMap {
+		if r.Timeouts == nil || r.Timeouts.Read == nil {
+			t.Errorf("data source %s has no read timeout", name)
+		}
+		if r.Read != nil {
+			t.Errorf("data source %s reads without a context, which gets no deadline", name)
+		}
+	}
+}
+
+// fakeAPIMeta configures a provider instance against srv and returns what its
+// resources are handed as meta, for a test that calls them directly.
+func fakeAPIMeta(t *testing.T, srv *fakeapi.Server) interface{} {
+	t.Helper()
+
+	p := Provider()
+	diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"client_id":     fakeapi.ClientID,
+		"client_secret": fakeapi.ClientSecret,
+		"url":           srv.URL,
+	}))
+	if diags.HasError() {
+		t.Fatalf("unexpected error configuring the provider: %v", diags)
+	}
+	return p.Meta()
+}
+
+// readDataSource reads data source r with config against meta, as a plan
+// would. The configuration is given as raw config too, so that a read which
+// tells an argument set to its zero value from one left out can.
+func readDataSource(t *testing.T, r *schema.Resource, meta interface{}, config map[string]interface{}) (*schema.ResourceData, error) {
+	t.Helper()
+
+	diff, err := schema.InternalMap(r.Schema).Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), nil, meta, true)
+	if err != nil {
+		t.Fatalf("unexpected error planning the read: %v", err)
+	}
+	if diff == nil {
+		diff = terraform.NewInstanceDiff()
+	}
+	raw, _ := json.Marshal(config)
+	if diff.RawConfig, err = ctyjson.Unmarshal(raw, r.CoreConfigSchema().ImpliedType()); err != nil {
+		t.Fatalf("unexpected error decoding the configuration: %v", err)
+	}
+	d, err := schema.InternalMap(r.Schema).Data(nil, diff)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if diags := r.ReadContext(context.Background(), d, meta); diags.HasError() {
+		var msgs []string
+		for _, diag := range diags {
+			msgs = append(msgs, diag.Summary)
+		}
+		return d, fmt.Errorf("%s", strings.Join(msgs, "; "))
+	}
+	return d, nil
+}
+
+// planResource plans creating r with config against meta, as a plan would,
+// so that its CustomizeDiff runs. The top-level arguments named in unknown are
+// left unknown, the way a reference to something not created yet is.
+func planResource(t *testing.T, r *schema.Resource, meta interface{}, config map[string]interface{}, unknown ...string) error {
+	t.Helper()
+
+	raw, _ := json.Marshal(config)
+	val, err := ctyjson.Unmarshal(raw, r.CoreConfigSchema().ImpliedType())
+	if err != nil {
+		t.Fatalf("unexpected error decoding the configuration: %v", err)
+	}
+	if len(unknown) > 0 {
+		attrs := val.AsValueMap()
+		for _, k := range unknown {
+			attrs[k] = cty.UnknownVal(attrs[k].Type())
+		}
+		val = cty.ObjectVal(attrs)
+	}
+
+	_, err = r.Diff(context.Background(), &terraform.InstanceState{RawConfig: val}, terraform.NewResourceConfigShimmed(val, r.CoreConfigSchema()), meta)
+	return err
+}
+
 // TestAccPreCheck performs a check to ensure requisite credentials are in
 // the environment and stops further testing if a problem is found
 func TestAccPreCheck(t *testing.T) {
@@ -38,6 +154,19 @@ func TestAccPreCheck(t *testing.T) {
 		t.Skip("Skipping acceptance test in short mode")
 	}
 
+	// ONELOGIN_FAKE_API points the run at an in-process fake instead of a
+	// tenant, so the suite can run without credentials or network access. The
+	// fake stands in for whatever ONELOGIN_* settings are already exported:
+	// a run that sets it never touches a real account.
+	if os.Getenv("ONELOGIN_FAKE_API") != "" {
+		testAccFakeAPIOnce.Do(func() {
+			testAccFakeAPI = fakeapi.NewServer()
+		})
+		os.Setenv("ONELOGIN_CLIENT_ID", fakeapi.ClientID)
+		os.Setenv("ONELOGIN_CLIENT_SECRET", fakeapi.ClientSecret)
+		os.Setenv("ONELOGIN_API_URL", testAccFakeAPI.URL)
+	}
+
 	// Check for client credentials
 	if v := os.Getenv("ONELOGIN_CLIENT_ID"); v == "" {
 		t.Fatal("ONELOGIN_CLIENT_ID must be set for acceptance tests")
@@ -69,3 +198,60 @@ func TestAccPreCheck(t *testing.T) {
 		t.Logf("Using existing ONELOGIN_CLIENT_TIMEOUT: %s", os.Getenv("ONELOGIN_CLIENT_TIMEOUT"))
 	}
 }
+
+// TestConfigureRejectsInvertedBackoff checks the one retry setting that cannot
+// be validated attribute by attribute.
+func TestConfigureRejectsInvertedBackoff(t *testing.T) {
+	diags := Provider().Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"client_id":     "id",
+		"client_secret": "secret",
+		"url":           "https://api.us.onelogin.com",
+		"min_backoff":   10,
+		"max_backoff":   2,
+	}))
+
+	if !diags.HasError() {
+		t.Fatal("expected a max_backoff below min_backoff to be rejected")
+	}
+	if !strings.Contains(fmt.Sprint(diags), "max_backoff") {
+		t.Fatalf("expected the error to name max_backoff, got %v", diags)
+	}
+}
+
+func TestConfigureNetworkOptions(t *testing.T) {
+	configure := func(extra map[string]interface{}) diag.Diagnostics {
+		raw := map[string]interface{}{
+			"client_id":     "id",
+			"client_secret": "secret",
+			"url":           "https://api.us.onelogin.com",
+		}
+		for k, v := range extra {
+			raw[k] = v
+		}
+		return Provider().Configure(context.Background(), terraform.NewResourceConfigRaw(raw))
+	}
+
+	t.Run("a bad proxy_url is an error", func(t *testing.T) {
+		diags := configure(map[string]interface{}{"proxy_url": "ftp://proxy.corp"})
+		if !diags.HasError() || !strings.Contains(fmt.Sprint(diags), "proxy_url") {
+			t.Fatalf("expected an error naming proxy_url, got %v", diags)
+		}
+	})
+
+	t.Run("a certificate without its key is an error", func(t *testing.T) {
+		diags := configure(map[string]interface{}{"client_cert_file": "/tmp/client.pem"})
+		if !diags.HasError() {
+			t.Fatal("expected a certificate without a key to be rejected")
+		}
+	})
+
+	t.Run("insecure_skip_verify warns", func(t *testing.T) {
+		diags := configure(map[string]interface{}{"insecure_skip_verify": true})
+		if diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+		if len(diags) != 1 || diags[0].Severity != diag.Warning {
+			t.Fatalf("expected a single warning, got %v", diags)
+		}
+	})
+}
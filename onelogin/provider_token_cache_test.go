@@ -0,0 +1,44 @@
+package onelogin
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/onelogin/terraform-provider-onelogin/fakeapi"
+)
+
+// TestProviderInstancesShareAToken configures two provider instances with the
+// same credentials, as aliased blocks or two modules would, and drives a run's
+// worth of resource operations through both. Every one of them is served by
+// a single token.
+func TestProviderInstancesShareAToken(t *testing.T) {
+	srv := fakeapi.NewServer()
+	defer srv.Close()
+
+	instances := []interface{}{fakeAPIMeta(t, srv), fakeAPIMeta(t, srv)}
+
+	roles := Roles()
+	for i := 0; i < 10; i++ {
+		meta := instances[i%len(instances)]
+		d := schema.TestResourceDataRaw(t, roles.Schema, map[string]interface{}{
+			"name": fmt.Sprintf("acctest-shared-token-%d", i),
+		})
+		ctx := context.Background()
+		if diags := roles.CreateContext(ctx, d, meta); diags.HasError() {
+			t.Fatalf("unexpected error creating a role: %v", diags)
+		}
+		if diags := roles.ReadContext(ctx, d, meta); diags.HasError() {
+			t.Fatalf("unexpected error reading a role: %v", diags)
+		}
+		if diags := roles.DeleteContext(ctx, d, meta); diags.HasError() {
+			t.Fatalf("unexpected error deleting a role: %v", diags)
+		}
+	}
+
+	if n := srv.TokenRequests(); n != 1 {
+		t.Fatalf("expected one token for every operation, got %d", n)
+	}
+}
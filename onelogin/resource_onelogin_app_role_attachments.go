@@ -2,13 +2,17 @@ package onelogin
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
 	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin/models"
+	"github.com/onelogin/terraform-provider-onelogin/utils"
 )
 
 // AppRoleAttachment attaches additional configuration and sso schemas and
@@ -19,19 +23,133 @@ func AppRoleAttachment() *schema.Resource {
 		ReadContext:   appRoleAttachmentRead,
 		UpdateContext: appRoleAttachmentUpdate,
 		DeleteContext: appRoleAttachmentDelete,
-		Schema: map[string]*schema.Schema{
-			"role_id": {
-				Type:     schema.TypeInt,
-				Required: true,
-			},
-			"app_id": {
-				Type:     schema.TypeInt,
-				Required: true,
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(20 * time.Minute),
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: appRoleAttachmentImport,
+		},
+		Schema:        appRoleAttachmentSchema(),
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    (&schema.Resource{Schema: appRoleAttachmentSchema()}).CoreConfigSchema().ImpliedType(),
+				Upgrade: upgradeAppRoleAttachmentIDV0,
 			},
 		},
 	}
 }
 
+func appRoleAttachmentSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"role_id": {
+			Type:     schema.TypeInt,
+			Required: true,
+		},
+		"app_id": {
+			Type:     schema.TypeInt,
+			Required: true,
+		},
+	}
+}
+
+// appRoleAttachmentID is the attachment's ID, "app_id:role_id".
+//
+// It used to be the two numbers run together, role first, which made role 1 on
+// app 23 and role 12 on app 3 the same attachment, and left an ID nothing could
+// be parsed back out of -- hence no import.
+func appRoleAttachmentID(appID, roleID int) string {
+	return fmt.Sprintf("%d:%d", appID, roleID)
+}
+
+func parseAppRoleAttachmentID(id string) (appID, roleID int, err error) {
+	app, role, err := utils.ParseNestedResourceImportId(id)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unexpected format of ID (%s), expected app_id:role_id", id)
+	}
+	if appID, err = strconv.Atoi(app); err != nil {
+		return 0, 0, fmt.Errorf("invalid app_id %q in ID (%s): %v", app, id, err)
+	}
+	if roleID, err = strconv.Atoi(role); err != nil {
+		return 0, 0, fmt.Errorf("invalid role_id %q in ID (%s): %v", role, id, err)
+	}
+	return appID, roleID, nil
+}
+
+// appRoleAttachmentImport adopts an existing attachment by "app_id:role_id".
+// The attachment has to exist: importing one that does not would put a
+// resource in state that the next refresh silently takes out again.
+func appRoleAttachmentImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	appID, roleID, err := parseAppRoleAttachmentID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	client := m.(*onelogin.OneloginSDK)
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetAppByID(appID, nil) })
+	if err != nil {
+		return nil, fmt.Errorf("reading app %d: %w", appID, err)
+	}
+	appMap, ok := result.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("app %d not found", appID)
+	}
+	if !appHasRole(appMap, roleID) {
+		return nil, fmt.Errorf("role %d is not attached to app %d", roleID, appID)
+	}
+
+	d.SetId(appRoleAttachmentID(appID, roleID))
+	d.Set("app_id", appID)
+	d.Set("role_id", roleID)
+	return []*schema.ResourceData{d}, nil
+}
+
+// upgradeAppRoleAttachmentIDV0 rewrites an ID from the run-together form to
+// "app_id:role_id". The old ID cannot be split, but it never needed to be: the
+// role and app it stood for are in state beside it.
+func upgradeAppRoleAttachmentIDV0(ctx context.Context, state map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if state == nil {
+		return state, nil
+	}
+	appID, appOK := stateInt(state["app_id"])
+	roleID, roleOK := stateInt(state["role_id"])
+	if !appOK || !roleOK {
+		return nil, fmt.Errorf("cannot upgrade app role attachment %v: app_id or role_id missing from state", state["id"])
+	}
+	state["id"] = appRoleAttachmentID(appID, roleID)
+	return state, nil
+}
+
+// stateInt reads a number from raw state, which a state upgrader gets decoded
+// from JSON.
+func stateInt(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case float64:
+		return int(n), true
+	case json.Number:
+		i, err := n.Int64()
+		return int(i), err == nil
+	case string:
+		i, err := strconv.Atoi(n)
+		return i, err == nil
+	}
+	return 0, false
+}
+
+// appHasRole reports whether an app as the API returns it lists roleID.
+func appHasRole(appMap map[string]interface{}, roleID int) bool {
+	roleIDs, _ := appMap["role_ids"].([]interface{})
+	for _, r := range roleIDs {
+		if rID, ok := r.(float64); ok && int(rID) == roleID {
+			return true
+		}
+	}
+	return false
+}
+
 func appRoleAttachmentCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*onelogin.OneloginSDK)
 
@@ -39,10 +157,13 @@ func appRoleAttachmentCreate(ctx context.Context, d *schema.ResourceData, m inte
 	appID := d.Get("app_id").(int)
 
 	if appErr := attachRoleToApp(ctx, client, appID, roleID); appErr != nil {
+		if utils.IsTimeout(ctx, appErr) {
+			return utils.TimeoutDiagnostics(utils.ErrorCategoryCreate, "app role attachment", "", appErr)
+		}
 		return diag.Errorf("Unable to attach role to app: %s", appErr)
 	}
 
-	d.SetId(fmt.Sprintf("%d%d", roleID, appID))
+	d.SetId(appRoleAttachmentID(appID, roleID))
 	return appRoleAttachmentRead(ctx, d, m)
 }
 
@@ -51,8 +172,12 @@ func appRoleAttachmentRead(ctx context.Context, d *schema.ResourceData, m interf
 	appID := d.Get("app_id").(int)
 	roleID := d.Get("role_id").(int)
 
-	result, err := client.GetAppByID(appID, nil)
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetAppByID(appID, nil) })
 	if err != nil {
+		// Not knowing is not the same as not there.
+		if utils.IsTimeout(ctx, err) {
+			return utils.TimeoutDiagnostics(utils.ErrorCategoryRead, "app role attachment", d.Id(), err)
+		}
 		d.SetId("")
 		return diag.Errorf("App does not exist: %s", err)
 	}
@@ -89,14 +214,20 @@ func appRoleAttachmentUpdate(ctx context.Context, d *schema.ResourceData, m inte
 
 	var err error
 	if err = removeRoleFromApp(ctx, client, oldApp.(int), oldRole.(int)); err != nil {
+		if utils.IsTimeout(ctx, err) {
+			return utils.TimeoutDiagnostics(utils.ErrorCategoryUpdate, "app role attachment", d.Id(), err)
+		}
 		return diag.Errorf("Unable to remove role from app: %s", err)
 	}
 
 	if err = attachRoleToApp(ctx, client, newApp.(int), newRole.(int)); err != nil {
+		if utils.IsTimeout(ctx, err) {
+			return utils.TimeoutDiagnostics(utils.ErrorCategoryUpdate, "app role attachment", d.Id(), err)
+		}
 		return diag.Errorf("Unable to attach role to app: %s", err)
 	}
 
-	d.SetId(fmt.Sprintf("%d%d", newRole, newApp))
+	d.SetId(appRoleAttachmentID(newApp.(int), newRole.(int)))
 	return appRoleAttachmentRead(ctx, d, m)
 }
 
@@ -108,6 +239,9 @@ func appRoleAttachmentDelete(ctx context.Context, d *schema.ResourceData, m inte
 
 	var err error
 	if err = removeRoleFromApp(ctx, client, appID, roleID); err != nil {
+		if utils.IsTimeout(ctx, err) {
+			return utils.TimeoutDiagnostics(utils.ErrorCategoryDelete, "app role attachment", d.Id(), err)
+		}
 		return diag.Errorf("Unable to remove role from app: %s", err)
 	}
 	d.SetId("")
@@ -115,7 +249,7 @@ func appRoleAttachmentDelete(ctx context.Context, d *schema.ResourceData, m inte
 }
 
 func removeRoleFromApp(ctx context.Context, client *onelogin.OneloginSDK, appID int, roleID int) error {
-	result, err := client.GetAppByID(appID, nil)
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetAppByID(appID, nil) })
 	if err != nil {
 		return err
 	}
@@ -144,7 +278,7 @@ func removeRoleFromApp(ctx context.Context, client *onelogin.OneloginSDK, appID
 		RoleIDs: &newRoleIDs,
 	}
 
-	_, err = client.UpdateApp(appID, appToUpdate)
+	_, err = utils.CallWithContext(ctx, func() (interface{}, error) { return client.UpdateApp(appID, appToUpdate) })
 	if err != nil {
 		return err
 	}
@@ -157,7 +291,7 @@ func removeRoleFromApp(ctx context.Context, client *onelogin.OneloginSDK, appID
 }
 
 func attachRoleToApp(ctx context.Context, client *onelogin.OneloginSDK, appID int, roleID int) error {
-	result, err := client.GetAppByID(appID, nil)
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetAppByID(appID, nil) })
 	if err != nil {
 		return err
 	}
@@ -185,7 +319,7 @@ func attachRoleToApp(ctx context.Context, client *onelogin.OneloginSDK, appID in
 		RoleIDs: &roleIDs,
 	}
 
-	_, err = client.UpdateApp(appID, appToUpdate)
+	_, err = utils.CallWithContext(ctx, func() (interface{}, error) { return client.UpdateApp(appID, appToUpdate) })
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,141 @@
+package onelogin
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/onelogin/terraform-provider-onelogin/fakeapi"
+)
+
+// TestAppRoleAttachmentIDsDoNotCollide covers the pair the run-together ID
+// could not tell apart.
+func TestAppRoleAttachmentIDsDoNotCollide(t *testing.T) {
+	a, b := appRoleAttachmentID(23, 1), appRoleAttachmentID(3, 12)
+	if a == b {
+		t.Fatalf("expected distinct IDs, both were %q", a)
+	}
+
+	for _, pair := range [][2]int{{23, 1}, {3, 12}} {
+		appID, roleID, err := parseAppRoleAttachmentID(appRoleAttachmentID(pair[0], pair[1]))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if appID != pair[0] || roleID != pair[1] {
+			t.Fatalf("expected app %d role %d, got app %d role %d", pair[0], pair[1], appID, roleID)
+		}
+	}
+}
+
+func TestParseAppRoleAttachmentIDErrors(t *testing.T) {
+	for _, id := range []string{"", "123", "123:", ":456", "abc:456", "123:def", "1231"} {
+		t.Run(id, func(t *testing.T) {
+			if _, _, err := parseAppRoleAttachmentID(id); err == nil {
+				t.Fatalf("expected %q to be rejected", id)
+			}
+		})
+	}
+}
+
+func TestUpgradeAppRoleAttachmentIDV0(t *testing.T) {
+	t.Run("rewrites the ID from the attributes beside it", func(t *testing.T) {
+		// Role 12 on app 3 was stored as "123", as was role 1 on app 23.
+		state := map[string]interface{}{"id": "123", "app_id": float64(3), "role_id": float64(12)}
+		out, err := upgradeAppRoleAttachmentIDV0(context.Background(), state, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out["id"] != "3:12" {
+			t.Fatalf("expected id 3:12, got %v", out["id"])
+		}
+		if out["app_id"] != float64(3) || out["role_id"] != float64(12) {
+			t.Fatalf("expected the attributes to be left alone, got %v", out)
+		}
+	})
+
+	t.Run("json numbers", func(t *testing.T) {
+		state := map[string]interface{}{"id": "123", "app_id": json.Number("23"), "role_id": json.Number("1")}
+		out, err := upgradeAppRoleAttachmentIDV0(context.Background(), state, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out["id"] != "23:1" {
+			t.Fatalf("expected id 23:1, got %v", out["id"])
+		}
+	})
+
+	t.Run("missing attributes", func(t *testing.T) {
+		if _, err := upgradeAppRoleAttachmentIDV0(context.Background(), map[string]interface{}{"id": "123"}, nil); err == nil {
+			t.Fatal("expected an error for state without app_id and role_id")
+		}
+	})
+
+	t.Run("wired up", func(t *testing.T) {
+		r := AppRoleAttachment()
+		if r.SchemaVersion != 1 || len(r.StateUpgraders) != 1 || r.StateUpgraders[0].Version != 0 {
+			t.Fatalf("expected a version 0 upgrader to schema version 1, got %d and %v", r.SchemaVersion, r.StateUpgraders)
+		}
+	})
+}
+
+func TestAppRoleAttachmentImport(t *testing.T) {
+	srv := fakeapi.NewServer()
+	defer srv.Close()
+	meta := fakeAPIMeta(t, srv)
+
+	roleID, _ := strconv.Atoi(srv.Seed(fakeapi.Roles, map[string]interface{}{"name": "acctest-attached"}))
+	otherRoleID, _ := strconv.Atoi(srv.Seed(fakeapi.Roles, map[string]interface{}{"name": "acctest-unattached"}))
+	appID, _ := strconv.Atoi(srv.Seed(fakeapi.Apps, map[string]interface{}{
+		"name":         "acctest-app",
+		"connector_id": float64(108419),
+		"role_ids":     []interface{}{float64(roleID)},
+	}))
+
+	importID := func(id string) ([]*schema.ResourceData, error) {
+		r := AppRoleAttachment()
+		d := r.Data(nil)
+		d.SetId(id)
+		return r.Importer.StateContext(context.Background(), d, meta)
+	}
+
+	t.Run("an attachment that exists", func(t *testing.T) {
+		out, err := importID(appRoleAttachmentID(appID, roleID))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(out) != 1 {
+			t.Fatalf("expected one resource, got %d", len(out))
+		}
+		d := out[0]
+		if d.Get("app_id").(int) != appID || d.Get("role_id").(int) != roleID {
+			t.Fatalf("expected app %d role %d, got app %v role %v", appID, roleID, d.Get("app_id"), d.Get("role_id"))
+		}
+		if d.Id() != appRoleAttachmentID(appID, roleID) {
+			t.Fatalf("unexpected id %q", d.Id())
+		}
+	})
+
+	t.Run("a role not attached", func(t *testing.T) {
+		_, err := importID(appRoleAttachmentID(appID, otherRoleID))
+		if err == nil || !strings.Contains(err.Error(), "not attached") {
+			t.Fatalf("expected the import to be refused, got %v", err)
+		}
+	})
+
+	t.Run("an app that does not exist", func(t *testing.T) {
+		if _, err := importID(appRoleAttachmentID(appID+1000, roleID)); err == nil {
+			t.Fatal("expected the import to be refused")
+		}
+	})
+
+	t.Run("a malformed ID", func(t *testing.T) {
+		_, err := importID("123")
+		if err == nil || !strings.Contains(err.Error(), "app_id:role_id") {
+			t.Fatalf("expected the expected format to be named, got %v", err)
+		}
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -25,6 +27,10 @@ func AppRules() *schema.Resource {
 		ReadContext:   appRuleRead,
 		UpdateContext: appRuleUpdate,
 		DeleteContext: appRuleDelete,
+		CustomizeDiff: appRuleCustomizeDiff,
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
 			// State is added here, which splits app_id and rules_id
 			// and sets them appropriately before reading
@@ -44,6 +50,52 @@ func AppRules() *schema.Resource {
 	}
 }
 
+// appRuleCustomizeDiff checks a rule's conditions and actions against its
+// app's rule catalogue, so that a mistyped source, operator or action fails the
+// plan, listing what it could have been, rather than the apply with a bare 422.
+// Whatever is not known yet is left for the API to check, and that includes
+// the whole rule when its app is created in the same run.
+func appRuleCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	client, ok := m.(*onelogin.OneloginSDK)
+	config := d.GetRawConfig()
+	if !ok || config.IsNull() || !config.IsKnown() {
+		return nil
+	}
+	appIDStr, ok := knownString(config.GetAttr("app_id"))
+	if !ok {
+		return nil
+	}
+	appID, err := strconv.Atoi(appIDStr)
+	if err != nil {
+		return nil
+	}
+
+	var conditions []configuredCondition
+	for _, block := range configuredBlocks(config.GetAttr("conditions")) {
+		var c configuredCondition
+		c.source, _ = knownString(block.GetAttr("source"))
+		c.operator, _ = knownString(block.GetAttr("operator"))
+		c.value, _ = knownString(block.GetAttr("value"))
+		conditions = append(conditions, c)
+	}
+
+	var actions []configuredAction
+	for _, block := range configuredBlocks(config.GetAttr("actions")) {
+		var a configuredAction
+		a.action, _ = knownString(block.GetAttr("action"))
+		a.action = strings.TrimSuffix(a.action, appruleactionsschema.NO_EXPRESSION_SUFFIX)
+		a.values, _ = knownStrings(block.GetAttr("value"))
+		// With an expression or a macro, the values are what the expression
+		// reads from, not values of the action's own.
+		expression, _ := knownString(block.GetAttr("expression"))
+		macro, _ := knownString(block.GetAttr("macro"))
+		a.checkValues = expression == "" && macro == "" && block.GetAttr("expression").IsKnown() && block.GetAttr("macro").IsKnown()
+		actions = append(actions, a)
+	}
+
+	return validateAgainstCatalogue(ctx, fmt.Sprintf("The rules of app %d", appID), appRuleCatalogue(client, appID), conditions, actions)
+}
+
 // appRuleCreate takes a pointer to the ResourceData Struct and a HTTP client and
 // makes the POST request to OneLogin to create an App Rule
 func appRuleCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -77,7 +129,7 @@ func appRuleCreate(ctx context.Context, d *schema.ResourceData, m interface{}) d
 		"name":   d.Get("name"),
 	})
 
-	result, err := client.CreateAppRule(appID, appRule)
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.CreateAppRule(appID, appRule) })
 	if err != nil {
 		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryCreate, resourceTypeAppRule, appIDStr)
 	}
@@ -146,7 +198,7 @@ func appRuleRead(ctx context.Context, d *schema.ResourceData, m interface{}) dia
 		"rule_id": ruleID,
 	})
 
-	result, err := client.GetAppRuleByID(appID, ruleID, nil)
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetAppRuleByID(appID, ruleID, nil) })
 	if err != nil {
 		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryRead, resourceTypeAppRule, ruleIDStr)
 	}
@@ -280,7 +332,7 @@ func appRuleUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) d
 		"rule_id": ruleID,
 	})
 
-	_, err = client.UpdateAppRule(appID, ruleID, appRule, nil)
+	_, err = utils.CallWithContext(ctx, func() (interface{}, error) { return client.UpdateAppRule(appID, ruleID, appRule, nil) })
 	if err != nil {
 		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryUpdate, resourceTypeAppRule, ruleIDStr)
 	}
@@ -331,7 +383,7 @@ func appRuleDelete(ctx context.Context, d *schema.ResourceData, m interface{}) d
 		"rule_id": ruleID,
 	})
 
-	_, err = client.DeleteAppRule(appID, ruleID, nil)
+	_, err = utils.CallWithContext(ctx, func() (interface{}, error) { return client.DeleteAppRule(appID, ruleID, nil) })
 	if err != nil {
 		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryDelete, resourceTypeAppRule, ruleIDStr)
 	}
@@ -0,0 +1,174 @@
+package onelogin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
+	"github.com/onelogin/terraform-provider-onelogin/utils"
+)
+
+const resourceTypeAppRulesOrder = "app_rules_order"
+
+// AppRulesOrder returns the onelogin_app_rules_order resource, which puts an
+// app's rules in the order given.
+//
+// Rules run first to last, as mappings do, and onelogin_app_rules sets the
+// position of one rule at a time: putting a new rule at the top renumbers
+// every other rule, each in an apply of its own. This sets the whole order in
+// one call to the app's rule sort endpoint, and reads it back whole so that a
+// rule moved in the admin portal shows up as a change.
+func AppRulesOrder() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: appRulesOrderApply,
+		ReadContext:   appRulesOrderRead,
+		UpdateContext: appRulesOrderApply,
+		DeleteContext: appRulesOrderDelete,
+		Importer: &schema.ResourceImporter{
+			// The ID is the app's ID, which is all Read needs.
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				if _, err := strconv.Atoi(d.Id()); err != nil {
+					return nil, fmt.Errorf("unexpected format of ID (%s), expected app_id", d.Id())
+				}
+				d.Set("app_id", d.Id())
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(20 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"app_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the app whose rules are ordered",
+			},
+			"rule_ids": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "IDs of every rule of the app, in the order OneLogin should run them",
+			},
+		},
+	}
+}
+
+func appRulesOrderApply(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*onelogin.OneloginSDK)
+	appIDStr := d.Get("app_id").(string)
+	appID, err := strconv.Atoi(appIDStr)
+	if err != nil {
+		return diag.Errorf("invalid app_id %q: %v", appIDStr, err)
+	}
+	before, after := d.GetChange("rule_ids")
+
+	current, err := appRuleIDs(ctx, client, appID)
+	if err != nil {
+		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryRead, resourceTypeAppRule, appIDStr)
+	}
+	order, err := sortOrder(fmt.Sprintf("rules of app %d", appID), current, intList(before.([]interface{})), intList(after.([]interface{})))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Info(ctx, "[UPDATE] Sorting app rules", map[string]interface{}{
+		"app_id":   appID,
+		"rule_ids": order,
+	})
+
+	_, err = utils.CallWithContext(ctx, func() (interface{}, error) { return client.SortAppRules(appID, order) })
+	if err != nil {
+		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryUpdate, resourceTypeAppRulesOrder, appIDStr)
+	}
+
+	d.SetId(appIDStr)
+	return appRulesOrderRead(ctx, d, m)
+}
+
+// appRulesOrderRead records the app's rules in the order OneLogin has them
+// now, which is how a reorder made outside Terraform is noticed.
+func appRulesOrderRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*onelogin.OneloginSDK)
+	appID, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diag.Errorf("invalid app_id %q: %v", d.Id(), err)
+	}
+
+	tflog.Info(ctx, "[READ] Reading app rules order", map[string]interface{}{
+		"app_id": appID,
+	})
+
+	ids, err := appRuleIDs(ctx, client, appID)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			tflog.Info(ctx, "[NOT FOUND] App not found, removing rules order from state", map[string]interface{}{
+				"app_id": appID,
+			})
+			d.SetId("")
+			return nil
+		}
+		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryRead, resourceTypeAppRulesOrder, d.Id())
+	}
+
+	d.Set("app_id", d.Id())
+	if err := d.Set("rule_ids", ids); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+// appRulesOrderDelete only forgets the order. The rules stay where they are.
+func appRulesOrderDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	tflog.Info(ctx, "[DELETE] Removing app rules order from state; the rules keep their order", map[string]interface{}{
+		"app_id": d.Id(),
+	})
+	d.SetId("")
+	return nil
+}
+
+// appRuleIDs lists the IDs of app appID's rules by position. Disabled rules
+// keep their place, so they are listed too.
+func appRuleIDs(ctx context.Context, client *onelogin.OneloginSDK, appID int) ([]int, error) {
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetAppRules(appID, nil) })
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return []int{}, nil
+	}
+	items, ok := result.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected list response: want a JSON array, got %T", result)
+	}
+
+	type placed struct{ id, position int }
+	rules := make([]placed, 0, len(items))
+	for _, item := range items {
+		rule, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, ok := rule["id"].(float64)
+		if !ok {
+			continue
+		}
+		position, _ := rule["position"].(float64)
+		rules = append(rules, placed{int(id), int(position)})
+	}
+	sort.SliceStable(rules, func(i, j int) bool { return rules[i].position < rules[j].position })
+
+	ids := make([]int, len(rules))
+	for i, rule := range rules {
+		ids[i] = rule.id
+	}
+	return ids, nil
+}
@@ -0,0 +1,191 @@
+package onelogin
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/onelogin/terraform-provider-onelogin/fakeapi"
+)
+
+func TestAccAppRulesOrder(t *testing.T) {
+	name := newFixtureSuffix() + "-rules-order"
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { TestAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAppRulesOrderConfig(name, "first", "second"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("onelogin_app_rules_order.saml", "rule_ids.0", "onelogin_app_rules.first", "id"),
+					resource.TestCheckResourceAttrPair("onelogin_app_rules_order.saml", "rule_ids.1", "onelogin_app_rules.second", "id"),
+				),
+			},
+			{
+				Config: testAccAppRulesOrderConfig(name, "second", "first"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("onelogin_app_rules_order.saml", "rule_ids.0", "onelogin_app_rules.second", "id"),
+					resource.TestCheckResourceAttrPair("onelogin_app_rules_order.saml", "rule_ids.1", "onelogin_app_rules.first", "id"),
+				),
+			},
+			{
+				ResourceName:      "onelogin_app_rules_order.saml",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// testAccAppRulesOrderConfig orders an app's two rules, named by the resources
+// given in the order wanted. The rules leave position out, as they have to for
+// the order resource to own it.
+func testAccAppRulesOrderConfig(name string, order ...string) string {
+	refs := make([]string, len(order))
+	for i, o := range order {
+		refs[i] = fmt.Sprintf("onelogin_app_rules.%s.id", o)
+	}
+	return fmt.Sprintf(`
+resource "onelogin_saml_apps" "saml" {
+  name         = %[1]q
+  connector_id = 50534
+}
+
+resource "onelogin_app_rules" "first" {
+  app_id  = onelogin_saml_apps.saml.id
+  name    = "first"
+  match   = "all"
+  enabled = true
+
+  conditions {
+    source   = "last_login"
+    operator = ">"
+    value    = "90"
+  }
+
+  actions {
+    action     = "set_amazonusername"
+    expression = ".*"
+    value      = ["member_of"]
+  }
+}
+
+resource "onelogin_app_rules" "second" {
+  app_id  = onelogin_saml_apps.saml.id
+  name    = "second"
+  match   = "all"
+  enabled = true
+
+  conditions {
+    source   = "last_login"
+    operator = "<"
+    value    = "30"
+  }
+
+  actions {
+    action     = "set_amazonusername"
+    expression = ".*"
+    value      = ["member_of"]
+  }
+}
+
+resource "onelogin_app_rules_order" "saml" {
+  app_id   = onelogin_saml_apps.saml.id
+  rule_ids = [%[2]s]
+}
+`, name, strings.Join(refs, ", "))
+}
+
+func TestAppRulesOrder(t *testing.T) {
+	srv := fakeapi.NewServer()
+	defer srv.Close()
+	meta := fakeAPIMeta(t, srv)
+	ctx := context.Background()
+
+	app := srv.Seed(fakeapi.Apps, map[string]interface{}{"name": "Ordered", "connector_id": float64(1)})
+	other := srv.Seed(fakeapi.Apps, map[string]interface{}{"name": "Other", "connector_id": float64(1)})
+	seed := func(appID, name string, enabled bool, position int) int {
+		id, _ := strconv.Atoi(srv.Seed(fakeapi.AppRules, map[string]interface{}{
+			"app_id": appID, "name": name, "match": "all", "enabled": enabled, "position": float64(position),
+		}))
+		return id
+	}
+	a := seed(app, "a", true, 1)
+	b := seed(app, "b", false, 2)
+	c := seed(app, "c", true, 3)
+	theirs := seed(other, "theirs", true, 1)
+
+	r := AppRulesOrder()
+	order := func(appID string, ids ...int) *schema.ResourceData {
+		d := r.Data(nil)
+		d.Set("app_id", appID)
+		d.Set("rule_ids", ids)
+		return d
+	}
+	got := func(d *schema.ResourceData) string {
+		return fmt.Sprint(d.Get("rule_ids"))
+	}
+
+	mine := order(app, c, b, a)
+	if diags := r.CreateContext(ctx, mine, meta); diags.HasError() {
+		t.Fatalf("unexpected error sorting: %v", diags)
+	}
+	if mine.Id() != app {
+		t.Fatalf("expected the app's ID, %s, got %q", app, mine.Id())
+	}
+	if want := fmt.Sprint([]int{c, b, a}); got(mine) != want {
+		t.Fatalf("expected rule_ids %s, got %s", want, got(mine))
+	}
+
+	// Someone moves a rule in the admin portal; the next refresh records it.
+	if diags := r.CreateContext(ctx, order(app, a, c, b), meta); diags.HasError() {
+		t.Fatalf("unexpected error sorting: %v", diags)
+	}
+	if diags := r.ReadContext(ctx, mine, meta); diags.HasError() {
+		t.Fatalf("unexpected error reading: %v", diags)
+	}
+	if want := fmt.Sprint([]int{a, c, b}); got(mine) != want {
+		t.Fatalf("expected the reorder to be read back as %s, got %s", want, got(mine))
+	}
+
+	t.Run("another app's rule", func(t *testing.T) {
+		diags := r.CreateContext(ctx, order(app, a, c, b, theirs), meta)
+		if !diags.HasError() || !strings.Contains(diags[0].Summary, fmt.Sprintf("%d is not one of the rules of app %s", theirs, app)) {
+			t.Fatalf("expected the other app's rule to be refused, got %v", diags)
+		}
+	})
+
+	t.Run("import", func(t *testing.T) {
+		d := r.Data(nil)
+		d.SetId(app)
+		out, err := r.Importer.StateContext(ctx, d, meta)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diags := r.ReadContext(ctx, out[0], meta); diags.HasError() {
+			t.Fatalf("unexpected error reading: %v", diags)
+		}
+		if out[0].Get("app_id").(string) != app || got(out[0]) != fmt.Sprint([]int{a, c, b}) {
+			t.Fatalf("expected app %s's rules, got app %v rules %s", app, out[0].Get("app_id"), got(out[0]))
+		}
+		if _, err := r.Importer.StateContext(ctx, r.Data(nil), meta); err == nil {
+			t.Fatal("expected an ID that is not an app's to be refused")
+		}
+	})
+
+	t.Run("app gone", func(t *testing.T) {
+		d := order("999999", a)
+		d.SetId("999999")
+		if diags := r.ReadContext(ctx, d, meta); diags.HasError() {
+			t.Fatalf("unexpected error reading: %v", diags)
+		}
+		if d.Id() != "" {
+			t.Fatalf("expected the order of an app that is gone to be removed from state, got %q", d.Id())
+		}
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -25,7 +26,10 @@ func Apps() *schema.Resource {
 		ReadContext:   appRead,
 		UpdateContext: appUpdate,
 		DeleteContext: appDelete,
-		Importer:      &schema.ResourceImporter{},
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(20 * time.Minute),
+		},
+		Importer:      importer("app", appLookup(), objectID, "name"),
 		Schema:        appschema.Schema(),
 		SchemaVersion: 1,
 		StateUpgraders: []schema.StateUpgrader{
@@ -58,8 +62,11 @@ func appCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.
 	})
 
 	client := m.(*onelogin.OneloginSDK)
-	result, err := client.CreateApp(basicApp)
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.CreateApp(basicApp) })
 	if err != nil {
+		if utils.IsTimeout(ctx, err) {
+			return utils.TimeoutDiagnostics(utils.ErrorCategoryCreate, "App", "", err)
+		}
 		tflog.Error(ctx, "[ERROR] Error creating app", map[string]interface{}{"error": err})
 		return diag.FromErr(err)
 	}
@@ -87,8 +94,11 @@ func appRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Di
 	client := m.(*onelogin.OneloginSDK)
 	aid, _ := strconv.Atoi(d.Id())
 
-	result, err := client.GetAppByID(aid, nil)
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetAppByID(aid, nil) })
 	if err != nil {
+		if utils.IsTimeout(ctx, err) {
+			return utils.TimeoutDiagnostics(utils.ErrorCategoryRead, "App", d.Id(), err)
+		}
 		tflog.Error(ctx, "[ERROR] Error reading app", map[string]interface{}{"id": aid, "error": err})
 		return diag.FromErr(err)
 	}
@@ -193,8 +203,11 @@ func appUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.
 	})
 
 	client := m.(*onelogin.OneloginSDK)
-	_, err := client.UpdateApp(aid, basicApp)
+	_, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.UpdateApp(aid, basicApp) })
 	if err != nil {
+		if utils.IsTimeout(ctx, err) {
+			return utils.TimeoutDiagnostics(utils.ErrorCategoryUpdate, "App", d.Id(), err)
+		}
 		tflog.Error(ctx, "[ERROR] Error updating app", map[string]interface{}{"id": aid, "error": err})
 		return diag.FromErr(err)
 	}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"testing"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 )
 
@@ -39,7 +38,7 @@ const upgradeFromVersion = "0.16.0"
 //
 // or clear the stale copies out.
 func TestAccSAMLApp_upgradeParameterValues(t *testing.T) {
-	suffix := acctest.RandStringFromCharSet(8, acctest.CharSetAlphaNum)
+	suffix := newFixtureSuffix()
 
 	// One value, so the string that v0.16.0 stores and the single-element list
 	// this version stores are the same value expressed two ways. That is the
@@ -0,0 +1,145 @@
+package onelogin
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
+	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin/models"
+	authserverclaimschema "github.com/onelogin/terraform-provider-onelogin/ol_schema/auth_server/claim"
+	"github.com/onelogin/terraform-provider-onelogin/utils"
+)
+
+// AuthServerClaim returns the onelogin_auth_server_claim resource, one custom
+// claim an authorization server puts in its access tokens.
+func AuthServerClaim() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: authServerClaimCreate,
+		ReadContext:   authServerClaimRead,
+		UpdateContext: authServerClaimUpdate,
+		DeleteContext: authServerClaimDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(20 * time.Minute),
+		},
+		Importer: authServerChildImporter("claim", "claim_id", authServerClaimRead),
+		Schema:   authserverclaimschema.Schema(),
+	}
+}
+
+func inflateAuthServerClaim(d *schema.ResourceData) models.AccessTokenClaim {
+	return authserverclaimschema.Inflate(map[string]interface{}{
+		"name":                    d.Get("name"),
+		"user_attribute_mappings": d.Get("user_attribute_mappings"),
+		"user_attribute_macros":   d.Get("user_attribute_macros"),
+	})
+}
+
+func authServerClaimCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*onelogin.OneloginSDK)
+	authID := d.Get("auth_server_id").(int)
+	claim := inflateAuthServerClaim(d)
+
+	tflog.Info(ctx, "[CREATE] Creating auth server claim", map[string]interface{}{
+		"auth_server_id": authID,
+		"name":           d.Get("name"),
+	})
+
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.CreateAuthServerClaim(authID, &claim) })
+	if err != nil {
+		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryCreate, "Auth Server Claim", "")
+	}
+	claimMap, ok := result.(map[string]interface{})
+	if !ok || claimMap["id"] == nil {
+		return diag.Errorf("failed to parse auth server claim creation response or claim ID not found in response")
+	}
+
+	d.SetId(authServerChildID(authID, int(claimMap["id"].(float64))))
+	tflog.Info(ctx, "[CREATED] Created auth server claim", map[string]interface{}{
+		"id": d.Id(),
+	})
+	return authServerClaimRead(ctx, d, m)
+}
+
+func authServerClaimRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*onelogin.OneloginSDK)
+	authID, claimID, err := parseAuthServerChildID(d.Id(), "claim_id")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Info(ctx, "[READ] Reading auth server claim", map[string]interface{}{
+		"id": d.Id(),
+	})
+
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetAuthServerClaims(authID, nil) })
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			tflog.Info(ctx, "[NOT FOUND] Auth server not found, removing claim from state", map[string]interface{}{
+				"id": d.Id(),
+			})
+			d.SetId("")
+			return nil
+		}
+		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryRead, "Auth Server Claim", d.Id())
+	}
+
+	claim, ok := findAuthServerChild(result, "id", claimID)
+	if !ok {
+		tflog.Info(ctx, "[NOT FOUND] Auth server claim not found, removing from state", map[string]interface{}{
+			"id": d.Id(),
+		})
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("auth_server_id", authID)
+	d.Set("claim_id", claimID)
+	for k, v := range authserverclaimschema.Flatten(claim) {
+		if err := d.Set(k, v); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return nil
+}
+
+func authServerClaimUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*onelogin.OneloginSDK)
+	authID, claimID, err := parseAuthServerChildID(d.Id(), "claim_id")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	claim := inflateAuthServerClaim(d)
+
+	tflog.Info(ctx, "[UPDATE] Updating auth server claim", map[string]interface{}{
+		"id": d.Id(),
+	})
+
+	_, err = utils.CallWithContext(ctx, func() (interface{}, error) { return client.UpdateAuthServerClaim(authID, claimID, &claim) })
+	if err != nil {
+		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryUpdate, "Auth Server Claim", d.Id())
+	}
+	return authServerClaimRead(ctx, d, m)
+}
+
+func authServerClaimDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*onelogin.OneloginSDK)
+	authID, claimID, err := parseAuthServerChildID(d.Id(), "claim_id")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	_, err = utils.CallWithContext(ctx, func() (interface{}, error) { return client.DeleteAuthServerClaim(authID, claimID) })
+	if err != nil && !utils.IsNotFoundError(err) {
+		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryDelete, "Auth Server Claim", d.Id())
+	}
+
+	tflog.Info(ctx, "[DELETED] Deleted auth server claim", map[string]interface{}{
+		"id": d.Id(),
+	})
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,142 @@
+package onelogin
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
+	authserverclientappschema "github.com/onelogin/terraform-provider-onelogin/ol_schema/auth_server/client_app"
+	"github.com/onelogin/terraform-provider-onelogin/utils"
+)
+
+// AuthServerClientApp returns the onelogin_auth_server_client_app resource,
+// which lets one app ask an authorization server for tokens with the scopes
+// it is given.
+func AuthServerClientApp() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: authServerClientAppCreate,
+		ReadContext:   authServerClientAppRead,
+		UpdateContext: authServerClientAppUpdate,
+		DeleteContext: authServerClientAppDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(20 * time.Minute),
+		},
+		Importer: authServerChildImporter("client app", "app_id", authServerClientAppRead),
+		Schema:   authserverclientappschema.Schema(),
+	}
+}
+
+func authServerClientAppCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*onelogin.OneloginSDK)
+	authID := d.Get("auth_server_id").(int)
+	appID := d.Get("app_id").(int)
+	clientApp := authserverclientappschema.Inflate(map[string]interface{}{
+		"app_id":    appID,
+		"scope_ids": d.Get("scope_ids"),
+	})
+
+	tflog.Info(ctx, "[CREATE] Adding auth server client app", map[string]interface{}{
+		"auth_server_id": authID,
+		"app_id":         appID,
+	})
+
+	_, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.CreateAuthServerClientApp(authID, &clientApp) })
+	if err != nil {
+		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryCreate, "Auth Server Client App", authServerChildID(authID, appID))
+	}
+
+	// A client app is known by the app it grants; the API gives it no ID of
+	// its own.
+	d.SetId(authServerChildID(authID, appID))
+	tflog.Info(ctx, "[CREATED] Added auth server client app", map[string]interface{}{
+		"id": d.Id(),
+	})
+	return authServerClientAppRead(ctx, d, m)
+}
+
+func authServerClientAppRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*onelogin.OneloginSDK)
+	authID, appID, err := parseAuthServerChildID(d.Id(), "app_id")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Info(ctx, "[READ] Reading auth server client app", map[string]interface{}{
+		"id": d.Id(),
+	})
+
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetAuthServerClientApps(authID, nil) })
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			tflog.Info(ctx, "[NOT FOUND] Auth server not found, removing client app from state", map[string]interface{}{
+				"id": d.Id(),
+			})
+			d.SetId("")
+			return nil
+		}
+		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryRead, "Auth Server Client App", d.Id())
+	}
+
+	clientApp, ok := findAuthServerChild(result, "app_id", appID)
+	if !ok {
+		tflog.Info(ctx, "[NOT FOUND] Auth server client app not found, removing from state", map[string]interface{}{
+			"id": d.Id(),
+		})
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("auth_server_id", authID)
+	d.Set("app_id", appID)
+	d.Set("name", clientApp["name"])
+	if err := d.Set("scope_ids", authserverclientappschema.FlattenScopeIDs(clientApp)); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+// authServerClientAppUpdate changes the app's scopes, which is all that can
+// change without replacing it.
+func authServerClientAppUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*onelogin.OneloginSDK)
+	authID, appID, err := parseAuthServerChildID(d.Id(), "app_id")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	clientApp := authserverclientappschema.Inflate(map[string]interface{}{
+		"scope_ids": d.Get("scope_ids"),
+	})
+
+	tflog.Info(ctx, "[UPDATE] Updating auth server client app scopes", map[string]interface{}{
+		"id": d.Id(),
+	})
+
+	_, err = utils.CallWithContext(ctx, func() (interface{}, error) { return client.UpdateAuthServerClientApp(authID, appID, &clientApp) })
+	if err != nil {
+		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryUpdate, "Auth Server Client App", d.Id())
+	}
+	return authServerClientAppRead(ctx, d, m)
+}
+
+func authServerClientAppDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*onelogin.OneloginSDK)
+	authID, appID, err := parseAuthServerChildID(d.Id(), "app_id")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	_, err = utils.CallWithContext(ctx, func() (interface{}, error) { return client.DeleteAuthServerClientApp(authID, appID) })
+	if err != nil && !utils.IsNotFoundError(err) {
+		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryDelete, "Auth Server Client App", d.Id())
+	}
+
+	tflog.Info(ctx, "[DELETED] Removed auth server client app", map[string]interface{}{
+		"id": d.Id(),
+	})
+	d.SetId("")
+	return nil
+}
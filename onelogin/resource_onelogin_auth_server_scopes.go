@@ -0,0 +1,142 @@
+package onelogin
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
+	authserverscopeschema "github.com/onelogin/terraform-provider-onelogin/ol_schema/auth_server/scope"
+	"github.com/onelogin/terraform-provider-onelogin/utils"
+)
+
+// AuthServerScope returns the onelogin_auth_server_scope resource, one scope
+// of an authorization server.
+func AuthServerScope() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: authServerScopeCreate,
+		ReadContext:   authServerScopeRead,
+		UpdateContext: authServerScopeUpdate,
+		DeleteContext: authServerScopeDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(20 * time.Minute),
+		},
+		Importer: authServerChildImporter("scope", "scope_id", authServerScopeRead),
+		Schema:   authserverscopeschema.Schema(),
+	}
+}
+
+func authServerScopeCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*onelogin.OneloginSDK)
+	authID := d.Get("auth_server_id").(int)
+	scope := authserverscopeschema.Inflate(map[string]interface{}{
+		"value":       d.Get("value"),
+		"description": d.Get("description"),
+	})
+
+	tflog.Info(ctx, "[CREATE] Creating auth server scope", map[string]interface{}{
+		"auth_server_id": authID,
+		"value":          d.Get("value"),
+	})
+
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.CreateAuthServerScope(authID, &scope) })
+	if err != nil {
+		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryCreate, "Auth Server Scope", "")
+	}
+	scopeMap, ok := result.(map[string]interface{})
+	if !ok || scopeMap["id"] == nil {
+		return diag.Errorf("failed to parse auth server scope creation response or scope ID not found in response")
+	}
+
+	d.SetId(authServerChildID(authID, int(scopeMap["id"].(float64))))
+	tflog.Info(ctx, "[CREATED] Created auth server scope", map[string]interface{}{
+		"id": d.Id(),
+	})
+	return authServerScopeRead(ctx, d, m)
+}
+
+func authServerScopeRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*onelogin.OneloginSDK)
+	authID, scopeID, err := parseAuthServerChildID(d.Id(), "scope_id")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Info(ctx, "[READ] Reading auth server scope", map[string]interface{}{
+		"id": d.Id(),
+	})
+
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetAuthServerScopes(authID, nil) })
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			tflog.Info(ctx, "[NOT FOUND] Auth server not found, removing scope from state", map[string]interface{}{
+				"id": d.Id(),
+			})
+			d.SetId("")
+			return nil
+		}
+		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryRead, "Auth Server Scope", d.Id())
+	}
+
+	scope, ok := findAuthServerChild(result, "id", scopeID)
+	if !ok {
+		tflog.Info(ctx, "[NOT FOUND] Auth server scope not found, removing from state", map[string]interface{}{
+			"id": d.Id(),
+		})
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("auth_server_id", authID)
+	d.Set("scope_id", scopeID)
+	for k, v := range authserverscopeschema.Flatten(scope) {
+		if err := d.Set(k, v); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return nil
+}
+
+func authServerScopeUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*onelogin.OneloginSDK)
+	authID, scopeID, err := parseAuthServerChildID(d.Id(), "scope_id")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	scope := authserverscopeschema.Inflate(map[string]interface{}{
+		"value":       d.Get("value"),
+		"description": d.Get("description"),
+	})
+
+	tflog.Info(ctx, "[UPDATE] Updating auth server scope", map[string]interface{}{
+		"id": d.Id(),
+	})
+
+	_, err = utils.CallWithContext(ctx, func() (interface{}, error) { return client.UpdateAuthServerScope(authID, scopeID, &scope) })
+	if err != nil {
+		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryUpdate, "Auth Server Scope", d.Id())
+	}
+	return authServerScopeRead(ctx, d, m)
+}
+
+func authServerScopeDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*onelogin.OneloginSDK)
+	authID, scopeID, err := parseAuthServerChildID(d.Id(), "scope_id")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	_, err = utils.CallWithContext(ctx, func() (interface{}, error) { return client.DeleteAuthServerScope(authID, scopeID) })
+	if err != nil && !utils.IsNotFoundError(err) {
+		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryDelete, "Auth Server Scope", d.Id())
+	}
+
+	tflog.Info(ctx, "[DELETED] Deleted auth server scope", map[string]interface{}{
+		"id": d.Id(),
+	})
+	d.SetId("")
+	return nil
+}
@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"log"
 	"strconv"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
 	authserverschema "github.com/onelogin/terraform-provider-onelogin/ol_schema/auth_server"
+	"github.com/onelogin/terraform-provider-onelogin/utils"
 )
 
 // AuthServers returns a resource with the CRUD methods and Terraform Schema defined
@@ -20,8 +22,11 @@ func AuthServers() *schema.Resource {
 		ReadContext:   authServersRead,
 		UpdateContext: authServersUpdate,
 		DeleteContext: authServersDelete,
-		Importer:      &schema.ResourceImporter{},
-		Schema:        authserverschema.Schema(),
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(20 * time.Minute),
+		},
+		Importer: &schema.ResourceImporter{},
+		Schema:   authserverschema.Schema(),
 	}
 }
 
@@ -34,8 +39,11 @@ func authServersCreate(ctx context.Context, d *schema.ResourceData, m interface{
 		"configuration": d.Get("configuration"),
 	})
 
-	result, err := client.CreateAuthServer(&authServer)
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.CreateAuthServer(&authServer) })
 	if err != nil {
+		if utils.IsTimeout(ctx, err) {
+			return utils.TimeoutDiagnostics(utils.ErrorCategoryCreate, "Auth Server", "", err)
+		}
 		return diag.Errorf("error creating auth server: %v", err)
 	}
 
@@ -67,8 +75,11 @@ func authServersUpdate(ctx context.Context, d *schema.ResourceData, m interface{
 		return diag.Errorf("error converting id to integer: %v", err)
 	}
 
-	_, err = client.UpdateAuthServer(authID, &authServer)
+	_, err = utils.CallWithContext(ctx, func() (interface{}, error) { return client.UpdateAuthServer(authID, &authServer) })
 	if err != nil {
+		if utils.IsTimeout(ctx, err) {
+			return utils.TimeoutDiagnostics(utils.ErrorCategoryUpdate, "Auth Server", d.Id(), err)
+		}
 		return diag.Errorf("error updating auth server: %v", err)
 	}
 
@@ -84,8 +95,11 @@ func authServersRead(ctx context.Context, d *schema.ResourceData, m interface{})
 		return diag.Errorf("error converting id to integer: %v", err)
 	}
 
-	result, err := client.GetAuthServerByID(authID, nil)
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetAuthServerByID(authID, nil) })
 	if err != nil {
+		if utils.IsTimeout(ctx, err) {
+			return utils.TimeoutDiagnostics(utils.ErrorCategoryRead, "Auth Server", d.Id(), err)
+		}
 		log.Printf("[ERROR] There was a problem reading the auth server: %v", err)
 		return diag.FromErr(err)
 	}
@@ -149,8 +163,11 @@ func authServersDelete(ctx context.Context, d *schema.ResourceData, m interface{
 		return diag.Errorf("error converting id to integer: %v", err)
 	}
 
-	_, err = client.DeleteAuthServer(authID)
+	_, err = utils.CallWithContext(ctx, func() (interface{}, error) { return client.DeleteAuthServer(authID) })
 	if err != nil {
+		if utils.IsTimeout(ctx, err) {
+			return utils.TimeoutDiagnostics(utils.ErrorCategoryDelete, "Auth Server", d.Id(), err)
+		}
 		log.Printf("[ERROR] There was a problem deleting the auth server: %v", err)
 		return diag.FromErr(err)
 	}
@@ -160,3 +177,63 @@ func authServersDelete(ctx context.Context, d *schema.ResourceData, m interface{
 
 	return nil
 }
+
+// authServerChildID is the ID of a scope, claim or client app, which is only
+// unique within its authorization server: "auth_server_id:id".
+func authServerChildID(authID, id int) string {
+	return fmt.Sprintf("%d:%d", authID, id)
+}
+
+func parseAuthServerChildID(id, childKey string) (authID, childID int, err error) {
+	auth, child, err := utils.ParseNestedResourceImportId(id)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unexpected format of ID (%s), expected auth_server_id:%s", id, childKey)
+	}
+	if authID, err = strconv.Atoi(auth); err != nil {
+		return 0, 0, fmt.Errorf("invalid auth_server_id %q in ID (%s): %v", auth, id, err)
+	}
+	if childID, err = strconv.Atoi(child); err != nil {
+		return 0, 0, fmt.Errorf("invalid %s %q in ID (%s): %v", childKey, child, id, err)
+	}
+	return authID, childID, nil
+}
+
+// findAuthServerChild picks the item whose field is id out of a list of an
+// authorization server's scopes, claims or client apps. There is no endpoint
+// that reads one of them alone.
+func findAuthServerChild(result interface{}, field string, id int) (map[string]interface{}, bool) {
+	items, _ := result.([]interface{})
+	for _, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if v, ok := obj[field].(float64); ok && int(v) == id {
+			return obj, true
+		}
+	}
+	return nil, false
+}
+
+// authServerChildImporter imports a scope, claim or client app by
+// "auth_server_id:id". It reads what it imports, and refuses one that is not
+// there rather than putting a resource in state that the next refresh takes
+// out again.
+func authServerChildImporter(kind, childKey string, read schema.ReadContextFunc) *schema.ResourceImporter {
+	return &schema.ResourceImporter{
+		StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+			authID, childID, err := parseAuthServerChildID(d.Id(), childKey)
+			if err != nil {
+				return nil, err
+			}
+			d.SetId(authServerChildID(authID, childID))
+			if diags := read(ctx, d, m); diags.HasError() {
+				return nil, fmt.Errorf("reading %s %d of authorization server %d: %v", kind, childID, authID, diags[0].Summary)
+			}
+			if d.Id() == "" {
+				return nil, fmt.Errorf("authorization server %d has no %s %d", authID, kind, childID)
+			}
+			return []*schema.ResourceData{d}, nil
+		},
+	}
+}
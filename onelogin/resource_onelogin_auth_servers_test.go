@@ -1,9 +1,15 @@
 package onelogin
 
 import (
+	"context"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/onelogin/terraform-provider-onelogin/fakeapi"
 )
 
 func TestAccAuthServer_crud(t *testing.T) {
@@ -40,3 +46,109 @@ func TestAccAuthServer_crud(t *testing.T) {
 		},
 	})
 }
+
+func TestAccAuthServerScopesClaimsAndClientApps(t *testing.T) {
+	configs := GetFixtures([]string{"onelogin_auth_server_children_example.tf", "onelogin_auth_server_children_updated_example.tf"}, t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { TestAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: configs[0],
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("onelogin_auth_server_scope.read", "value", "contacts:read"),
+					resource.TestCheckResourceAttrSet("onelogin_auth_server_scope.read", "scope_id"),
+					resource.TestCheckResourceAttr("onelogin_auth_server_claim.groups", "name", "groups"),
+					resource.TestCheckResourceAttr("onelogin_auth_server_claim.groups", "user_attribute_mappings", "memberOf"),
+					resource.TestCheckResourceAttr("onelogin_auth_server_client_app.client", "scope_ids.#", "1"),
+					resource.TestCheckResourceAttrPair("onelogin_auth_server_client_app.client", "name", "onelogin_oidc_apps.client", "name"),
+				),
+			},
+			{
+				Config: configs[1],
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("onelogin_auth_server_scope.write", "description", "Create and change contacts"),
+					resource.TestCheckResourceAttr("onelogin_auth_server_claim.groups", "user_attribute_mappings", "email"),
+					resource.TestCheckResourceAttr("onelogin_auth_server_client_app.client", "scope_ids.#", "2"),
+				),
+			},
+			{
+				ResourceName:      "onelogin_auth_server_scope.read",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "onelogin_auth_server_claim.groups",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "onelogin_auth_server_client_app.client",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestParseAuthServerChildIDErrors(t *testing.T) {
+	for _, id := range []string{"", "12", "12:", ":34", "abc:34", "12:def"} {
+		t.Run(id, func(t *testing.T) {
+			if _, _, err := parseAuthServerChildID(id, "scope_id"); err == nil {
+				t.Fatalf("expected %q to be rejected", id)
+			}
+		})
+	}
+}
+
+// TestAuthServerScopeDrift covers a scope deleted outside Terraform, and an
+// import of one that is not there.
+func TestAuthServerScopeDrift(t *testing.T) {
+	srv := fakeapi.NewServer()
+	defer srv.Close()
+	meta := fakeAPIMeta(t, srv)
+	ctx := context.Background()
+
+	authID := srv.Seed(fakeapi.AuthServers, map[string]interface{}{"name": "API"})
+	authIDInt, _ := strconv.Atoi(authID)
+
+	r := AuthServerScope()
+	d := r.Data(nil)
+	d.Set("auth_server_id", authIDInt)
+	d.Set("value", "contacts:read")
+	if diags := r.CreateContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("unexpected error creating: %v", diags)
+	}
+	if !strings.HasPrefix(d.Id(), authID+":") || d.Get("scope_id").(int) == 0 {
+		t.Fatalf("expected an ID under auth server %s and the scope's own ID, got %q and %v", authID, d.Id(), d.Get("scope_id"))
+	}
+	id := d.Id()
+
+	importID := func(id string) ([]*schema.ResourceData, error) {
+		d := r.Data(nil)
+		d.SetId(id)
+		return r.Importer.StateContext(ctx, d, meta)
+	}
+	if out, err := importID(id); err != nil || out[0].Get("value") != "contacts:read" {
+		t.Fatalf("expected the scope to import, got %v", err)
+	}
+
+	// Deleted by somebody else, through their own copy.
+	other := r.Data(nil)
+	other.SetId(id)
+	if diags := r.DeleteContext(ctx, other, meta); diags.HasError() {
+		t.Fatalf("unexpected error deleting: %v", diags)
+	}
+
+	if diags := r.ReadContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("unexpected error reading: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Fatalf("expected a scope deleted outside Terraform to leave state, got %q", d.Id())
+	}
+
+	if _, err := importID(id); err == nil || !strings.Contains(err.Error(), "has no scope") {
+		t.Fatalf("expected the import of a missing scope to be refused, got %v", err)
+	}
+}
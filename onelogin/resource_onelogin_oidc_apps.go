@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -31,7 +32,10 @@ func OIDCApps() *schema.Resource {
 		ReadContext:   oidcAppRead,
 		UpdateContext: oidcAppUpdate,
 		DeleteContext: oidcAppDelete,
-		Importer:      &schema.ResourceImporter{},
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(20 * time.Minute),
+		},
+		Importer:      importer("OIDC app", appLookup(authMethodOIDC), objectID, "name"),
 		Schema:        appSchema,
 		SchemaVersion: 1,
 		StateUpgraders: []schema.StateUpgrader{
@@ -79,7 +83,7 @@ func oidcAppCreate(ctx context.Context, d *schema.ResourceData, m interface{}) d
 		"name": d.Get("name").(string),
 	})
 
-	result, err := client.CreateApp(oidcApp)
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.CreateApp(oidcApp) })
 	if err != nil {
 		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryCreate, "OIDC App", "")
 	}
@@ -113,7 +117,7 @@ func oidcAppRead(ctx context.Context, d *schema.ResourceData, m interface{}) dia
 		"id": aid,
 	})
 
-	result, err := client.GetAppByID(aid, nil)
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetAppByID(aid, nil) })
 	if err != nil {
 		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryRead, "OIDC App", d.Id())
 	}
@@ -190,7 +194,7 @@ func oidcAppUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) d
 		"id": aid,
 	})
 
-	_, err = client.UpdateApp(aid, oidcApp)
+	_, err = utils.CallWithContext(ctx, func() (interface{}, error) { return client.UpdateApp(aid, oidcApp) })
 	if err != nil {
 		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryUpdate, "OIDC App", d.Id())
 	}
@@ -3,11 +3,13 @@ package onelogin
 import (
 	"context"
 	"log"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
 	privilegeschema "github.com/onelogin/terraform-provider-onelogin/ol_schema/privilege"
+	"github.com/onelogin/terraform-provider-onelogin/utils"
 )
 
 // Privileges returns a resource with the CRUD methods and Terraform Schema defined
@@ -18,8 +20,11 @@ func Privileges() *schema.Resource {
 		ReadContext:   privilegeRead,
 		UpdateContext: privilegeUpdate,
 		DeleteContext: privilegeDelete,
-		Importer:      &schema.ResourceImporter{},
-		Schema:        privilegeSchema,
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(20 * time.Minute),
+		},
+		Importer: importer("privilege", privilegeLookup, objectID, "name"),
+		Schema:   privilegeSchema,
 	}
 }
 
@@ -39,8 +44,11 @@ func privilegeCreate(ctx context.Context, d *schema.ResourceData, m interface{})
 		return diag.Errorf("unable to inflate privilege: %v", err)
 	}
 
-	result, err := client.CreatePrivilege(privilege)
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.CreatePrivilege(privilege) })
 	if err != nil {
+		if utils.IsTimeout(ctx, err) {
+			return utils.TimeoutDiagnostics(utils.ErrorCategoryCreate, "Privilege", "", err)
+		}
 		return diag.Errorf("error creating privilege: %v", err)
 	}
 
@@ -62,8 +70,11 @@ func privilegeCreate(ctx context.Context, d *schema.ResourceData, m interface{})
 func privilegeRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*onelogin.OneloginSDK)
 
-	result, err := client.GetPrivilege(d.Id())
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetPrivilege(d.Id()) })
 	if err != nil {
+		if utils.IsTimeout(ctx, err) {
+			return utils.TimeoutDiagnostics(utils.ErrorCategoryRead, "Privilege", d.Id(), err)
+		}
 		log.Printf("[ERROR] There was a problem reading the privilege: %v", err)
 		return diag.FromErr(err)
 	}
@@ -164,8 +175,11 @@ func privilegeUpdate(ctx context.Context, d *schema.ResourceData, m interface{})
 		return diag.Errorf("unable to inflate privilege: %v", err)
 	}
 
-	_, err = client.UpdatePrivilege(d.Id(), privilege)
+	_, err = utils.CallWithContext(ctx, func() (interface{}, error) { return client.UpdatePrivilege(d.Id(), privilege) })
 	if err != nil {
+		if utils.IsTimeout(ctx, err) {
+			return utils.TimeoutDiagnostics(utils.ErrorCategoryUpdate, "Privilege", d.Id(), err)
+		}
 		return diag.Errorf("error updating privilege: %v", err)
 	}
 
@@ -178,8 +192,11 @@ func privilegeUpdate(ctx context.Context, d *schema.ResourceData, m interface{})
 func privilegeDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*onelogin.OneloginSDK)
 
-	_, err := client.DeletePrivilege(d.Id())
+	_, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.DeletePrivilege(d.Id()) })
 	if err != nil {
+		if utils.IsTimeout(ctx, err) {
+			return utils.TimeoutDiagnostics(utils.ErrorCategoryDelete, "Privilege", d.Id(), err)
+		}
 		log.Printf("[ERROR] There was a problem deleting the privilege: %v", err)
 		return diag.FromErr(err)
 	}
@@ -0,0 +1,314 @@
+package onelogin
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
+	"github.com/onelogin/terraform-provider-onelogin/utils"
+)
+
+// A role member resource holds one user, admin or app of a role and nothing
+// else. onelogin_roles owns the whole of each list it is given, so two
+// configurations adding their own users to a shared role take each other's
+// out; these add and remove only their own member, through the role's
+// sub-endpoints, and never touch the rest.
+
+// roleMemberKind is what tells the three role member resources apart.
+type roleMemberKind struct {
+	// attr is the role's membership attribute the member belongs to.
+	attr string
+	// key is the attribute that holds the member's ID.
+	key string
+	// noun names the member in messages.
+	noun string
+	// add and remove change the role's membership by the one member.
+	add, remove func(ctx context.Context, client *onelogin.OneloginSDK, roleID, memberID int) error
+}
+
+var (
+	roleUserKind = roleMemberKind{
+		attr: "users",
+		key:  "user_id",
+		noun: "user",
+		add: func(ctx context.Context, client *onelogin.OneloginSDK, roleID, userID int) error {
+			_, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.AddRoleUsers(roleID, []int{userID}) })
+			return err
+		},
+		remove: func(ctx context.Context, client *onelogin.OneloginSDK, roleID, userID int) error {
+			_, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.DeleteRoleUsers(roleID, []int{userID}) })
+			return err
+		},
+	}
+	roleAdminKind = roleMemberKind{
+		attr: "admins",
+		key:  "user_id",
+		noun: "admin",
+		add: func(ctx context.Context, client *onelogin.OneloginSDK, roleID, userID int) error {
+			_, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.AddRoleAdmins(roleID, []int{userID}) })
+			return err
+		},
+		remove: func(ctx context.Context, client *onelogin.OneloginSDK, roleID, userID int) error {
+			_, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.DeleteRoleAdmins(roleID, []int{userID}) })
+			return err
+		},
+	}
+	roleAppKind = roleMemberKind{
+		attr: "apps",
+		key:  "app_id",
+		noun: "app",
+		add: func(ctx context.Context, client *onelogin.OneloginSDK, roleID, appID int) error {
+			return changeRoleApps(ctx, client, roleID, appID, true)
+		},
+		remove: func(ctx context.Context, client *onelogin.OneloginSDK, roleID, appID int) error {
+			return changeRoleApps(ctx, client, roleID, appID, false)
+		},
+	}
+)
+
+// RoleUser returns the onelogin_role_user resource, which gives one user a
+// role without managing the role's other users.
+func RoleUser() *schema.Resource {
+	return roleMemberResource(roleUserKind)
+}
+
+// RoleAdmin returns the onelogin_role_admin resource, which makes one user an
+// admin of a role without managing the role's other admins.
+func RoleAdmin() *schema.Resource {
+	return roleMemberResource(roleAdminKind)
+}
+
+// RoleApp returns the onelogin_role_app resource, which gives a role one app
+// without managing the role's other apps.
+func RoleApp() *schema.Resource {
+	return roleMemberResource(roleAppKind)
+}
+
+func roleMemberResource(kind roleMemberKind) *schema.Resource {
+	return &schema.Resource{
+		CreateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			return roleMemberCreate(ctx, d, m, kind)
+		},
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			return roleMemberRead(ctx, d, m, kind)
+		},
+		DeleteContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			return roleMemberDelete(ctx, d, m, kind)
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				return roleMemberImport(ctx, d, m, kind)
+			},
+		},
+		// Reading walks the role's members a page at a time, as onelogin_roles
+		// does.
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(20 * time.Minute),
+			Read:    schema.DefaultTimeout(40 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"role_id": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the role",
+			},
+			kind.key: {
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: fmt.Sprintf("ID of the %s to add to the role", kind.noun),
+			},
+		},
+	}
+}
+
+// roleMemberID is a role member's ID, "role_id:member_id".
+func roleMemberID(roleID, memberID int) string {
+	return fmt.Sprintf("%d:%d", roleID, memberID)
+}
+
+func parseRoleMemberID(id string, kind roleMemberKind) (roleID, memberID int, err error) {
+	role, member, err := utils.ParseNestedResourceImportId(id)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unexpected format of ID (%s), expected role_id:%s", id, kind.key)
+	}
+	if roleID, err = strconv.Atoi(role); err != nil {
+		return 0, 0, fmt.Errorf("invalid role_id %q in ID (%s): %v", role, id, err)
+	}
+	if memberID, err = strconv.Atoi(member); err != nil {
+		return 0, 0, fmt.Errorf("invalid %s %q in ID (%s): %v", kind.key, member, id, err)
+	}
+	return roleID, memberID, nil
+}
+
+// roleMemberIDs returns every ID in membership attribute attr of role roleID.
+func roleMemberIDs(ctx context.Context, client *onelogin.OneloginSDK, roleID int, attr string) ([]int, error) {
+	for _, member := range roleMembers(client, roleID) {
+		if member.attr == attr {
+			return fetchAllMemberIDs(ctx, member.fetch)
+		}
+	}
+	return nil, fmt.Errorf("roles have no %s", attr)
+}
+
+func containsInt(ids []int, id int) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func roleMemberCreate(ctx context.Context, d *schema.ResourceData, m interface{}, kind roleMemberKind) diag.Diagnostics {
+	client := m.(*onelogin.OneloginSDK)
+	roleID := d.Get("role_id").(int)
+	memberID := d.Get(kind.key).(int)
+
+	tflog.Info(ctx, "[CREATE] Adding role member", map[string]interface{}{
+		"role_id": roleID,
+		"kind":    kind.noun,
+		kind.key:  memberID,
+	})
+
+	if err := kind.add(ctx, client, roleID, memberID); err != nil {
+		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryCreate, fmt.Sprintf("Role %s", kind.noun), roleMemberID(roleID, memberID))
+	}
+
+	d.SetId(roleMemberID(roleID, memberID))
+	return roleMemberRead(ctx, d, m, kind)
+}
+
+// roleMemberRead checks the member is still in the role and takes it out of
+// state if not. The role's other members are read past, not recorded.
+func roleMemberRead(ctx context.Context, d *schema.ResourceData, m interface{}, kind roleMemberKind) diag.Diagnostics {
+	client := m.(*onelogin.OneloginSDK)
+	roleID, memberID, err := parseRoleMemberID(d.Id(), kind)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Info(ctx, "[READ] Reading role member", map[string]interface{}{
+		"role_id": roleID,
+		"kind":    kind.noun,
+		kind.key:  memberID,
+	})
+
+	ids, err := roleMemberIDs(ctx, client, roleID, kind.attr)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			tflog.Info(ctx, "[NOT FOUND] Role not found, removing member from state", map[string]interface{}{
+				"role_id": roleID,
+			})
+			d.SetId("")
+			return nil
+		}
+		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryRead, fmt.Sprintf("Role %s", kind.attr), strconv.Itoa(roleID))
+	}
+	if !containsInt(ids, memberID) {
+		tflog.Info(ctx, "[NOT FOUND] Member no longer in role, removing from state", map[string]interface{}{
+			"role_id": roleID,
+			"kind":    kind.noun,
+			kind.key:  memberID,
+		})
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("role_id", roleID); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(kind.key, memberID); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func roleMemberDelete(ctx context.Context, d *schema.ResourceData, m interface{}, kind roleMemberKind) diag.Diagnostics {
+	client := m.(*onelogin.OneloginSDK)
+	roleID := d.Get("role_id").(int)
+	memberID := d.Get(kind.key).(int)
+
+	tflog.Info(ctx, "[DELETE] Removing role member", map[string]interface{}{
+		"role_id": roleID,
+		"kind":    kind.noun,
+		kind.key:  memberID,
+	})
+
+	if err := kind.remove(ctx, client, roleID, memberID); err != nil {
+		// A role that is already gone has no members left to remove.
+		if utils.IsNotFoundError(err) {
+			d.SetId("")
+			return nil
+		}
+		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryDelete, fmt.Sprintf("Role %s", kind.noun), d.Id())
+	}
+	d.SetId("")
+	return nil
+}
+
+// roleMemberImport adopts an existing member by "role_id:member_id". The
+// member has to be in the role already: importing one that is not would put a
+// resource in state that the next refresh silently takes out again.
+func roleMemberImport(ctx context.Context, d *schema.ResourceData, m interface{}, kind roleMemberKind) ([]*schema.ResourceData, error) {
+	roleID, memberID, err := parseRoleMemberID(d.Id(), kind)
+	if err != nil {
+		return nil, err
+	}
+
+	client := m.(*onelogin.OneloginSDK)
+	ids, err := roleMemberIDs(ctx, client, roleID, kind.attr)
+	if err != nil {
+		return nil, fmt.Errorf("reading the %s of role %d: %w", kind.attr, roleID, err)
+	}
+	if !containsInt(ids, memberID) {
+		return nil, fmt.Errorf("%s %d is not in role %d", kind.noun, memberID, roleID)
+	}
+
+	d.SetId(roleMemberID(roleID, memberID))
+	d.Set("role_id", roleID)
+	d.Set(kind.key, memberID)
+	return []*schema.ResourceData{d}, nil
+}
+
+// roleAppsMu serialises changes to roles' apps. Unlike users and admins, a
+// role's apps can only be replaced as a whole, so adding or removing one is a
+// read followed by a write, and two onelogin_role_app resources on the same
+// role applied side by side would otherwise each write back the list without
+// the other's app.
+var roleAppsMu sync.Mutex
+
+// changeRoleApps adds appID to role roleID's apps, or removes it, leaving the
+// rest as they are.
+func changeRoleApps(ctx context.Context, client *onelogin.OneloginSDK, roleID, appID int, add bool) error {
+	roleAppsMu.Lock()
+	defer roleAppsMu.Unlock()
+
+	current, err := roleMemberIDs(ctx, client, roleID, "apps")
+	if err != nil {
+		return err
+	}
+
+	appIDs := make([]int, 0, len(current)+1)
+	for _, id := range current {
+		if id != appID {
+			appIDs = append(appIDs, id)
+		}
+	}
+	if add {
+		appIDs = append(appIDs, appID)
+	} else if len(appIDs) == len(current) {
+		// Already gone; there is nothing to write.
+		return nil
+	}
+
+	_, err = utils.CallWithContext(ctx, func() (interface{}, error) { return client.UpdateRoleApps(roleID, appIDs) })
+	return err
+}
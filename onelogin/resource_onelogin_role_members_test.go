@@ -0,0 +1,218 @@
+package onelogin
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
+
+	"github.com/onelogin/terraform-provider-onelogin/fakeapi"
+)
+
+func TestAccRoleMembers(t *testing.T) {
+	name := newFixtureSuffix() + "-role-members"
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { TestAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRoleMembersConfig(name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.onelogin_role.shared", "users.#", "1"),
+					resource.TestCheckResourceAttr("data.onelogin_role.shared", "admins.#", "1"),
+					resource.TestCheckResourceAttr("data.onelogin_role.shared", "apps.#", "1"),
+				),
+			},
+			{
+				ResourceName:      "onelogin_role_user.member",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccRoleMembersConfig(name string) string {
+	return fmt.Sprintf(`
+resource "onelogin_roles" "shared" {
+  name = %[1]q
+
+  lifecycle {
+    ignore_changes = [users, admins, apps]
+  }
+}
+
+resource "onelogin_users" "member" {
+  username = %[1]q
+  email    = "%[1]s@example.com"
+}
+
+resource "onelogin_saml_apps" "app" {
+  name         = %[1]q
+  connector_id = 50534
+}
+
+resource "onelogin_role_user" "member" {
+  role_id = onelogin_roles.shared.id
+  user_id = onelogin_users.member.id
+}
+
+resource "onelogin_role_admin" "member" {
+  role_id = onelogin_roles.shared.id
+  user_id = onelogin_users.member.id
+}
+
+resource "onelogin_role_app" "app" {
+  role_id = onelogin_roles.shared.id
+  app_id  = onelogin_saml_apps.app.id
+}
+
+data "onelogin_role" "shared" {
+  id = onelogin_roles.shared.id
+
+  depends_on = [onelogin_role_user.member, onelogin_role_admin.member, onelogin_role_app.app]
+}
+`, name)
+}
+
+func TestParseRoleMemberIDErrors(t *testing.T) {
+	for _, id := range []string{"", "123", "123:", ":456", "abc:456", "123:def"} {
+		t.Run(id, func(t *testing.T) {
+			_, _, err := parseRoleMemberID(id, roleUserKind)
+			if err == nil {
+				t.Fatalf("expected %q to be rejected", id)
+			}
+		})
+	}
+}
+
+// TestRoleMembersLeaveOthersAlone covers the reason these resources exist: a
+// role member resource adds and removes its own member and nobody else's.
+func TestRoleMembersLeaveOthersAlone(t *testing.T) {
+	srv := fakeapi.NewServer()
+	defer srv.Close()
+	meta := fakeAPIMeta(t, srv)
+	client := meta.(*onelogin.OneloginSDK)
+	ctx := context.Background()
+
+	seed := func(kind fakeapi.Kind, obj map[string]interface{}) int {
+		id, _ := strconv.Atoi(srv.Seed(kind, obj))
+		return id
+	}
+	ours := seed(fakeapi.Users, map[string]interface{}{"username": "ours", "email": "ours@example.com"})
+	theirs := seed(fakeapi.Users, map[string]interface{}{"username": "theirs", "email": "theirs@example.com"})
+	roleID := seed(fakeapi.Roles, map[string]interface{}{
+		"name":   "Shared",
+		"users":  []interface{}{float64(theirs)},
+		"admins": []interface{}{float64(theirs)},
+	})
+	ourApp := seed(fakeapi.Apps, map[string]interface{}{"name": "Ours", "connector_id": float64(1)})
+	theirApp := seed(fakeapi.Apps, map[string]interface{}{
+		"name": "Theirs", "connector_id": float64(1), "role_ids": []interface{}{float64(roleID)},
+	})
+
+	for _, tt := range []struct {
+		resource  *schema.Resource
+		kind      roleMemberKind
+		ours, out int
+	}{
+		{RoleUser(), roleUserKind, ours, theirs},
+		{RoleAdmin(), roleAdminKind, ours, theirs},
+		{RoleApp(), roleAppKind, ourApp, theirApp},
+	} {
+		t.Run(tt.kind.noun, func(t *testing.T) {
+			members := func() []int {
+				t.Helper()
+				ids, err := roleMemberIDs(ctx, client, roleID, tt.kind.attr)
+				if err != nil {
+					t.Fatalf("unexpected error reading the role's %s: %v", tt.kind.attr, err)
+				}
+				return ids
+			}
+
+			d := tt.resource.Data(nil)
+			d.Set("role_id", roleID)
+			d.Set(tt.kind.key, tt.ours)
+			if diags := tt.resource.CreateContext(ctx, d, meta); diags.HasError() {
+				t.Fatalf("unexpected error creating: %v", diags)
+			}
+			if d.Id() != roleMemberID(roleID, tt.ours) {
+				t.Fatalf("expected ID %s, got %q", roleMemberID(roleID, tt.ours), d.Id())
+			}
+			if got := members(); !containsInt(got, tt.ours) || !containsInt(got, tt.out) {
+				t.Fatalf("expected both %d and %d in the role's %s, got %v", tt.ours, tt.out, tt.kind.attr, got)
+			}
+
+			if diags := tt.resource.DeleteContext(ctx, d, meta); diags.HasError() {
+				t.Fatalf("unexpected error deleting: %v", diags)
+			}
+			if got := members(); containsInt(got, tt.ours) || !containsInt(got, tt.out) {
+				t.Fatalf("expected only %d left in the role's %s, got %v", tt.out, tt.kind.attr, got)
+			}
+
+			// Someone else took the member out; the next refresh drops it
+			// from state rather than failing.
+			d.SetId(roleMemberID(roleID, tt.ours))
+			if diags := tt.resource.ReadContext(ctx, d, meta); diags.HasError() {
+				t.Fatalf("unexpected error reading: %v", diags)
+			}
+			if d.Id() != "" {
+				t.Fatalf("expected a member no longer in the role to be removed from state, got %q", d.Id())
+			}
+		})
+	}
+}
+
+func TestRoleMemberImport(t *testing.T) {
+	srv := fakeapi.NewServer()
+	defer srv.Close()
+	meta := fakeAPIMeta(t, srv)
+
+	userID, _ := strconv.Atoi(srv.Seed(fakeapi.Users, map[string]interface{}{"username": "member", "email": "member@example.com"}))
+	otherID, _ := strconv.Atoi(srv.Seed(fakeapi.Users, map[string]interface{}{"username": "other", "email": "other@example.com"}))
+	roleID, _ := strconv.Atoi(srv.Seed(fakeapi.Roles, map[string]interface{}{
+		"name": "Engineering", "users": []interface{}{float64(userID)},
+	}))
+
+	importID := func(id string) ([]*schema.ResourceData, error) {
+		r := RoleUser()
+		d := r.Data(nil)
+		d.SetId(id)
+		return r.Importer.StateContext(context.Background(), d, meta)
+	}
+
+	t.Run("a member", func(t *testing.T) {
+		out, err := importID(roleMemberID(roleID, userID))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(out) != 1 || out[0].Get("role_id").(int) != roleID || out[0].Get("user_id").(int) != userID {
+			t.Fatalf("expected role %d user %d, got %v", roleID, userID, out)
+		}
+	})
+
+	t.Run("not a member", func(t *testing.T) {
+		_, err := importID(roleMemberID(roleID, otherID))
+		if err == nil || !strings.Contains(err.Error(), "is not in role") {
+			t.Fatalf("expected the import to be refused, got %v", err)
+		}
+	})
+
+	t.Run("a role that does not exist", func(t *testing.T) {
+		if _, err := importID(roleMemberID(roleID+1000, userID)); err == nil {
+			t.Fatal("expected the import to be refused")
+		}
+	})
+
+	t.Run("a malformed ID", func(t *testing.T) {
+		_, err := importID("123")
+		if err == nil || !strings.Contains(err.Error(), "role_id:user_id") {
+			t.Fatalf("expected the expected format to be named, got %v", err)
+		}
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -24,8 +25,16 @@ func Roles() *schema.Resource {
 		ReadContext:   roleRead,
 		UpdateContext: roleUpdate,
 		DeleteContext: roleDelete,
-		Importer:      &schema.ResourceImporter{},
-		Schema:        roleschema.Schema(),
+		// Reading and updating a role walks its users, admins and apps a page
+		// at a time, which in a large tenant outlasts everything else a resource
+		// does.
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(20 * time.Minute),
+			Read:    schema.DefaultTimeout(40 * time.Minute),
+			Update:  schema.DefaultTimeout(40 * time.Minute),
+		},
+		Importer: importer("role", roleLookup, objectID, "name"),
+		Schema:   roleschema.Schema(),
 	}
 }
 
@@ -143,26 +152,9 @@ func roleRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.D
 		return diag.FromErr(err)
 	}
 
-	// Membership is not part of the base role object — it lives on three separate
-	// sub-endpoints, each of which is paginated.
-	memberAttrs := []struct {
-		attr  string
-		fetch memberFetcher
-	}{
-		{"apps", func(ctx context.Context, q *roleschema.RoleQuery) (interface{}, *models.PaginationInfo, error) {
-			return client.GetRoleAppsWithPaginationAndContext(ctx, rid, q)
-		}},
-		{"users", func(ctx context.Context, q *roleschema.RoleQuery) (interface{}, *models.PaginationInfo, error) {
-			return client.GetRoleUsersWithPaginationAndContext(ctx, rid, q)
-		}},
-		{"admins", func(ctx context.Context, q *roleschema.RoleQuery) (interface{}, *models.PaginationInfo, error) {
-			return client.GetRoleAdminsWithPaginationAndContext(ctx, rid, q)
-		}},
-	}
-
 	skip := skippedMembershipAttrs(d)
 
-	for _, member := range memberAttrs {
+	for _, member := range roleMembers(client, rid) {
 		// Skipping means not calling the endpoint at all, which is the point:
 		// the cost is the page walk, not the diff. State keeps whatever it
 		// already held rather than being blanked, so a skipped attribute does
@@ -208,9 +200,14 @@ func roleRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.D
 // available, which is why this is a stored attribute rather than something
 // inferred.
 func skippedMembershipAttrs(d *schema.ResourceData) map[string]bool {
+	return membershipAttrsIn(d, "skip_membership_refresh")
+}
+
+// membershipAttrsIn returns the membership attributes named in the set key.
+func membershipAttrsIn(d *schema.ResourceData, key string) map[string]bool {
 	skip := make(map[string]bool, len(roleschema.MembershipAttrs))
 
-	raw, ok := d.Get("skip_membership_refresh").(*schema.Set)
+	raw, ok := d.Get(key).(*schema.Set)
 	if !ok {
 		return skip
 	}
@@ -225,6 +222,30 @@ func skippedMembershipAttrs(d *schema.ResourceData) map[string]bool {
 // memberFetcher retrieves one page of a role sub-endpoint (apps, users or admins).
 type memberFetcher func(context.Context, *roleschema.RoleQuery) (interface{}, *models.PaginationInfo, error)
 
+// roleMember is one of a role's membership attributes and the sub-endpoint it
+// is read from.
+type roleMember struct {
+	attr  string
+	fetch memberFetcher
+}
+
+// roleMembers returns the membership attributes of role rid. Membership is not
+// part of the base role object — it lives on three separate sub-endpoints,
+// each of which is paginated.
+func roleMembers(client *onelogin.OneloginSDK, rid int) []roleMember {
+	return []roleMember{
+		{"apps", func(ctx context.Context, q *roleschema.RoleQuery) (interface{}, *models.PaginationInfo, error) {
+			return client.GetRoleAppsWithPaginationAndContext(ctx, rid, q)
+		}},
+		{"users", func(ctx context.Context, q *roleschema.RoleQuery) (interface{}, *models.PaginationInfo, error) {
+			return client.GetRoleUsersWithPaginationAndContext(ctx, rid, q)
+		}},
+		{"admins", func(ctx context.Context, q *roleschema.RoleQuery) (interface{}, *models.PaginationInfo, error) {
+			return client.GetRoleAdminsWithPaginationAndContext(ctx, rid, q)
+		}},
+	}
+}
+
 // rolePageLimit is the page size requested when walking role sub-endpoints.
 // Verified against the V2 API: GET /api/2/roles/{id}/users and .../apps with
 // ?limit=100 both return 200 with page-items: 100 and a bare JSON array.
@@ -303,9 +324,6 @@ func roleUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag
 	rid, _ := strconv.Atoi(d.Id())
 	client := m.(*onelogin.OneloginSDK)
 
-	// Add debug logging to client
-	utils.AddRequestResponseLogging(ctx, client)
-
 	tflog.Info(ctx, "[UPDATE] Updating role", map[string]interface{}{
 		"id": rid,
 	})
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -42,7 +43,10 @@ func SAMLApps() *schema.Resource {
 		ReadContext:   samlAppRead,
 		UpdateContext: samlAppUpdate,
 		DeleteContext: samlAppDelete,
-		Importer:      &schema.ResourceImporter{},
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(20 * time.Minute),
+		},
+		Importer:      importer("SAML app", appLookup(authMethodSAML), objectID, "name"),
 		Schema:        appSchema,
 		SchemaVersion: 1,
 		StateUpgraders: []schema.StateUpgrader{
@@ -97,7 +101,7 @@ func samlAppCreate(ctx context.Context, d *schema.ResourceData, m interface{}) d
 		"name": d.Get("name").(string),
 	})
 
-	result, err := client.CreateApp(samlApp)
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.CreateApp(samlApp) })
 	if err != nil {
 		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryCreate, "SAML App", "")
 	}
@@ -132,7 +136,7 @@ func samlAppRead(ctx context.Context, d *schema.ResourceData, m interface{}) dia
 		"id": aid,
 	})
 
-	result, err := client.GetAppByID(aid, nil)
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetAppByID(aid, nil) })
 	if err != nil {
 		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryRead, "SAML App", d.Id())
 	}
@@ -203,16 +207,18 @@ func samlAppRead(ctx context.Context, d *schema.ResourceData, m interface{}) dia
 	}
 
 	// Handle SSO if it exists
+	var diags diag.Diagnostics
 	if v, ok := appMap["sso"]; ok {
 		if ssoData, ok := v.(map[string]interface{}); ok {
 			tflog.Debug(ctx, "Flattening SSO data", map[string]interface{}{
 				"sso_data": ssoData,
 			})
 			d.Set("sso", appssoschema.Flatten(ssoData))
+			diags = certificateExpiryDiagnostics(ctx, d.Id(), ssoData, time.Now())
 		}
 	}
 
-	return nil
+	return diags
 }
 
 // samlAppUpdate takes a pointer to the ResourceData Struct and a HTTP client and
@@ -242,7 +248,7 @@ func samlAppUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) d
 		"id": aid,
 	})
 
-	_, err = client.UpdateApp(aid, samlApp)
+	_, err = utils.CallWithContext(ctx, func() (interface{}, error) { return client.UpdateApp(aid, samlApp) })
 	if err != nil {
 		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryUpdate, "SAML App", d.Id())
 	}
@@ -0,0 +1,197 @@
+package onelogin
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
+	samlcertificateschema "github.com/onelogin/terraform-provider-onelogin/ol_schema/saml_certificate"
+	"github.com/onelogin/terraform-provider-onelogin/utils"
+)
+
+// SAMLCertificate returns the onelogin_saml_certificate resource, a signing
+// certificate a SAML app can use in place of the account's standard one.
+// OneLogin either generates it, key and all, or is given one to upload. A SAML
+// app is bound to it through configuration.certificate_id.
+func SAMLCertificate() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: samlCertificateCreate,
+		ReadContext:   samlCertificateRead,
+		UpdateContext: samlCertificateUpdate,
+		DeleteContext: samlCertificateDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(20 * time.Minute),
+		},
+		Importer: importer("SAML certificate", certificateLookup, objectID, "name"),
+		Schema:   samlcertificateschema.Schema(),
+	}
+}
+
+func samlCertificateCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*onelogin.OneloginSDK)
+	config := map[string]interface{}{
+		"name":            d.Get("name"),
+		"key_size":        d.Get("key_size"),
+		"validity_years":  d.Get("validity_years"),
+		"certificate_pem": d.Get("certificate_pem"),
+		"private_key_pem": d.Get("private_key_pem"),
+	}
+	body := samlcertificateschema.Inflate(config)
+	uploaded := samlcertificateschema.Uploaded(config)
+
+	tflog.Info(ctx, "[CREATE] Creating SAML certificate", map[string]interface{}{
+		"name":     d.Get("name").(string),
+		"uploaded": uploaded,
+	})
+
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) {
+		if uploaded {
+			return client.CreateCertificate(body)
+		}
+		return client.GenerateCertificate(body)
+	})
+	if err != nil {
+		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryCreate, "SAML Certificate", "")
+	}
+
+	cert, ok := result.(map[string]interface{})
+	if !ok || cert["id"] == nil {
+		return diag.Errorf("failed to parse SAML certificate creation response or certificate ID not found in response")
+	}
+	d.SetId(objectID(cert))
+
+	tflog.Info(ctx, "[CREATED] Created SAML certificate", map[string]interface{}{
+		"id": d.Id(),
+	})
+	return samlCertificateRead(ctx, d, m)
+}
+
+func samlCertificateRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*onelogin.OneloginSDK)
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diag.Errorf("invalid SAML certificate ID %q: %v", d.Id(), err)
+	}
+
+	tflog.Info(ctx, "[READ] Reading SAML certificate", map[string]interface{}{
+		"id": id,
+	})
+
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetCertificate(id) })
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			tflog.Info(ctx, "[NOT FOUND] SAML certificate not found, removing from state", map[string]interface{}{
+				"id": id,
+			})
+			d.SetId("")
+			return nil
+		}
+		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryRead, "SAML Certificate", d.Id())
+	}
+
+	cert, ok := result.(map[string]interface{})
+	if !ok {
+		return diag.Errorf("failed to parse SAML certificate response")
+	}
+	flat, err := samlcertificateschema.Flatten(cert)
+	if err != nil {
+		return diag.Errorf("reading SAML certificate %s: %v", d.Id(), err)
+	}
+	for k, v := range flat {
+		if err := d.Set(k, v); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return nil
+}
+
+// samlCertificateUpdate renames the certificate, which is all that can change
+// without replacing it.
+func samlCertificateUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*onelogin.OneloginSDK)
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diag.Errorf("invalid SAML certificate ID %q: %v", d.Id(), err)
+	}
+
+	tflog.Info(ctx, "[UPDATE] Renaming SAML certificate", map[string]interface{}{
+		"id": id,
+	})
+
+	_, err = utils.CallWithContext(ctx, func() (interface{}, error) {
+		return client.UpdateCertificate(id, map[string]interface{}{"name": d.Get("name")})
+	})
+	if err != nil {
+		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryUpdate, "SAML Certificate", d.Id())
+	}
+	return samlCertificateRead(ctx, d, m)
+}
+
+// samlCertificateDelete deletes the certificate. OneLogin refuses while an app
+// still signs with it, which is why a certificate being rotated wants
+// create_before_destroy: the apps move to the new one before the old one goes.
+func samlCertificateDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*onelogin.OneloginSDK)
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diag.Errorf("invalid SAML certificate ID %q: %v", d.Id(), err)
+	}
+
+	_, err = utils.CallWithContext(ctx, func() (interface{}, error) { return nil, client.DeleteCertificate(id) })
+	if err != nil && !utils.IsNotFoundError(err) {
+		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryDelete, "SAML Certificate", d.Id())
+	}
+
+	tflog.Info(ctx, "[DELETED] Deleted SAML certificate", map[string]interface{}{
+		"id": id,
+	})
+	d.SetId("")
+	return nil
+}
+
+// certificateExpiryWarning is how close to expiry an app's signing certificate
+// has to be for reading the app to warn about it.
+const certificateExpiryWarning = 30 * 24 * time.Hour
+
+// certificateExpiryDiagnostics warns when the certificate app signs with
+// expires within certificateExpiryWarning, or has expired. A read's warnings
+// are shown with the plan, so the warning comes while there is time to rotate.
+// A certificate that cannot be parsed is left alone: the warning is only a
+// courtesy.
+func certificateExpiryDiagnostics(ctx context.Context, app string, ssoData map[string]interface{}, now time.Time) diag.Diagnostics {
+	node, ok := ssoData["certificate"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	value, _ := node["value"].(string)
+	cert, err := utils.ParseCertificate(value)
+	if err != nil {
+		tflog.Debug(ctx, "Could not parse the app's signing certificate, not checking its expiry", map[string]interface{}{
+			"app":   app,
+			"error": err.Error(),
+		})
+		return nil
+	}
+	left := cert.NotAfter.Sub(now)
+	if left > certificateExpiryWarning {
+		return nil
+	}
+
+	name, _ := node["name"].(string)
+	summary := fmt.Sprintf("SAML app %s signs with a certificate that expires in %d days", app, int(left.Hours()/24))
+	if left <= 0 {
+		summary = fmt.Sprintf("SAML app %s signs with a certificate that has expired", app)
+	}
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  summary,
+		Detail: fmt.Sprintf("The certificate %q (fingerprint %s) expires at %s. Bind a new onelogin_saml_certificate through configuration.certificate_id, and update the service provider's copy, before then.",
+			name, cert.Fingerprint, cert.NotAfter.Format(time.RFC3339)),
+	}}
+}
@@ -0,0 +1,220 @@
+package onelogin
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+
+	"github.com/onelogin/terraform-provider-onelogin/fakeapi"
+)
+
+func TestAccSAMLCertificate(t *testing.T) {
+	name := newFixtureSuffix() + "-saml-cert"
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { TestAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSAMLCertificateConfig(name, "signing"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("onelogin_saml_certificate.signing", "key_size", "2048"),
+					resource.TestCheckResourceAttr("onelogin_saml_certificate.signing", "validity_years", "3"),
+					resource.TestCheckResourceAttrSet("onelogin_saml_certificate.signing", "fingerprint"),
+					resource.TestCheckResourceAttrSet("onelogin_saml_certificate.signing", "expires_at"),
+					resource.TestCheckResourceAttrPair("onelogin_saml_apps.saml", "configuration.certificate_id", "onelogin_saml_certificate.signing", "id"),
+				),
+			},
+			{
+				// Rotation: the app moves to the new certificate before the
+				// old one, which OneLogin will not delete while in use, goes.
+				Config: testAccSAMLCertificateConfig(name, "rotated"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("onelogin_saml_apps.saml", "configuration.certificate_id", "onelogin_saml_certificate.rotated", "id"),
+				),
+			},
+			{
+				ResourceName:      "onelogin_saml_certificate.rotated",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// testAccSAMLCertificateConfig binds a SAML app to the certificate named
+// bound.
+func testAccSAMLCertificateConfig(name, bound string) string {
+	return fmt.Sprintf(`
+resource "onelogin_saml_certificate" %[2]q {
+  name = "%[1]s-%[2]s"
+
+  lifecycle {
+    create_before_destroy = true
+  }
+}
+
+resource "onelogin_saml_apps" "saml" {
+  name         = %[1]q
+  connector_id = 50534
+
+  configuration = {
+    signature_algorithm = "SHA-256"
+    certificate_id      = onelogin_saml_certificate.%[2]s.id
+  }
+}
+`, name, bound)
+}
+
+// testCertificatePEM is a self-signed certificate, with the key it was made
+// with, valid until notAfter.
+func testCertificatePEM(t *testing.T, notAfter time.Time) (certPEM, keyPEM string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    notAfter.AddDate(-1, 0, 0),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+}
+
+func TestSAMLCertificate(t *testing.T) {
+	srv := fakeapi.NewServer()
+	defer srv.Close()
+	meta := fakeAPIMeta(t, srv)
+	ctx := context.Background()
+	r := SAMLCertificate()
+
+	t.Run("generated", func(t *testing.T) {
+		d := r.Data(nil)
+		d.Set("name", "Generated")
+		d.Set("validity_years", 2)
+		if diags := r.CreateContext(ctx, d, meta); diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+		if d.Get("key_size") != 2048 || d.Get("validity_years") != 2 {
+			t.Fatalf("expected a 2048 bit key valid for 2 years, got %v and %v", d.Get("key_size"), d.Get("validity_years"))
+		}
+		notBefore, _ := time.Parse(time.RFC3339, d.Get("not_before").(string))
+		expiresAt, _ := time.Parse(time.RFC3339, d.Get("expires_at").(string))
+		if !expiresAt.Equal(notBefore.AddDate(2, 0, 0)) {
+			t.Fatalf("expected the certificate to expire two years after %s, got %s", notBefore, expiresAt)
+		}
+
+		// An import has only the ID to go on, and reads the validity from the
+		// certificate's dates, so a configuration that sets it is not planned
+		// as a replacement.
+		imported := r.Data(nil)
+		imported.SetId(d.Id())
+		if diags := r.ReadContext(ctx, imported, meta); diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+		if imported.Get("validity_years") != 2 {
+			t.Fatalf("expected an imported certificate to be read as valid for 2 years, got %v", imported.Get("validity_years"))
+		}
+
+		d.Set("name", "Renamed")
+		if diags := r.UpdateContext(ctx, d, meta); diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+		if d.Get("name") != "Renamed" {
+			t.Fatalf("expected the certificate to be renamed, got %v", d.Get("name"))
+		}
+	})
+
+	t.Run("uploaded", func(t *testing.T) {
+		certPEM, keyPEM := testCertificatePEM(t, time.Now().AddDate(1, 0, 0))
+		d := r.Data(nil)
+		d.Set("name", "Uploaded")
+		d.Set("certificate_pem", certPEM)
+		d.Set("private_key_pem", keyPEM)
+		if diags := r.CreateContext(ctx, d, meta); diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+		if d.Get("certificate") != certPEM {
+			t.Fatalf("expected the uploaded certificate back, got %v", d.Get("certificate"))
+		}
+		if fp := d.Get("fingerprint").(string); len(strings.Split(fp, ":")) != 20 {
+			t.Fatalf("expected a SHA-1 fingerprint, got %q", fp)
+		}
+	})
+
+	t.Run("in use", func(t *testing.T) {
+		d := r.Data(nil)
+		d.Set("name", "Bound")
+		if diags := r.CreateContext(ctx, d, meta); diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+		srv.Seed(fakeapi.Apps, map[string]interface{}{
+			"name": "Bound", "connector_id": float64(1),
+			"configuration": map[string]interface{}{"certificate_id": d.Id()},
+		})
+		if diags := r.DeleteContext(ctx, d, meta); !diags.HasError() {
+			t.Fatal("expected deleting a certificate an app signs with to fail")
+		}
+	})
+
+	t.Run("deleted outside Terraform", func(t *testing.T) {
+		d := r.Data(nil)
+		d.SetId("999999")
+		if diags := r.ReadContext(ctx, d, meta); diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+		if d.Id() != "" {
+			t.Fatal("expected the certificate to be removed from state")
+		}
+	})
+}
+
+func TestCertificateExpiryDiagnostics(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	sso := func(notAfter time.Time) map[string]interface{} {
+		certPEM, _ := testCertificatePEM(t, notAfter)
+		return map[string]interface{}{"certificate": map[string]interface{}{"name": "Signing", "value": certPEM}}
+	}
+
+	for name, tt := range map[string]struct {
+		sso  map[string]interface{}
+		want string
+	}{
+		"far from expiry": {sso: sso(now.AddDate(1, 0, 0))},
+		"nearing expiry":  {sso: sso(now.AddDate(0, 0, 10)), want: "expires in 10 days"},
+		"expired":         {sso: sso(now.AddDate(0, 0, -1)), want: "has expired"},
+		"not a certificate": {sso: map[string]interface{}{
+			"certificate": map[string]interface{}{"value": "-----BEGIN CERTIFICATE-----\nZmFrZWFwaQ==\n-----END CERTIFICATE-----"},
+		}},
+		"no certificate": {sso: map[string]interface{}{"client_id": "abc"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			diags := certificateExpiryDiagnostics(context.Background(), "42", tt.sso, now)
+			if tt.want == "" {
+				if len(diags) != 0 {
+					t.Fatalf("expected no warning, got %v", diags)
+				}
+				return
+			}
+			if len(diags) != 1 || diags.HasError() || !strings.Contains(diags[0].Summary, tt.want) {
+				t.Fatalf("expected one warning saying %q, got %v", tt.want, diags)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -22,6 +23,9 @@ func SelfRegistrationProfiles() *schema.Resource {
 		ReadContext:   selfRegistrationProfileRead,
 		UpdateContext: selfRegistrationProfileUpdate,
 		DeleteContext: selfRegistrationProfileDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -58,7 +62,7 @@ func selfRegistrationProfileCreate(ctx context.Context, d *schema.ResourceData,
 		"name": profile.Name,
 	})
 
-	result, err := client.CreateSelfRegistrationProfile(profile)
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.CreateSelfRegistrationProfile(profile) })
 	if err != nil {
 		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryCreate, "Self-Registration Profile", "")
 	}
@@ -89,7 +93,9 @@ func selfRegistrationProfileCreate(ctx context.Context, d *schema.ResourceData,
 			fieldMap := field.(map[string]interface{})
 			customAttributeID := int(fieldMap["custom_attribute_id"].(int))
 
-			_, err := client.CreateSelfRegistrationProfileField(profileID, customAttributeID)
+			_, err := utils.CallWithContext(ctx, func() (interface{}, error) {
+				return client.CreateSelfRegistrationProfileField(profileID, customAttributeID)
+			})
 			if err != nil {
 				return utils.HandleAPIError(ctx, err, utils.ErrorCategoryCreate, "Self-Registration Profile Field", "")
 			}
@@ -116,7 +122,7 @@ func selfRegistrationProfileRead(ctx context.Context, d *schema.ResourceData, m
 		"id": profileID,
 	})
 
-	result, err := client.GetSelfRegistrationProfile(profileID)
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetSelfRegistrationProfile(profileID) })
 	if err != nil {
 		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryRead, "Self-Registration Profile", d.Id())
 	}
@@ -206,7 +212,7 @@ func selfRegistrationProfileUpdate(ctx context.Context, d *schema.ResourceData,
 		"id": profileID,
 	})
 
-	_, err = client.UpdateSelfRegistrationProfile(profileID, profile)
+	_, err = utils.CallWithContext(ctx, func() (interface{}, error) { return client.UpdateSelfRegistrationProfile(profileID, profile) })
 	if err != nil {
 		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryUpdate, "Self-Registration Profile", d.Id())
 	}
@@ -214,7 +220,7 @@ func selfRegistrationProfileUpdate(ctx context.Context, d *schema.ResourceData,
 	// Handle fields if they've changed
 	if d.HasChange("fields") {
 		// Get the current fields from the API
-		result, err := client.GetSelfRegistrationProfile(profileID)
+		result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetSelfRegistrationProfile(profileID) })
 		if err != nil {
 			return utils.HandleAPIError(ctx, err, utils.ErrorCategoryRead, "Self-Registration Profile", d.Id())
 		}
@@ -242,7 +248,9 @@ func selfRegistrationProfileUpdate(ctx context.Context, d *schema.ResourceData,
 
 			// Add fields that don't exist
 			if _, exists := currentFieldMap[customAttributeID]; !exists {
-				_, err := client.CreateSelfRegistrationProfileField(profileID, customAttributeID)
+				_, err := utils.CallWithContext(ctx, func() (interface{}, error) {
+					return client.CreateSelfRegistrationProfileField(profileID, customAttributeID)
+				})
 				if err != nil {
 					return utils.HandleAPIError(ctx, err, utils.ErrorCategoryCreate, "Self-Registration Profile Field", "")
 				}
@@ -252,7 +260,7 @@ func selfRegistrationProfileUpdate(ctx context.Context, d *schema.ResourceData,
 		// Remove fields that are no longer desired
 		for customAttributeID, fieldID := range currentFieldMap {
 			if _, exists := desiredFieldMap[customAttributeID]; !exists {
-				_, err := client.DeleteSelfRegistrationProfileField(profileID, fieldID)
+				_, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.DeleteSelfRegistrationProfileField(profileID, fieldID) })
 				if err != nil {
 					return utils.HandleAPIError(ctx, err, utils.ErrorCategoryDelete, "Self-Registration Profile Field", "")
 				}
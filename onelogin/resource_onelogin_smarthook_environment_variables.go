@@ -9,6 +9,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
 	smarthookenvironmentvariablesschema "github.com/onelogin/terraform-provider-onelogin/ol_schema/smarthook/environment_variable"
+	"github.com/onelogin/terraform-provider-onelogin/utils"
 )
 
 // SmarthookEnvironmentVariables returns a resource with the CRUD methods and Terraform Schema defined
@@ -18,8 +19,11 @@ func SmarthookEnvironmentVariables() *schema.Resource {
 		ReadContext:   environmentVariablesRead,
 		UpdateContext: environmentVariablesUpdate,
 		DeleteContext: environmentVariablesDelete,
-		Importer:      &schema.ResourceImporter{},
-		Schema:        smarthookenvironmentvariablesschema.Schema(),
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(20 * time.Minute),
+		},
+		Importer: importer("environment variable", environmentVariableLookup, objectID, "name"),
+		Schema:   smarthookenvironmentvariablesschema.Schema(),
 	}
 }
 
@@ -31,8 +35,11 @@ func environmentVariablesCreate(ctx context.Context, d *schema.ResourceData, m i
 		"value": d.Get("value"),
 	})
 
-	result, err := client.CreateEnvironmentVariable(envVar)
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.CreateEnvironmentVariable(envVar) })
 	if err != nil {
+		if utils.IsTimeout(ctx, err) {
+			return utils.TimeoutDiagnostics(utils.ErrorCategoryCreate, "Smart Hook Environment Variable", "", err)
+		}
 		return diag.Errorf("error creating environment variable: %v", err)
 	}
 
@@ -52,8 +59,11 @@ func environmentVariablesCreate(ctx context.Context, d *schema.ResourceData, m i
 func environmentVariablesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*onelogin.OneloginSDK)
 
-	result, err := client.GetEnvironmentVariable(d.Id())
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetEnvironmentVariable(d.Id()) })
 	if err != nil {
+		if utils.IsTimeout(ctx, err) {
+			return utils.TimeoutDiagnostics(utils.ErrorCategoryRead, "Smart Hook Environment Variable", d.Id(), err)
+		}
 		log.Printf("[ERROR] There was a problem reading the environment variable: %v", err)
 		return diag.FromErr(err)
 	}
@@ -110,8 +120,11 @@ func environmentVariablesUpdate(ctx context.Context, d *schema.ResourceData, m i
 		"value": d.Get("value"),
 	})
 
-	_, err := client.UpdateEnvironmentVariable(d.Id(), envVar)
+	_, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.UpdateEnvironmentVariable(d.Id(), envVar) })
 	if err != nil {
+		if utils.IsTimeout(ctx, err) {
+			return utils.TimeoutDiagnostics(utils.ErrorCategoryUpdate, "Smart Hook Environment Variable", d.Id(), err)
+		}
 		return diag.Errorf("error updating environment variable: %v", err)
 	}
 
@@ -126,8 +139,11 @@ func environmentVariablesUpdate(ctx context.Context, d *schema.ResourceData, m i
 func environmentVariablesDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*onelogin.OneloginSDK)
 
-	_, err := client.DeleteEnvironmentVariable(d.Id())
+	_, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.DeleteEnvironmentVariable(d.Id()) })
 	if err != nil {
+		if utils.IsTimeout(ctx, err) {
+			return utils.TimeoutDiagnostics(utils.ErrorCategoryDelete, "Smart Hook Environment Variable", d.Id(), err)
+		}
 		log.Printf("[ERROR] There was a problem deleting the environment variable: %v", err)
 		return diag.FromErr(err)
 	}
@@ -2,6 +2,7 @@ package onelogin
 
 import (
 	"context"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -9,6 +10,7 @@ import (
 	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
 	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin/models"
 	smarthooksschema "github.com/onelogin/terraform-provider-onelogin/ol_schema/smarthook"
+	"github.com/onelogin/terraform-provider-onelogin/utils"
 )
 
 // SmartHooks attaches additional configuration and sso schemas and
@@ -21,8 +23,15 @@ func SmartHooks() *schema.Resource {
 		ReadContext:   smartHookRead,
 		UpdateContext: smartHookUpdate,
 		DeleteContext: smartHookDelete,
-		Importer:      &schema.ResourceImporter{},
-		Schema:        smarthookSchema,
+		// Creating or updating a hook deploys its function, and OneLogin does
+		// not answer until the deploy has finished.
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(20 * time.Minute),
+			Create:  schema.DefaultTimeout(40 * time.Minute),
+			Update:  schema.DefaultTimeout(40 * time.Minute),
+		},
+		Importer: &schema.ResourceImporter{},
+		Schema:   smarthookSchema,
 	}
 }
 
@@ -123,8 +132,11 @@ func smartHookCreate(ctx context.Context, d *schema.ResourceData, m interface{})
 	}
 
 	// Create the hook
-	result, err := client.CreateHook(hook)
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.CreateHook(hook) })
 	if err != nil {
+		if utils.IsTimeout(ctx, err) {
+			return utils.TimeoutDiagnostics(utils.ErrorCategoryCreate, "Smart Hook", "", err)
+		}
 		tflog.Error(ctx, "[ERROR] There was a problem creating the smart hook!", map[string]interface{}{"error": err})
 		return diag.FromErr(err)
 	}
@@ -146,8 +158,11 @@ func smartHookCreate(ctx context.Context, d *schema.ResourceData, m interface{})
 // makes the GET request to OneLogin to read a SmartHook with its sub-resources
 func smartHookRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*onelogin.OneloginSDK)
-	result, err := client.GetHook(d.Id(), nil)
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetHook(d.Id(), nil) })
 	if err != nil {
+		if utils.IsTimeout(ctx, err) {
+			return utils.TimeoutDiagnostics(utils.ErrorCategoryRead, "Smart Hook", d.Id(), err)
+		}
 		tflog.Error(ctx, "[ERROR] There was a problem reading the smarthook!", map[string]interface{}{"error": err})
 		return diag.FromErr(err)
 	}
@@ -330,8 +345,11 @@ func smartHookUpdate(ctx context.Context, d *schema.ResourceData, m interface{})
 	}
 
 	// Update the hook
-	_, err := client.UpdateSmartHook(d.Id(), hook)
+	_, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.UpdateSmartHook(d.Id(), hook) })
 	if err != nil {
+		if utils.IsTimeout(ctx, err) {
+			return utils.TimeoutDiagnostics(utils.ErrorCategoryUpdate, "Smart Hook", d.Id(), err)
+		}
 		tflog.Error(ctx, "[ERROR] There was a problem updating the smart hook!", map[string]interface{}{"error": err})
 		return diag.FromErr(err)
 	}
@@ -345,8 +363,11 @@ func smartHookUpdate(ctx context.Context, d *schema.ResourceData, m interface{})
 func smartHookDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*onelogin.OneloginSDK)
 
-	result, err := client.DeleteHook(d.Id())
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.DeleteHook(d.Id()) })
 	if err != nil {
+		if utils.IsTimeout(ctx, err) {
+			return utils.TimeoutDiagnostics(utils.ErrorCategoryDelete, "Smart Hook", d.Id(), err)
+		}
 		tflog.Error(ctx, "[ERROR] There was a problem deleting the smart hook!", map[string]interface{}{"error": err})
 		return diag.FromErr(err)
 	}
@@ -5,7 +5,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
-	ol "github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
+	"github.com/onelogin/terraform-provider-onelogin/apiclient"
 )
 
 // skipIfHookTypeExists skips when the tenant already has a hook of this type.
@@ -22,33 +22,15 @@ import (
 func skipIfHookTypeExists(t *testing.T, hookType string) {
 	t.Helper()
 
-	// The SDK still insists on a subdomain even though every call goes to
-	// ONELOGIN_API_URL, and nothing has configured the provider this early.
-	//
-	// Derived from the URL, with no fallback to a bare ONELOGIN_SUBDOMAIN on
-	// purpose. Given only a subdomain the SDK builds <subdomain>.onelogin.com
-	// -- production -- and this would be the one thing in the run to contact
-	// it, since the provider refuses to configure without a url at all.
-	subdomain, err := subdomainFromURL(os.Getenv("ONELOGIN_API_URL"))
-	if err != nil {
-		t.Fatalf("could not work out the subdomain to check for an existing %s hook: %v", hookType, err)
-	}
-
-	// Put back whatever was there. configProvider sets this from the same URL
-	// moments later, so the value is not in dispute -- but a precheck leaving
-	// the process environment changed makes what a later test sees depend on
-	// whether this one ran, and that is worth not doing.
-	previous, wasSet := os.LookupEnv("ONELOGIN_SUBDOMAIN")
-	t.Cleanup(func() {
-		if wasSet {
-			os.Setenv("ONELOGIN_SUBDOMAIN", previous)
-			return
-		}
-		os.Unsetenv("ONELOGIN_SUBDOMAIN")
-	})
-	os.Setenv("ONELOGIN_SUBDOMAIN", subdomain)
-
-	client, err := ol.NewOneloginSDK()
+	// Built from the same variables the provider block reads, with its own
+	// client, rather than through NewOneloginSDK: that reads ONELOGIN_SUBDOMAIN
+	// as well, and given one it aims at <subdomain>.onelogin.com -- production
+	// -- regardless of ONELOGIN_API_URL.
+	client, err := newOneloginSDK(apiclient.Config{
+		ClientID:     os.Getenv("ONELOGIN_CLIENT_ID"),
+		ClientSecret: os.Getenv("ONELOGIN_CLIENT_SECRET"),
+		URL:          os.Getenv("ONELOGIN_API_URL"),
+	}, os.Getenv("ONELOGIN_SUBDOMAIN"))
 	if err != nil {
 		t.Fatalf("could not build a client to check for an existing %s hook: %v", hookType, err)
 	}
@@ -1,13 +1,16 @@
 package onelogin
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strconv"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
 	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin/models"
+	"github.com/onelogin/terraform-provider-onelogin/utils"
 )
 
 // validCustomAttributePosition rejects a negative position at plan time.
@@ -94,11 +97,14 @@ func userCustomAttributeDefinitionUpdateInput(d *schema.ResourceData) map[string
 // UserCustomAttributes returns a resource with the CRUD methods and Terraform Schema defined
 func UserCustomAttributes() *schema.Resource {
 	return &schema.Resource{
-		Create:   userCustomAttributesCreate,
-		Read:     userCustomAttributesRead,
-		Update:   userCustomAttributesUpdate,
-		Delete:   userCustomAttributesDelete,
-		Importer: &schema.ResourceImporter{},
+		CreateContext: utils.ContextOperation(utils.ErrorCategoryCreate, "User Custom Attribute", userCustomAttributesCreate),
+		ReadContext:   utils.ContextOperation(utils.ErrorCategoryRead, "User Custom Attribute", userCustomAttributesRead),
+		UpdateContext: utils.ContextOperation(utils.ErrorCategoryUpdate, "User Custom Attribute", userCustomAttributesUpdate),
+		DeleteContext: utils.ContextOperation(utils.ErrorCategoryDelete, "User Custom Attribute", userCustomAttributesDelete),
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(20 * time.Minute),
+		},
+		Importer: importer("custom attribute", customAttributeLookup, customAttributeDefinitionID, "shortname"),
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -137,7 +143,7 @@ func UserCustomAttributes() *schema.Resource {
 	}
 }
 
-func userCustomAttributesCreate(d *schema.ResourceData, m interface{}) error {
+func userCustomAttributesCreate(ctx context.Context, d *schema.ResourceData, m interface{}) error {
 	client := m.(*onelogin.OneloginSDK)
 
 	// Check if we're creating a definition or setting a value
@@ -154,7 +160,7 @@ func userCustomAttributesCreate(d *schema.ResourceData, m interface{}) error {
 		userIdInt32 := int32(userIdInt)
 
 		// Get the user first to get its current state
-		user, err := client.GetUserByID(userIdInt, nil)
+		user, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetUserByID(userIdInt, nil) })
 		if err != nil {
 			log.Printf("[ERROR] Error getting user %d: %v", userIdInt, err)
 			return err
@@ -183,7 +189,7 @@ func userCustomAttributesCreate(d *schema.ResourceData, m interface{}) error {
 		}
 
 		// Update the user
-		_, err = client.UpdateUser(userIdInt, userUpdate)
+		_, err = utils.CallWithContext(ctx, func() (interface{}, error) { return client.UpdateUser(userIdInt, userUpdate) })
 		if err != nil {
 			log.Printf("[ERROR] Error setting custom attribute for user %d: %v", userIdInt, err)
 			return err
@@ -191,7 +197,7 @@ func userCustomAttributesCreate(d *schema.ResourceData, m interface{}) error {
 
 		// For user-specific custom attributes, use {user_id}_{shortname} as the ID
 		d.SetId(fmt.Sprintf("%d_%s", userIdInt, shortname))
-		return userCustomAttributesRead(d, m)
+		return userCustomAttributesRead(ctx, d, m)
 	} else {
 		// Otherwise, we're creating a new custom attribute definition,
 		// wrapped in a user_field object as required by API
@@ -204,7 +210,7 @@ func userCustomAttributesCreate(d *schema.ResourceData, m interface{}) error {
 		}
 
 		// Create custom attribute
-		result, err := client.CreateCustomAttributes(payload)
+		result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.CreateCustomAttributes(payload) })
 		if err != nil {
 			log.Printf("[ERROR] Error creating custom attribute: %v", err)
 			return err
@@ -225,11 +231,11 @@ func userCustomAttributesCreate(d *schema.ResourceData, m interface{}) error {
 		// For attribute definitions, prefix the ID with "attr_" to distinguish from user attribute values
 		d.SetId(fmt.Sprintf("attr_%d", attributeID))
 
-		return userCustomAttributesRead(d, m)
+		return userCustomAttributesRead(ctx, d, m)
 	}
 }
 
-func userCustomAttributesRead(d *schema.ResourceData, m interface{}) error {
+func userCustomAttributesRead(ctx context.Context, d *schema.ResourceData, m interface{}) error {
 	client := m.(*onelogin.OneloginSDK)
 
 	// Special case for a new custom attribute definition resource
@@ -250,7 +256,7 @@ func userCustomAttributesRead(d *schema.ResourceData, m interface{}) error {
 		}
 
 		// Get all custom attributes
-		attributes, err := client.GetCustomAttributes()
+		attributes, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetCustomAttributes() })
 		if err != nil {
 			return fmt.Errorf("error retrieving custom attributes: %v", err)
 		}
@@ -304,7 +310,7 @@ func userCustomAttributesRead(d *schema.ResourceData, m interface{}) error {
 
 		shortname := parts[1]
 		// Read the user to get their custom attributes
-		user, err := client.GetUserByID(userId, nil)
+		user, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetUserByID(userId, nil) })
 		if err != nil {
 			return fmt.Errorf("error reading user %d: %v", userId, err)
 		}
@@ -343,7 +349,7 @@ func userCustomAttributesRead(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
-func userCustomAttributesUpdate(d *schema.ResourceData, m interface{}) error {
+func userCustomAttributesUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) error {
 	client := m.(*onelogin.OneloginSDK)
 
 	// Check if this is an attribute definition ID (prefixed with "attr_")
@@ -359,13 +365,15 @@ func userCustomAttributesUpdate(d *schema.ResourceData, m interface{}) error {
 		}
 
 		// Update the custom attribute
-		_, err = client.UpdateCustomAttributes(attrId, userCustomAttributeDefinitionUpdateInput(d))
+		_, err = utils.CallWithContext(ctx, func() (interface{}, error) {
+			return client.UpdateCustomAttributes(attrId, userCustomAttributeDefinitionUpdateInput(d))
+		})
 		if err != nil {
 			log.Printf("[ERROR] Error updating custom attribute %d: %v", attrId, err)
 			return err
 		}
 
-		return userCustomAttributesRead(d, m)
+		return userCustomAttributesRead(ctx, d, m)
 	}
 
 	// Check if this is a user-specific custom attribute
@@ -376,14 +384,14 @@ func userCustomAttributesUpdate(d *schema.ResourceData, m interface{}) error {
 			// This might be a shortname-based ID, just update the state
 			shortname := d.Get("shortname").(string)
 			d.SetId(shortname)
-			return userCustomAttributesRead(d, m)
+			return userCustomAttributesRead(ctx, d, m)
 		}
 
 		shortname := parts[1]
 		userIdInt32 := int32(userId)
 
 		// Get the user to update custom attributes
-		user, err := client.GetUserByID(userId, nil)
+		user, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetUserByID(userId, nil) })
 		if err != nil {
 			return fmt.Errorf("error reading user %d: %v", userId, err)
 		}
@@ -409,23 +417,23 @@ func userCustomAttributesUpdate(d *schema.ResourceData, m interface{}) error {
 		}
 
 		// Update the user
-		_, err = client.UpdateUser(userId, userUpdate)
+		_, err = utils.CallWithContext(ctx, func() (interface{}, error) { return client.UpdateUser(userId, userUpdate) })
 		if err != nil {
 			log.Printf("[ERROR] Error updating custom attribute for user %d: %v", userId, err)
 			return err
 		}
 
-		return userCustomAttributesRead(d, m)
+		return userCustomAttributesRead(ctx, d, m)
 	}
 
 	// Otherwise, just update the shortname value in the state
 	shortname := d.Get("shortname").(string)
 	d.SetId(shortname)
 
-	return userCustomAttributesRead(d, m)
+	return userCustomAttributesRead(ctx, d, m)
 }
 
-func userCustomAttributesDelete(d *schema.ResourceData, m interface{}) error {
+func userCustomAttributesDelete(ctx context.Context, d *schema.ResourceData, m interface{}) error {
 	client := m.(*onelogin.OneloginSDK)
 
 	// Check if this is an attribute definition ID (prefixed with "attr_")
@@ -437,7 +445,7 @@ func userCustomAttributesDelete(d *schema.ResourceData, m interface{}) error {
 		}
 
 		// Delete the custom attribute
-		_, err = client.DeleteCustomAttributes(attrId)
+		_, err = utils.CallWithContext(ctx, func() (interface{}, error) { return client.DeleteCustomAttributes(attrId) })
 		if err != nil {
 			log.Printf("[ERROR] Error deleting custom attribute %d: %v", attrId, err)
 			return err
@@ -461,7 +469,7 @@ func userCustomAttributesDelete(d *schema.ResourceData, m interface{}) error {
 		userIdInt32 := int32(userId)
 
 		// Get the user to update custom attributes
-		user, err := client.GetUserByID(userId, nil)
+		user, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetUserByID(userId, nil) })
 		if err != nil {
 			return fmt.Errorf("error reading user %d: %v", userId, err)
 		}
@@ -485,7 +493,7 @@ func userCustomAttributesDelete(d *schema.ResourceData, m interface{}) error {
 			}
 
 			// Update the user
-			_, err = client.UpdateUser(userId, userUpdate)
+			_, err = utils.CallWithContext(ctx, func() (interface{}, error) { return client.UpdateUser(userId, userUpdate) })
 			if err != nil {
 				log.Printf("[ERROR] Error clearing custom attribute for user %d: %v", userId, err)
 				return err
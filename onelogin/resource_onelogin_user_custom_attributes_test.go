@@ -4,7 +4,6 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -172,7 +171,7 @@ func TestAccUserCustomAttributes_basic(t *testing.T) {
 	// Inline rather than a fixture, so the token is substituted here. Custom
 	// attribute shortnames are unique per tenant, and a definition left behind
 	// by an earlier run would otherwise collide.
-	suffix := acctest.RandStringFromCharSet(8, acctest.CharSetAlphaNum)
+	suffix := newFixtureSuffix()
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:  func() { TestAccPreCheck(t) },
@@ -246,7 +245,7 @@ resource onelogin_user_custom_attributes positioned_attr {
 func TestAccUserCustomAttributesWithUser_basic(t *testing.T) {
 	// This config is inline rather than a fixture, so it needs the token
 	// substituted here: OneLogin enforces unique usernames per tenant.
-	suffix := acctest.RandStringFromCharSet(8, acctest.CharSetAlphaNum)
+	suffix := newFixtureSuffix()
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:  func() { TestAccPreCheck(t) },
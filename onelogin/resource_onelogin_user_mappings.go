@@ -4,12 +4,14 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
+	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin/models"
 	usermappingschema "github.com/onelogin/terraform-provider-onelogin/ol_schema/user_mapping"
 	"github.com/onelogin/terraform-provider-onelogin/utils"
 )
@@ -51,11 +53,62 @@ func UserMappings() *schema.Resource {
 		ReadContext:   userMappingRead,
 		UpdateContext: userMappingUpdate,
 		DeleteContext: userMappingDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(20 * time.Minute),
+		},
+		CustomizeDiff: userMappingCustomizeDiff,
 		Importer:      &schema.ResourceImporter{},
-		Schema:        usermappingschema.Schema(),
+		Schema:        userMappingSchema(),
 	}
 }
 
+// userMappingSchema is the mapping's schema plus validate_catalogue, which is
+// the provider's own and never sent to the API.
+func userMappingSchema() map[string]*schema.Schema {
+	s := usermappingschema.Schema()
+	s["validate_catalogue"] = &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Description: "Check the conditions and actions against the tenant's mapping catalogue when planning, so that a mistyped source, operator, action or value fails the plan rather than the apply. Off unless set",
+	}
+	return s
+}
+
+// userMappingCustomizeDiff checks a mapping's conditions and actions against
+// the mapping catalogue when validate_catalogue is set. It is opt-in because it
+// costs a few requests per mapping on every plan. Whatever is not known yet,
+// such as the ID of a role created in the same run, is left for the API.
+func userMappingCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	client, ok := m.(*onelogin.OneloginSDK)
+	config := d.GetRawConfig()
+	if !ok || config.IsNull() || !config.IsKnown() {
+		return nil
+	}
+	if v := config.GetAttr("validate_catalogue"); v.IsNull() || !v.IsKnown() || v.False() {
+		return nil
+	}
+
+	var conditions []configuredCondition
+	for _, block := range configuredBlocks(config.GetAttr("conditions")) {
+		var c configuredCondition
+		c.source, _ = knownString(block.GetAttr("source"))
+		c.operator, _ = knownString(block.GetAttr("operator"))
+		c.value, _ = knownString(block.GetAttr("value"))
+		conditions = append(conditions, c)
+	}
+
+	var actions []configuredAction
+	for _, block := range configuredBlocks(config.GetAttr("actions")) {
+		a := configuredAction{checkValues: true}
+		a.action, _ = knownString(block.GetAttr("action"))
+		a.values, _ = knownStrings(block.GetAttr("value"))
+		actions = append(actions, a)
+	}
+
+	name, _ := knownString(config.GetAttr("name"))
+	return validateAgainstCatalogue(ctx, fmt.Sprintf("The user mapping %q", name), userMappingCatalogue(client), conditions, actions)
+}
+
 // userMappingCreate creates a new user mapping in OneLogin
 func userMappingCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	userMapping, err := usermappingschema.Inflate(map[string]interface{}{
@@ -75,7 +128,7 @@ func userMappingCreate(ctx context.Context, d *schema.ResourceData, m interface{
 		"name": d.Get("name").(string),
 	})
 
-	result, err := client.CreateUserMapping(userMapping)
+	result, err := createUserMapping(ctx, client, userMapping)
 	if err != nil {
 		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryCreate, "User Mapping", "")
 	}
@@ -106,7 +159,7 @@ func userMappingRead(ctx context.Context, d *schema.ResourceData, m interface{})
 		"id": mid,
 	})
 
-	result, err := client.GetUserMapping(mid32)
+	result, err := getUserMapping(ctx, client, mid32)
 	if err != nil {
 		// A mapping deleted outside Terraform is not an error to report, it is
 		// a resource to forget. Without this a plan fails outright instead of
@@ -199,7 +252,10 @@ func userMappingUpdate(ctx context.Context, d *schema.ResourceData, m interface{
 		"id": mid,
 	})
 
-	_, err = client.UpdateUserMapping(mid32, userMapping)
+	_, err = utils.CallWithContext(ctx, func() (interface{}, error) {
+		_, err := client.UpdateUserMapping(mid32, userMapping)
+		return nil, err
+	})
 	if err != nil {
 		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryUpdate, "User Mapping", d.Id())
 	}
@@ -225,7 +281,8 @@ func userMappingDelete(ctx context.Context, d *schema.ResourceData, m interface{
 	// A 404 is the state the delete was asking for, so it falls through to the
 	// same ending as a successful one. Reporting it would leave the mapping in
 	// state for the next run to fail on again.
-	if err := client.DeleteUserMapping(mid32); err != nil && !utils.IsNotFoundError(err) {
+	_, err := utils.CallWithContext(ctx, func() (interface{}, error) { return nil, client.DeleteUserMapping(mid32) })
+	if err != nil && !utils.IsNotFoundError(err) {
 		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryDelete, "User Mapping", d.Id())
 	}
 
@@ -236,3 +293,32 @@ func userMappingDelete(ctx context.Context, d *schema.ResourceData, m interface{
 	d.SetId("")
 	return nil
 }
+
+// createUserMapping and getUserMapping bound the SDK's mapping calls by ctx.
+// Unlike most SDK calls these return a typed mapping, which CallWithContext
+// would hand back as an interface{}.
+func createUserMapping(ctx context.Context, client *onelogin.OneloginSDK, mapping models.UserMapping) (*models.UserMapping, error) {
+	var result *models.UserMapping
+	_, err := utils.CallWithContext(ctx, func() (interface{}, error) {
+		r, err := client.CreateUserMapping(mapping)
+		result = r
+		return nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func getUserMapping(ctx context.Context, client *onelogin.OneloginSDK, id int32) (*models.UserMapping, error) {
+	var result *models.UserMapping
+	_, err := utils.CallWithContext(ctx, func() (interface{}, error) {
+		r, err := client.GetUserMapping(id)
+		result = r
+		return nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,187 @@
+package onelogin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
+	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin/models"
+	"github.com/onelogin/terraform-provider-onelogin/utils"
+)
+
+// userMappingsOrderID is the ID of onelogin_user_mappings_order. A tenant has
+// one order of mappings, so there is only ever one of these to identify.
+const userMappingsOrderID = "user_mappings"
+
+// UserMappingsOrder returns the onelogin_user_mappings_order resource, which
+// puts a tenant's enabled user mappings in the order given.
+//
+// OneLogin applies mappings from first to last, so their order matters as much
+// as what they do. Setting position on each onelogin_user_mappings resource
+// moves one mapping at a time, and every move shifts the others, so a plan
+// rarely settles; this sets the whole order in one call to the bulk sort
+// endpoint instead, and reads it back whole so that a mapping dragged to a new
+// place in the admin portal shows up as a change.
+func UserMappingsOrder() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: userMappingsOrderApply,
+		ReadContext:   userMappingsOrderRead,
+		UpdateContext: userMappingsOrderApply,
+		DeleteContext: userMappingsOrderDelete,
+		Importer:      &schema.ResourceImporter{},
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(20 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"mapping_ids": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "IDs of every enabled user mapping, in the order OneLogin should apply them",
+			},
+		},
+	}
+}
+
+func userMappingsOrderApply(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*onelogin.OneloginSDK)
+	before, after := d.GetChange("mapping_ids")
+
+	current, err := enabledUserMappingIDs(ctx, client)
+	if err != nil {
+		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryRead, "User Mappings", userMappingsOrderID)
+	}
+	order, err := sortOrder("enabled user mappings", current, intList(before.([]interface{})), intList(after.([]interface{})))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Info(ctx, "[UPDATE] Sorting user mappings", map[string]interface{}{
+		"mapping_ids": order,
+	})
+
+	ids := make([]int32, len(order))
+	for i, id := range order {
+		ids[i] = int32(id)
+	}
+	_, err = utils.CallWithContext(ctx, func() (interface{}, error) {
+		_, err := client.SortUserMappings(ids)
+		return nil, err
+	})
+	if err != nil {
+		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryUpdate, "User Mappings Order", userMappingsOrderID)
+	}
+
+	d.SetId(userMappingsOrderID)
+	return userMappingsOrderRead(ctx, d, m)
+}
+
+// userMappingsOrderRead records the enabled mappings in the order OneLogin
+// has them now, which is how a reorder made outside Terraform is noticed.
+func userMappingsOrderRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*onelogin.OneloginSDK)
+
+	tflog.Info(ctx, "[READ] Reading user mappings order", nil)
+
+	ids, err := enabledUserMappingIDs(ctx, client)
+	if err != nil {
+		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryRead, "User Mappings Order", d.Id())
+	}
+
+	d.SetId(userMappingsOrderID)
+	if err := d.Set("mapping_ids", ids); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+// userMappingsOrderDelete only forgets the order. The mappings stay where
+// they are; there is no order to go back to.
+func userMappingsOrderDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	tflog.Info(ctx, "[DELETE] Removing user mappings order from state; the mappings keep their order", nil)
+	d.SetId("")
+	return nil
+}
+
+// enabledUserMappingIDs lists the enabled mappings' IDs by position. Disabled
+// mappings have no position and are not run, so they have no place in the
+// order.
+func enabledUserMappingIDs(ctx context.Context, client *onelogin.OneloginSDK) ([]int, error) {
+	var mappings []models.UserMapping
+	_, err := utils.CallWithContext(ctx, func() (interface{}, error) {
+		r, err := client.ListUserMappings()
+		mappings = r
+		return nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	enabled := make([]models.UserMapping, 0, len(mappings))
+	for _, mapping := range mappings {
+		if mapping.ID != nil && mapping.Enabled != nil && *mapping.Enabled && mapping.Position != nil {
+			enabled = append(enabled, mapping)
+		}
+	}
+	sort.SliceStable(enabled, func(i, j int) bool { return *enabled[i].Position < *enabled[j].Position })
+
+	ids := make([]int, len(enabled))
+	for i, mapping := range enabled {
+		ids[i] = int(*mapping.ID)
+	}
+	return ids, nil
+}
+
+// sortOrder works out the order to send a bulk sort endpoint, which insists
+// on every one of current, the things noun names, exactly once. want is the
+// order configured and before the one it replaces.
+//
+// An ID that was in before and has been taken out of want is on its way to
+// being destroyed: the order is updated first, while it still exists, so it
+// goes last until it is gone. Any other ID left out is an error naming it,
+// rather than the API's own, which does not: the usual cause is one created
+// since the configuration was written, and the fix is to say where it goes.
+func sortOrder(noun string, current, before, want []int) ([]int, error) {
+	var problems []string
+	seen := map[int]bool{}
+	for _, id := range want {
+		switch {
+		case seen[id]:
+			problems = append(problems, fmt.Sprintf("%d is listed more than once", id))
+		case !containsInt(current, id):
+			problems = append(problems, fmt.Sprintf("%d is not one of the %s", id, noun))
+		}
+		seen[id] = true
+	}
+
+	order := append([]int(nil), want...)
+	for _, id := range current {
+		switch {
+		case seen[id]:
+		case containsInt(before, id):
+			order = append(order, id)
+		default:
+			problems = append(problems, fmt.Sprintf("%d is missing", id))
+		}
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("the order has to list each of the %s exactly once: %s", noun, strings.Join(problems, "; "))
+	}
+	return order, nil
+}
+
+func intList(in []interface{}) []int {
+	out := make([]int, len(in))
+	for i, v := range in {
+		out[i] = v.(int)
+	}
+	return out
+}
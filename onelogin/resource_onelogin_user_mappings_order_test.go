@@ -0,0 +1,202 @@
+package onelogin
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/onelogin/terraform-provider-onelogin/fakeapi"
+)
+
+// TestAccUserMappingsOrder needs a tenant with no enabled mappings of its own:
+// the order has to list every one of them.
+func TestAccUserMappingsOrder(t *testing.T) {
+	name := newFixtureSuffix() + "-mappings-order"
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { TestAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserMappingsOrderConfig(name, "first", "second"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("onelogin_user_mappings_order.all", "mapping_ids.0", "onelogin_user_mappings.first", "id"),
+					resource.TestCheckResourceAttrPair("onelogin_user_mappings_order.all", "mapping_ids.1", "onelogin_user_mappings.second", "id"),
+				),
+			},
+			{
+				Config: testAccUserMappingsOrderConfig(name, "second", "first"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("onelogin_user_mappings_order.all", "mapping_ids.0", "onelogin_user_mappings.second", "id"),
+					resource.TestCheckResourceAttrPair("onelogin_user_mappings_order.all", "mapping_ids.1", "onelogin_user_mappings.first", "id"),
+				),
+			},
+			{
+				ResourceName:      "onelogin_user_mappings_order.all",
+				ImportState:       true,
+				ImportStateId:     userMappingsOrderID,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// testAccUserMappingsOrderConfig orders two mappings, named by the resources
+// given in the order wanted. The mappings leave position out, as they have to
+// for the order resource to own it.
+func testAccUserMappingsOrderConfig(name string, order ...string) string {
+	refs := make([]string, len(order))
+	for i, o := range order {
+		refs[i] = fmt.Sprintf("onelogin_user_mappings.%s.id", o)
+	}
+	return fmt.Sprintf(`
+resource "onelogin_roles" "mapped" {
+  name = %[1]q
+}
+
+resource "onelogin_user_mappings" "first" {
+  name    = "%[1]s first"
+  match   = "all"
+  enabled = true
+
+  conditions {
+    source   = "email"
+    operator = "~"
+    value    = "@first.%[1]s.example.com"
+  }
+
+  actions {
+    action = "add_role"
+    value  = [onelogin_roles.mapped.id]
+  }
+}
+
+resource "onelogin_user_mappings" "second" {
+  name    = "%[1]s second"
+  match   = "all"
+  enabled = true
+
+  conditions {
+    source   = "email"
+    operator = "~"
+    value    = "@second.%[1]s.example.com"
+  }
+
+  actions {
+    action = "add_role"
+    value  = [onelogin_roles.mapped.id]
+  }
+}
+
+resource "onelogin_user_mappings_order" "all" {
+  mapping_ids = [%[2]s]
+}
+`, name, strings.Join(refs, ", "))
+}
+
+func TestUserMappingsOrder(t *testing.T) {
+	srv := fakeapi.NewServer()
+	defer srv.Close()
+	meta := fakeAPIMeta(t, srv)
+	ctx := context.Background()
+
+	seed := func(name string, enabled bool, position int) int {
+		obj := map[string]interface{}{"name": name, "match": "all", "enabled": enabled}
+		if position > 0 {
+			obj["position"] = float64(position)
+		}
+		id, _ := strconv.Atoi(srv.Seed(fakeapi.Mappings, obj))
+		return id
+	}
+	a := seed("a", true, 1)
+	b := seed("b", true, 2)
+	c := seed("c", true, 3)
+	off := seed("off", false, 0)
+
+	r := UserMappingsOrder()
+	order := func(ids ...int) *schema.ResourceData {
+		d := r.Data(nil)
+		d.Set("mapping_ids", ids)
+		return d
+	}
+	got := func(d *schema.ResourceData) string {
+		return fmt.Sprint(d.Get("mapping_ids"))
+	}
+
+	mine := order(c, a, b)
+	if diags := r.CreateContext(ctx, mine, meta); diags.HasError() {
+		t.Fatalf("unexpected error sorting: %v", diags)
+	}
+	if mine.Id() != userMappingsOrderID {
+		t.Fatalf("expected ID %q, got %q", userMappingsOrderID, mine.Id())
+	}
+	if want := fmt.Sprint([]int{c, a, b}); got(mine) != want {
+		t.Fatalf("expected mapping_ids %s, got %s", want, got(mine))
+	}
+
+	// Someone drags the mappings into another order in the admin portal; the
+	// next refresh records it, so the plan proposes putting them back.
+	if diags := r.CreateContext(ctx, order(b, c, a), meta); diags.HasError() {
+		t.Fatalf("unexpected error sorting: %v", diags)
+	}
+	if diags := r.ReadContext(ctx, mine, meta); diags.HasError() {
+		t.Fatalf("unexpected error reading: %v", diags)
+	}
+	if want := fmt.Sprint([]int{b, c, a}); got(mine) != want {
+		t.Fatalf("expected the reorder to be read back as %s, got %s", want, got(mine))
+	}
+
+	// A mapping enabled since has to be given a place.
+	added := seed("added", true, 4)
+	diags := r.UpdateContext(ctx, order(c, a, b), meta)
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, fmt.Sprintf("%d is missing", added)) {
+		t.Fatalf("expected the new mapping to be named as missing, got %v", diags)
+	}
+
+	for name, ids := range map[string][]int{
+		"a disabled mapping": {c, a, b, added, off},
+		"a duplicate":        {c, a, b, added, a},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if diags := r.UpdateContext(ctx, order(ids...), meta); !diags.HasError() {
+				t.Fatal("expected the order to be refused")
+			}
+		})
+	}
+}
+
+func TestSortOrder(t *testing.T) {
+	for name, tt := range map[string]struct {
+		current, before, want []int
+		order                 []int
+		err                   string
+	}{
+		"reordered":               {current: []int{1, 2, 3}, before: []int{1, 2, 3}, want: []int{3, 1, 2}, order: []int{3, 1, 2}},
+		"first apply":             {current: []int{1, 2}, want: []int{2, 1}, order: []int{2, 1}},
+		"one being destroyed":     {current: []int{1, 2, 3}, before: []int{1, 2, 3}, want: []int{3, 1}, order: []int{3, 1, 2}},
+		"one destroyed already":   {current: []int{1, 3}, before: []int{1, 2, 3}, want: []int{3, 1}, order: []int{3, 1}},
+		"one created since":       {current: []int{1, 2, 4}, before: []int{1, 2}, want: []int{2, 1}, err: "4 is missing"},
+		"one that does not exist": {current: []int{1, 2}, want: []int{2, 1, 9}, err: "9 is not one of the things"},
+		"one listed twice":        {current: []int{1, 2}, want: []int{2, 1, 2}, err: "2 is listed more than once"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			order, err := sortOrder("things", tt.current, tt.before, tt.want)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected an error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if fmt.Sprint(order) != fmt.Sprint(tt.order) {
+				t.Fatalf("expected order %v, got %v", tt.order, order)
+			}
+		})
+	}
+}
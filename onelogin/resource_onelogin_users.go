@@ -58,8 +58,11 @@ func Users() *schema.Resource {
 		ReadContext:   userRead,
 		UpdateContext: userUpdate,
 		DeleteContext: userDelete,
-		Importer:      &schema.ResourceImporter{},
-		Schema:        userschema.Schema(),
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(20 * time.Minute),
+		},
+		Importer: importer("user", userLookup, objectID, "username", "email"),
+		Schema:   userschema.Schema(),
 	}
 }
 
@@ -99,7 +102,7 @@ func userCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag
 		"username": d.Get("username").(string),
 	})
 
-	result, err := client.CreateUser(user)
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.CreateUser(user) })
 	if err != nil {
 		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryCreate, "User", "")
 	}
@@ -134,7 +137,7 @@ func userRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.D
 		"id": uid,
 	})
 
-	result, err := client.GetUserByID(uid, &userschema.UserQueryable{})
+	result, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetUserByID(uid, &userschema.UserQueryable{}) })
 	if err != nil {
 		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryRead, "User", d.Id())
 	}
@@ -200,7 +203,7 @@ func userUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag
 
 	// Read current user state from API to get existing custom attributes
 	// This ensures we preserve custom attributes managed by other resources
-	currentUser, err := client.GetUserByID(uid, &userschema.UserQueryable{})
+	currentUser, err := utils.CallWithContext(ctx, func() (interface{}, error) { return client.GetUserByID(uid, &userschema.UserQueryable{}) })
 	if err != nil {
 		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryRead, "User", d.Id())
 	}
@@ -266,7 +269,7 @@ func userUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag
 		"id": uid,
 	})
 
-	_, err = client.UpdateUser(uid, user)
+	_, err = utils.CallWithContext(ctx, func() (interface{}, error) { return client.UpdateUser(uid, user) })
 	if err != nil {
 		return utils.HandleAPIError(ctx, err, utils.ErrorCategoryUpdate, "User", d.Id())
 	}
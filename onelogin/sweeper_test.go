@@ -0,0 +1,653 @@
+package onelogin
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/onelogin/terraform-provider-onelogin/apiclient"
+	"github.com/onelogin/terraform-provider-onelogin/fakeapi"
+)
+
+// TestMain hands over to the SDK, which runs the sweepers below instead of the
+// tests when given -sweep:
+//
+//	ONELOGIN_API_URL=... go test ./onelogin -v -sweep=us
+//
+// -sweep-run=onelogin_roles limits a run to one sweeper and whatever it
+// depends on.
+func TestMain(m *testing.M) {
+	resource.TestMain(m)
+}
+
+// sweepers delete what failed or cancelled acceptance runs leave behind: any
+// object whose name carries fixtureUniqueToken. They are listed in the order
+// they have to run -- each after the sweepers it depends on -- and every
+// resource in Provider().ResourcesMap has one.
+//
+// They go to the API through apiclient rather than the SDK. Each only needs to
+// list one path and delete from it, and doing that the same way everywhere,
+// cursors included, is simpler than a different SDK call and result shape per
+// object type.
+var sweepers = []*resource.Sweeper{
+	{
+		// Before apps and roles: a test role attached to an app that is not
+		// the suite's would otherwise outlive both sweeps on that app.
+		Name: "onelogin_app_role_attachments",
+		F:    sweepAppRoleAttachments,
+	},
+	{
+		Name: "onelogin_app_rules",
+		F:    sweepAppRules,
+	},
+	{
+		// The order is only the positions of the rules it lists, which are
+		// swept above. It is registered so that -sweep-run can name it.
+		Name:         "onelogin_app_rules_order",
+		Dependencies: []string{"onelogin_app_rules"},
+		F:            func(string) error { return nil },
+	},
+	{
+		Name:         "onelogin_apps",
+		Dependencies: []string{"onelogin_app_role_attachments", "onelogin_app_rules"},
+		F:            sweepApps,
+	},
+	{
+		// SAML and OIDC apps live at the same path as every other app and are
+		// swept with them. These are registered so that -sweep-run can name
+		// them.
+		Name:         "onelogin_saml_apps",
+		Dependencies: []string{"onelogin_apps"},
+		F:            func(string) error { return nil },
+	},
+	{
+		Name:         "onelogin_oidc_apps",
+		Dependencies: []string{"onelogin_apps"},
+		F:            func(string) error { return nil },
+	},
+	{
+		// After the apps: OneLogin will not delete a certificate an app still
+		// signs with.
+		Name:         "onelogin_saml_certificate",
+		Dependencies: []string{"onelogin_saml_apps"},
+		F:            sweepSAMLCertificates,
+	},
+	{
+		Name: "onelogin_user_mappings",
+		F:    sweepUserMappings,
+	},
+	{
+		// The order is only the positions of the mappings it lists, so there
+		// is nothing of its own in the tenant to delete. It is registered so
+		// that -sweep-run can name it.
+		Name:         "onelogin_user_mappings_order",
+		Dependencies: []string{"onelogin_user_mappings"},
+		F:            func(string) error { return nil },
+	},
+	{
+		Name: "onelogin_privileges",
+		F:    sweepPrivileges,
+	},
+	{
+		// Profiles name a default role and group, and collect custom
+		// attributes, so they go before all three.
+		Name: "onelogin_self_registration_profiles",
+		F:    sweepSelfRegistrationProfiles,
+	},
+	{
+		Name:         "onelogin_roles",
+		Dependencies: []string{"onelogin_app_role_attachments", "onelogin_privileges", "onelogin_self_registration_profiles"},
+		F:            sweepRoles,
+	},
+	{
+		Name:         "onelogin_users",
+		Dependencies: []string{"onelogin_privileges", "onelogin_roles"},
+		F:            sweepUsers,
+	},
+	{
+		// Role memberships go with the test role, or with the test user or
+		// app on the other end. These are registered so that -sweep-run can
+		// name them.
+		Name:         "onelogin_role_user",
+		Dependencies: []string{"onelogin_roles", "onelogin_users"},
+		F:            func(string) error { return nil },
+	},
+	{
+		Name:         "onelogin_role_admin",
+		Dependencies: []string{"onelogin_roles", "onelogin_users"},
+		F:            func(string) error { return nil },
+	},
+	{
+		Name:         "onelogin_role_app",
+		Dependencies: []string{"onelogin_roles", "onelogin_apps"},
+		F:            func(string) error { return nil },
+	},
+	{
+		Name:         "onelogin_user_custom_attributes",
+		Dependencies: []string{"onelogin_self_registration_profiles", "onelogin_users"},
+		F:            sweepUserCustomAttributes,
+	},
+	{
+		Name:         "onelogin_groups",
+		Dependencies: []string{"onelogin_self_registration_profiles"},
+		F:            sweepGroups,
+	},
+	{
+		Name: "onelogin_smarthooks",
+		F:    sweepSmartHooks,
+	},
+	{
+		Name:         "onelogin_smarthook_environment_variables",
+		Dependencies: []string{"onelogin_smarthooks"},
+		F:            sweepSmartHookEnvironmentVariables,
+	},
+	{
+		Name: "onelogin_auth_servers",
+		F:    sweepAuthServers,
+	},
+	{
+		// Scopes, claims and client apps belong to an auth server and are
+		// deleted with the test one; a client app also goes with its test
+		// app. These are registered so that -sweep-run can name them.
+		Name:         "onelogin_auth_server_scope",
+		Dependencies: []string{"onelogin_auth_servers"},
+		F:            func(string) error { return nil },
+	},
+	{
+		Name:         "onelogin_auth_server_claim",
+		Dependencies: []string{"onelogin_auth_servers"},
+		F:            func(string) error { return nil },
+	},
+	{
+		Name:         "onelogin_auth_server_client_app",
+		Dependencies: []string{"onelogin_apps", "onelogin_auth_servers"},
+		F:            func(string) error { return nil },
+	},
+}
+
+func init() {
+	for _, s := range sweepers {
+		resource.AddTestSweepers(s.Name, s)
+	}
+}
+
+// sweepable reports whether any of values is a string carrying the fixture
+// token. Case is ignored: smart hook variable names must be upper case, so a
+// fixture can only tag one as ACCTEST.
+func sweepable(values ...interface{}) bool {
+	for _, v := range values {
+		if s, ok := v.(string); ok && strings.Contains(strings.ToLower(s), fixtureUniqueToken) {
+			return true
+		}
+	}
+	return false
+}
+
+// sweepClient is the tenant a sweep runs against.
+type sweepClient struct {
+	http *http.Client
+	url  string
+}
+
+// newSweepClient reads the same variables as the acceptance tests. The region
+// given to -sweep is only used when ONELOGIN_API_URL is not set, to pick the
+// regional API host.
+func newSweepClient(region string) (*sweepClient, error) {
+	apiURL := os.Getenv("ONELOGIN_API_URL")
+	if apiURL == "" {
+		if region == "" {
+			return nil, errors.New("ONELOGIN_API_URL or a -sweep region must be given to sweep")
+		}
+		apiURL = fmt.Sprintf("https://api.%s.onelogin.com", region)
+	}
+
+	cfg := apiclient.Config{
+		ClientID:     os.Getenv("ONELOGIN_CLIENT_ID"),
+		ClientSecret: os.Getenv("ONELOGIN_CLIENT_SECRET"),
+		URL:          apiURL,
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	httpClient, err := apiclient.NewHTTPClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &sweepClient{http: httpClient, url: cfg.URL}, nil
+}
+
+// do sends one request. A 404 is not an error: the object went with something
+// swept before it, or another sweep got there first.
+func (c *sweepClient) do(method, path string, query url.Values, body interface{}) (*http.Response, error) {
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			return nil, err
+		}
+	}
+
+	u := c.url + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(method, u, &buf)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	if resp.StatusCode >= 300 && resp.StatusCode != http.StatusNotFound {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s %s: %s", method, path, resp.Status)
+	}
+	return resp, nil
+}
+
+// list returns every object at path, following the After-Cursor header
+// through as many pages as there are.
+func (c *sweepClient) list(path string, query url.Values) ([]map[string]interface{}, error) {
+	var out []map[string]interface{}
+	for {
+		resp, err := c.do(http.MethodGet, path, query, nil)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusNotFound {
+			resp.Body.Close()
+			return out, nil
+		}
+
+		var page []map[string]interface{}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("GET %s: %w", path, err)
+		}
+		out = append(out, page...)
+
+		cursor := resp.Header.Get("After-Cursor")
+		if cursor == "" || len(page) == 0 {
+			return out, nil
+		}
+		// Filters still apply on the next page; the API refuses a cursor sent
+		// with limit or page.
+		next := url.Values{"cursor": {cursor}}
+		for k, v := range query {
+			if k != "cursor" && k != "limit" && k != "page" {
+				next[k] = v
+			}
+		}
+		query = next
+	}
+}
+
+// deleteMatching deletes each of objects that match accepts from path/{id},
+// and reports every failure rather than stopping at the first.
+func (c *sweepClient) deleteMatching(what, path string, objects []map[string]interface{}, match func(map[string]interface{}) bool) error {
+	var errs []error
+	for _, o := range objects {
+		if !match(o) {
+			continue
+		}
+		id := sweepID(o["id"])
+		log.Printf("[INFO] Sweeping %s %s (%v)", what, id, o["name"])
+
+		resp, err := c.do(http.MethodDelete, path+"/"+id, nil, nil)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		resp.Body.Close()
+	}
+	return errors.Join(errs...)
+}
+
+// sweepID formats an object's ID for a path. Most are numbers, which arrive
+// from JSON as float64; privileges and smart hooks have UUIDs.
+func sweepID(v interface{}) string {
+	if f, ok := v.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+func byName(o map[string]interface{}) bool {
+	return sweepable(o["name"])
+}
+
+// sweepAll deletes everything at path whose name carries the token.
+func sweepAll(region, what, path string) error {
+	c, err := newSweepClient(region)
+	if err != nil {
+		return err
+	}
+	objects, err := c.list(path, nil)
+	if err != nil {
+		return err
+	}
+	return c.deleteMatching(what, path, objects, byName)
+}
+
+// sweepAppRoleAttachments detaches test roles from every app, the suite's and
+// anyone else's.
+func sweepAppRoleAttachments(region string) error {
+	c, err := newSweepClient(region)
+	if err != nil {
+		return err
+	}
+	roles, err := c.list("/api/2/roles", nil)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, role := range roles {
+		if !byName(role) {
+			continue
+		}
+		id := sweepID(role["id"])
+		log.Printf("[INFO] Sweeping app attachments of role %s (%v)", id, role["name"])
+
+		resp, err := c.do(http.MethodPut, "/api/2/roles/"+id+"/apps", nil, []interface{}{})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		resp.Body.Close()
+	}
+	return errors.Join(errs...)
+}
+
+// sweepAppRules deletes test rules from every app. Those on test apps would
+// go with the app, but a rule can be put on any app.
+func sweepAppRules(region string) error {
+	c, err := newSweepClient(region)
+	if err != nil {
+		return err
+	}
+	apps, err := c.list("/api/2/apps", nil)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, app := range apps {
+		path := "/api/2/apps/" + sweepID(app["id"]) + "/rules"
+		rules, err := c.list(path, nil)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		errs = append(errs, c.deleteMatching("app rule", path, rules, byName))
+	}
+	return errors.Join(errs...)
+}
+
+func sweepApps(region string) error {
+	return sweepAll(region, "app", "/api/2/apps")
+}
+
+func sweepSAMLCertificates(region string) error {
+	return sweepAll(region, "SAML certificate", "/api/2/certificates")
+}
+
+// sweepUserMappings lists disabled mappings as well as enabled ones: the API
+// returns only enabled mappings unless asked.
+func sweepUserMappings(region string) error {
+	c, err := newSweepClient(region)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, enabled := range []string{"true", "false"} {
+		mappings, err := c.list("/api/2/mappings", url.Values{"enabled": {enabled}})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		errs = append(errs, c.deleteMatching("user mapping", "/api/2/mappings", mappings, byNameOrDryRun))
+	}
+	return errors.Join(errs...)
+}
+
+// byNameOrDryRun also matches the disabled mappings an inline
+// onelogin_user_mapping_dry_run creates, should one outlive its read.
+func byNameOrDryRun(o map[string]interface{}) bool {
+	name, _ := o["name"].(string)
+	return byName(o) || strings.HasPrefix(name, dryRunMappingPrefix)
+}
+
+func sweepPrivileges(region string) error {
+	return sweepAll(region, "privilege", "/api/2/privileges")
+}
+
+func sweepRoles(region string) error {
+	return sweepAll(region, "role", "/api/2/roles")
+}
+
+// sweepUsers matches on username and email, since users carry no name.
+func sweepUsers(region string) error {
+	c, err := newSweepClient(region)
+	if err != nil {
+		return err
+	}
+	users, err := c.list("/api/2/users", nil)
+	if err != nil {
+		return err
+	}
+	return c.deleteMatching("user", "/api/2/users", users, func(u map[string]interface{}) bool {
+		return sweepable(u["username"], u["email"])
+	})
+}
+
+func sweepUserCustomAttributes(region string) error {
+	c, err := newSweepClient(region)
+	if err != nil {
+		return err
+	}
+	attributes, err := c.list("/api/2/users/custom_attributes", nil)
+	if err != nil {
+		return err
+	}
+	return c.deleteMatching("custom attribute", "/api/2/users/custom_attributes", attributes, func(a map[string]interface{}) bool {
+		return sweepable(a["name"], a["shortname"])
+	})
+}
+
+func sweepGroups(region string) error {
+	return sweepAll(region, "group", "/api/2/groups")
+}
+
+func sweepSelfRegistrationProfiles(region string) error {
+	c, err := newSweepClient(region)
+	if err != nil {
+		return err
+	}
+	profiles, err := c.list("/api/2/self_registration_profiles", nil)
+	if err != nil {
+		return err
+	}
+	return c.deleteMatching("self-registration profile", "/api/2/self_registration_profiles", profiles, func(p map[string]interface{}) bool {
+		return sweepable(p["name"], p["url"])
+	})
+}
+
+// sweepSmartHooks is the cautious one. A hook has no name, and a synchronous
+// hook is the only one of its type: deleting the wrong pre-authentication hook
+// takes a tenant's login flow with it. So a hook is only taken when its own
+// code or variables carry the token.
+func sweepSmartHooks(region string) error {
+	c, err := newSweepClient(region)
+	if err != nil {
+		return err
+	}
+	hooks, err := c.list("/api/2/hooks", nil)
+	if err != nil {
+		return err
+	}
+	return c.deleteMatching("smart hook", "/api/2/hooks", hooks, func(h map[string]interface{}) bool {
+		if fn, ok := h["function"].(string); ok {
+			if source, err := base64.StdEncoding.DecodeString(fn); err == nil && sweepable(string(source)) {
+				return true
+			}
+		}
+		vars, _ := h["env_vars"].([]interface{})
+		for _, v := range vars {
+			if env, ok := v.(map[string]interface{}); ok && sweepable(env["name"]) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func sweepSmartHookEnvironmentVariables(region string) error {
+	return sweepAll(region, "smart hook environment variable", "/api/2/hooks/envs")
+}
+
+func sweepAuthServers(region string) error {
+	return sweepAll(region, "auth server", "/api/2/api_authorizations")
+}
+
+// TestSweepersCoverEveryResource keeps the list above complete and in an order
+// that can actually run.
+func TestSweepersCoverEveryResource(t *testing.T) {
+	registered := map[string]bool{}
+	for _, s := range sweepers {
+		for _, dep := range s.Dependencies {
+			if !registered[dep] {
+				t.Errorf("%s depends on %s, which is not listed before it", s.Name, dep)
+			}
+		}
+		registered[s.Name] = true
+	}
+
+	for name := range Provider().ResourcesMap {
+		if !registered[name] {
+			t.Errorf("%s has no sweeper", name)
+		}
+	}
+}
+
+// TestSweepersAgainstFakeAPI runs every sweeper, in order, against a tenant
+// holding the suite's leftovers alongside objects that are not the suite's,
+// and checks that only the former are gone.
+func TestSweepersAgainstFakeAPI(t *testing.T) {
+	srv := fakeapi.NewServer()
+	defer srv.Close()
+
+	t.Setenv("ONELOGIN_CLIENT_ID", fakeapi.ClientID)
+	t.Setenv("ONELOGIN_CLIENT_SECRET", fakeapi.ClientSecret)
+	t.Setenv("ONELOGIN_API_URL", srv.URL)
+
+	token := newFixtureSuffix()
+	num := func(id string) float64 {
+		n, _ := strconv.ParseFloat(id, 64)
+		return n
+	}
+
+	testRole := srv.Seed(fakeapi.Roles, map[string]interface{}{"name": "role_1_" + token})
+	keptRole := srv.Seed(fakeapi.Roles, map[string]interface{}{"name": "Production"})
+
+	// A test role on somebody else's app, and a test rule on it.
+	keptApp := srv.Seed(fakeapi.Apps, map[string]interface{}{"name": "Payroll", "role_ids": []interface{}{num(testRole), num(keptRole)}})
+	srv.Seed(fakeapi.AppRules, map[string]interface{}{"app_id": keptApp, "name": "first rule " + token, "position": 1})
+	srv.Seed(fakeapi.AppRules, map[string]interface{}{"app_id": keptApp, "name": "Keep", "position": 2})
+	srv.Seed(fakeapi.Apps, map[string]interface{}{"name": "Upgrade Probe " + token})
+
+	// A test certificate a test app still signs with, which can only go once
+	// the app has.
+	testCert := srv.Seed(fakeapi.Certificates, map[string]interface{}{"name": token + "-saml-cert-signing"})
+	srv.Seed(fakeapi.Apps, map[string]interface{}{"name": "Signing Probe " + token, "configuration": map[string]interface{}{"certificate_id": num(testCert)}})
+	srv.Seed(fakeapi.Certificates, map[string]interface{}{"name": "Production signing"})
+
+	for i := 0; i < 60; i++ {
+		// More than one page of them.
+		srv.Seed(fakeapi.Users, map[string]interface{}{"username": fmt.Sprintf("testy.%d.%s", i, token), "email": fmt.Sprintf("testy.%d.%s@example.com", i, token)})
+	}
+	srv.Seed(fakeapi.Users, map[string]interface{}{"username": "jane", "email": "jane@example.com"})
+
+	srv.Seed(fakeapi.Mappings, map[string]interface{}{"name": "Mapping Example Primary " + token, "enabled": true, "position": 1})
+	srv.Seed(fakeapi.Mappings, map[string]interface{}{"name": "Mapping Example Secondary " + token, "enabled": false})
+	srv.Seed(fakeapi.Mappings, map[string]interface{}{"name": "Everyone", "enabled": true, "position": 2})
+
+	srv.Seed(fakeapi.Privileges, map[string]interface{}{"name": "super admin " + token})
+	srv.Seed(fakeapi.Privileges, map[string]interface{}{"name": "Helpdesk"})
+
+	srv.Seed(fakeapi.SmartHookEnvVars, map[string]interface{}{"name": "SOME_KEY_" + strings.ToUpper(token)})
+	srv.Seed(fakeapi.SmartHookEnvVars, map[string]interface{}{"name": "SOME_KEY"})
+	srv.Seed(fakeapi.SmartHooks, map[string]interface{}{
+		"type":     "user-migration",
+		"function": base64.StdEncoding.EncodeToString([]byte("// " + token + "\nexports.handler = async context => context;")),
+		"env_vars": []interface{}{},
+	})
+	srv.Seed(fakeapi.SmartHooks, map[string]interface{}{
+		"type":     "pre-authentication",
+		"function": base64.StdEncoding.EncodeToString([]byte("exports.handler = async context => context;")),
+		"env_vars": []interface{}{},
+	})
+
+	srv.Seed(fakeapi.AuthServers, map[string]interface{}{"name": "test " + token})
+	srv.Seed(fakeapi.CustomAttributes, map[string]interface{}{"name": "Test Attribute", "shortname": "test_attr_" + token})
+	srv.Seed(fakeapi.Groups, map[string]interface{}{"name": "Test Group " + token})
+	srv.Seed(fakeapi.SelfRegistrationProfiles, map[string]interface{}{"name": "Test Profile", "url": "profile-" + token})
+
+	for _, s := range sweepers {
+		if err := s.F(""); err != nil {
+			t.Fatalf("%s: %v", s.Name, err)
+		}
+	}
+
+	want := map[fakeapi.Kind][]string{
+		fakeapi.Roles:                    {"Production"},
+		fakeapi.Apps:                     {"Payroll"},
+		fakeapi.AppRules:                 {"Keep"},
+		fakeapi.Users:                    {"jane"},
+		fakeapi.Mappings:                 {"Everyone"},
+		fakeapi.Privileges:               {"Helpdesk"},
+		fakeapi.SmartHookEnvVars:         {"SOME_KEY"},
+		fakeapi.SmartHooks:               {"pre-authentication"},
+		fakeapi.AuthServers:              {},
+		fakeapi.CustomAttributes:         {},
+		fakeapi.Groups:                   {},
+		fakeapi.SelfRegistrationProfiles: {},
+		fakeapi.Certificates:             {"Production signing"},
+	}
+	for kind, names := range want {
+		got := []string{}
+		for _, o := range srv.Objects(kind) {
+			switch kind {
+			case fakeapi.Users:
+				got = append(got, fmt.Sprint(o["username"]))
+			case fakeapi.SmartHooks:
+				got = append(got, fmt.Sprint(o["type"]))
+			default:
+				got = append(got, fmt.Sprint(o["name"]))
+			}
+		}
+		if fmt.Sprint(got) != fmt.Sprint(names) {
+			t.Errorf("%s: expected %v left, got %v", kind, names, got)
+		}
+	}
+
+	for _, app := range srv.Objects(fakeapi.Apps) {
+		if got := fmt.Sprint(app["role_ids"]); got != fmt.Sprintf("[%s]", keptRole) {
+			t.Errorf("expected only the kept role left on %v, got %s", app["name"], got)
+		}
+	}
+}
@@ -20,20 +20,28 @@ import (
 //	Username must be unique within <tenant>
 //
 // A token keeps the examples readable while letting the suite be re-run.
+//
+// The token is kept at the front of the value it is replaced with, so whatever
+// a failed run leaves behind can still be found by it; see sweepers.
 const fixtureUniqueToken = "acctest"
 
+// newFixtureSuffix returns a value to replace fixtureUniqueToken with.
+func newFixtureSuffix() string {
+	return fixtureUniqueToken + acctest.RandStringFromCharSet(8, acctest.CharSetAlphaNum)
+}
+
 // GetFixture returns the HCL example to be used in an acceptance test, with
 // fixtureUniqueToken replaced by a value unique to this run.
 func GetFixture(name string, t *testing.T) string {
 	t.Helper()
-	return getFixtureWithSuffix(name, acctest.RandStringFromCharSet(8, acctest.CharSetAlphaNum), t)
+	return getFixtureWithSuffix(name, newFixtureSuffix(), t)
 }
 
 // GetFixturesWithSuffix returns several fixtures sharing one suffix, and the
 // suffix itself, for tests that assert on a value the token appears in.
 func GetFixturesWithSuffix(names []string, t *testing.T) ([]string, string) {
 	t.Helper()
-	suffix := acctest.RandStringFromCharSet(8, acctest.CharSetAlphaNum)
+	suffix := newFixtureSuffix()
 
 	out := make([]string, 0, len(names))
 	for _, name := range names {
@@ -48,7 +56,7 @@ func GetFixturesWithSuffix(names []string, t *testing.T) ([]string, string) {
 // resource rather than updating it.
 func GetFixtures(names []string, t *testing.T) []string {
 	t.Helper()
-	suffix := acctest.RandStringFromCharSet(8, acctest.CharSetAlphaNum)
+	suffix := newFixtureSuffix()
 
 	out := make([]string, 0, len(names))
 	for _, name := range names {
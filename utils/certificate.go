@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Certificate is what the provider reports about an X.509 certificate.
+type Certificate struct {
+	// PEM is the certificate PEM encoded, whichever way it was given.
+	PEM string
+	// Fingerprint is the SHA-1 fingerprint as OneLogin shows it, upper case
+	// hex pairs separated by colons. SHA256Fingerprint is the same for
+	// SHA-256.
+	Fingerprint       string
+	SHA256Fingerprint string
+	NotBefore         time.Time
+	NotAfter          time.Time
+	// KeySize is the RSA key's size in bits, 0 for any other kind of key.
+	KeySize int
+}
+
+// ParseCertificate reads a certificate given either as PEM or as the bare
+// base64 of its DER encoding, the way SAML metadata carries one. Whitespace
+// in the base64 is ignored.
+func ParseCertificate(s string) (*Certificate, error) {
+	var der []byte
+	if block, _ := pem.Decode([]byte(s)); block != nil {
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("expected a CERTIFICATE PEM block, got %s", block.Type)
+		}
+		der = block.Bytes
+	} else {
+		var err error
+		der, err = base64.StdEncoding.DecodeString(strings.Join(strings.Fields(s), ""))
+		if err != nil {
+			return nil, fmt.Errorf("certificate is neither PEM nor base64: %v", err)
+		}
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate: %v", err)
+	}
+	sha1Sum := sha1.Sum(der)
+	sha256Sum := sha256.Sum256(der)
+	out := &Certificate{
+		PEM:               string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		Fingerprint:       fingerprint(sha1Sum[:]),
+		SHA256Fingerprint: fingerprint(sha256Sum[:]),
+		NotBefore:         cert.NotBefore.UTC(),
+		NotAfter:          cert.NotAfter.UTC(),
+	}
+	if key, ok := cert.PublicKey.(*rsa.PublicKey); ok {
+		out.KeySize = key.N.BitLen()
+	}
+	return out, nil
+}
+
+func fingerprint(sum []byte) string {
+	pairs := make([]string, len(sum))
+	for i, b := range sum {
+		pairs[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(pairs, ":")
+}
@@ -1,67 +1,32 @@
 package utils
 
 import (
-	"context"
-	"encoding/json"
-	"fmt"
-
-	"github.com/hashicorp/terraform-plugin-log/tflog"
-	"github.com/onelogin/onelogin-go-sdk/v4/pkg/onelogin"
+	"os"
+	"strings"
 )
 
-// AddRequestResponseLogging adds request/response logging to the SDK client
-func AddRequestResponseLogging(ctx context.Context, client *onelogin.OneloginSDK) {
-	// Just log that debug mode is enabled
-	tflog.Info(ctx, "[DEBUG] Debug logging enabled for OneLogin SDK")
+// debugLogLevelVars are the variables that can ask for the provider's debug
+// output: Terraform's own, the one for providers only, this provider's, and
+// the one for its api subsystem alone.
+var debugLogLevelVars = []string{
+	"TF_LOG",
+	"TF_LOG_PROVIDER",
+	"TF_LOG_PROVIDER_ONELOGIN",
+	"TF_LOG_PROVIDER_ONELOGIN_API",
 }
 
-// LogAPIRequest logs an API request for debugging purposes
-func LogAPIRequest(ctx context.Context, method string, url string, body interface{}) {
-	// Format the body if it's provided
-	var bodyJSON string
-	if body != nil {
-		bodyBytes, err := json.MarshalIndent(body, "", "  ")
-		if err == nil {
-			bodyJSON = string(bodyBytes)
-		} else {
-			bodyJSON = fmt.Sprintf("%v", body)
+// DebugLoggingEnabled reports whether the log level asked for is DEBUG or
+// TRACE, the levels at which API requests and responses are worth logging.
+//
+// Logging them costs a copy of every body, and tflog filtering the entries out
+// afterwards would not save that, so the provider checks here before
+// installing the logging at all. TF_LOG=JSON is TRACE written as JSON.
+func DebugLoggingEnabled() bool {
+	for _, name := range debugLogLevelVars {
+		switch strings.ToUpper(strings.TrimSpace(os.Getenv(name))) {
+		case "DEBUG", "TRACE", "JSON":
+			return true
 		}
 	}
-
-	tflog.Info(ctx, "[DEBUG] API Request", map[string]interface{}{
-		"method": method,
-		"url":    url,
-		"body":   bodyJSON,
-	})
-}
-
-// LogAPIResponse logs an API response for debugging purposes
-func LogAPIResponse(ctx context.Context, statusCode int, response interface{}, err error) {
-	if err != nil {
-		tflog.Error(ctx, "[DEBUG] API Error", map[string]interface{}{
-			"error": err.Error(),
-		})
-		return
-	}
-
-	// Format the response
-	var responseJSON string
-	if response != nil {
-		respBytes, jsonErr := json.MarshalIndent(response, "", "  ")
-		if jsonErr == nil {
-			responseJSON = string(respBytes)
-		} else {
-			responseJSON = fmt.Sprintf("%v", response)
-		}
-	}
-
-	logFunc := tflog.Info
-	if statusCode >= 400 {
-		logFunc = tflog.Error
-	}
-
-	logFunc(ctx, "[DEBUG] API Response", map[string]interface{}{
-		"status_code": statusCode,
-		"body":        responseJSON,
-	})
+	return false
 }
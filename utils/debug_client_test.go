@@ -0,0 +1,29 @@
+package utils
+
+import "testing"
+
+func TestDebugLoggingEnabled(t *testing.T) {
+	tests := map[string]struct {
+		env  map[string]string
+		want bool
+	}{
+		"nothing set":             {env: map[string]string{}, want: false},
+		"TF_LOG=INFO":             {env: map[string]string{"TF_LOG": "INFO"}, want: false},
+		"TF_LOG=debug":            {env: map[string]string{"TF_LOG": "debug"}, want: true},
+		"TF_LOG=TRACE":            {env: map[string]string{"TF_LOG": "TRACE"}, want: true},
+		"TF_LOG=JSON":             {env: map[string]string{"TF_LOG": "JSON"}, want: true},
+		"providers only":          {env: map[string]string{"TF_LOG_PROVIDER": "DEBUG"}, want: true},
+		"the api subsystem alone": {env: map[string]string{"TF_LOG_PROVIDER_ONELOGIN_API": "TRACE"}, want: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			for _, v := range debugLogLevelVars {
+				t.Setenv(v, tt.env[v])
+			}
+			if got := DebugLoggingEnabled(); got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
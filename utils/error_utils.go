@@ -48,6 +48,7 @@ func LogAndReturnError(
 	err error,
 ) diag.Diagnostics {
 	message := FormatError(category, resourceType, operation, id, err)
+	timedOut := IsTimeout(ctx, err)
 
 	data := map[string]interface{}{
 		"resource_type": resourceType,
@@ -67,6 +68,9 @@ func LogAndReturnError(
 		tflog.Error(ctx, message, data)
 	}
 
+	if timedOut {
+		return TimeoutDiagnostics(category, resourceType, id, err)
+	}
 	return diag.FromErr(err)
 }
 
@@ -45,7 +45,9 @@ func StandardDeleteFunc(
 		"id": id,
 	})
 
-	if _, err = deleteFunc(id); err != nil {
+	// Through CallWithContext so the delete timeout applies whether or not
+	// deleteFunc's SDK call takes a context.
+	if _, err = CallWithContext(ctx, func() (interface{}, error) { return deleteFunc(id) }); err != nil {
 		// A 404 is the state the delete was asking for. Something else got
 		// there first -- another practitioner, a cascade, a retried request
 		// whose first attempt landed -- and reporting that as a failure leaves
@@ -63,6 +65,9 @@ func StandardDeleteFunc(
 			"id":    id,
 			"error": err,
 		})
+		if IsTimeout(ctx, err) {
+			return TimeoutDiagnostics(ErrorCategoryDelete, resourceType, id, err)
+		}
 		return diag.FromErr(err)
 	}
 
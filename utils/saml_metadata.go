@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// samlBindingPrefix is what every SAML 2.0 binding URI starts with.
+// SAMLMetadata keys its endpoints by what follows, e.g. HTTP-Redirect.
+const samlBindingPrefix = "urn:oasis:names:tc:SAML:2.0:bindings:"
+
+// SAMLMetadata is what the provider reports about an identity provider's
+// SAML 2.0 metadata.
+type SAMLMetadata struct {
+	EntityID string
+	// SSOURLs are the single sign-on endpoints by binding, e.g. HTTP-POST.
+	SSOURLs map[string]string
+	// SLOURL is the first single logout endpoint listed, if any.
+	SLOURL string
+	// Certificate is the first certificate listed for signing.
+	Certificate *Certificate
+}
+
+// samlEntityDescriptor is the part of an EntityDescriptor ParseSAMLMetadata
+// reads. Elements are matched by local name, whatever prefix the document
+// gives the metadata and signature namespaces.
+type samlEntityDescriptor struct {
+	XMLName  xml.Name `xml:"EntityDescriptor"`
+	EntityID string   `xml:"entityID,attr"`
+	IDP      *struct {
+		Keys []struct {
+			Use         string `xml:"use,attr"`
+			Certificate string `xml:"KeyInfo>X509Data>X509Certificate"`
+		} `xml:"KeyDescriptor"`
+		SSO []samlEndpoint `xml:"SingleSignOnService"`
+		SLO []samlEndpoint `xml:"SingleLogoutService"`
+	} `xml:"IDPSSODescriptor"`
+}
+
+type samlEndpoint struct {
+	Binding  string `xml:"Binding,attr"`
+	Location string `xml:"Location,attr"`
+}
+
+// ParseSAMLMetadata reads an identity provider's metadata document. It is an
+// error for the document not to describe an identity provider, or for that
+// identity provider to have no usable signing certificate.
+func ParseSAMLMetadata(data []byte) (*SAMLMetadata, error) {
+	var doc samlEntityDescriptor
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid SAML metadata: %v", err)
+	}
+	if doc.EntityID == "" {
+		return nil, fmt.Errorf("invalid SAML metadata: the EntityDescriptor has no entityID")
+	}
+	if doc.IDP == nil {
+		return nil, fmt.Errorf("SAML metadata for %s does not describe an identity provider", doc.EntityID)
+	}
+
+	out := &SAMLMetadata{EntityID: doc.EntityID, SSOURLs: map[string]string{}}
+	for _, sso := range doc.IDP.SSO {
+		binding := strings.TrimPrefix(sso.Binding, samlBindingPrefix)
+		if _, ok := out.SSOURLs[binding]; !ok {
+			out.SSOURLs[binding] = sso.Location
+		}
+	}
+	if len(doc.IDP.SLO) > 0 {
+		out.SLOURL = doc.IDP.SLO[0].Location
+	}
+
+	// A key with no use is for signing and encryption both.
+	for _, key := range doc.IDP.Keys {
+		if key.Use != "" && key.Use != "signing" {
+			continue
+		}
+		cert, err := ParseCertificate(key.Certificate)
+		if err != nil {
+			return nil, fmt.Errorf("SAML metadata for %s: reading the signing certificate: %v", doc.EntityID, err)
+		}
+		out.Certificate = cert
+		return out, nil
+	}
+	return nil, fmt.Errorf("SAML metadata for %s has no signing certificate", doc.EntityID)
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testSAMLMetadata is metadata shaped the way OneLogin serves it, signing with
+// cert.
+func testSAMLMetadata(cert string) string {
+	return fmt.Sprintf(`<?xml version="1.0"?>
+<EntityDescriptor xmlns="urn:oasis:names:tc:SAML:2.0:metadata" entityID="https://app.onelogin.com/saml/metadata/42">
+  <IDPSSODescriptor xmlns:ds="http://www.w3.org/2000/09/xmldsig#" protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+    <KeyDescriptor use="encryption">
+      <ds:KeyInfo><ds:X509Data><ds:X509Certificate>bm90IHVzZWQ=</ds:X509Certificate></ds:X509Data></ds:KeyInfo>
+    </KeyDescriptor>
+    <KeyDescriptor use="signing">
+      <ds:KeyInfo>
+        <ds:X509Data>
+          <ds:X509Certificate>%s</ds:X509Certificate>
+        </ds:X509Data>
+      </ds:KeyInfo>
+    </KeyDescriptor>
+    <SingleLogoutService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://example.onelogin.com/trust/saml2/http-redirect/slo/42"/>
+    <NameIDFormat>urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress</NameIDFormat>
+    <SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://example.onelogin.com/trust/saml2/http-redirect/sso/42"/>
+    <SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="https://example.onelogin.com/trust/saml2/http-post/sso/42"/>
+    <SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:SOAP" Location="https://example.onelogin.com/trust/saml2/soap/sso/42"/>
+  </IDPSSODescriptor>
+</EntityDescriptor>`, cert)
+}
+
+func TestParseSAMLMetadata(t *testing.T) {
+	notAfter := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	der := testCertificateDER(t, notAfter)
+
+	md, err := ParseSAMLMetadata([]byte(testSAMLMetadata(base64.StdEncoding.EncodeToString(der))))
+	assert.NoError(t, err)
+	assert.Equal(t, "https://app.onelogin.com/saml/metadata/42", md.EntityID)
+	assert.Equal(t, map[string]string{
+		"HTTP-Redirect": "https://example.onelogin.com/trust/saml2/http-redirect/sso/42",
+		"HTTP-POST":     "https://example.onelogin.com/trust/saml2/http-post/sso/42",
+		"SOAP":          "https://example.onelogin.com/trust/saml2/soap/sso/42",
+	}, md.SSOURLs)
+	assert.Equal(t, "https://example.onelogin.com/trust/saml2/http-redirect/slo/42", md.SLOURL)
+	assert.Equal(t, notAfter, md.Certificate.NotAfter)
+
+	for name, doc := range map[string]string{
+		"not XML":              "<EntityDescriptor",
+		"not metadata":         `<html><body>Not Found</body></html>`,
+		"no identity provider": `<EntityDescriptor xmlns="urn:oasis:names:tc:SAML:2.0:metadata" entityID="sp"><SPSSODescriptor/></EntityDescriptor>`,
+		"bad certificate":      testSAMLMetadata("bm90IGEgY2VydGlmaWNhdGU="),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseSAMLMetadata([]byte(doc))
+			assert.Error(t, err)
+		})
+	}
+}
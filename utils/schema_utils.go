@@ -120,3 +120,29 @@ func MergeSchemas(schemas ...map[string]*schema.Schema) map[string]*schema.Schem
 
 	return result
 }
+
+// ComputedSchema returns a copy of s, nested blocks included, with every field
+// made computed-only, for a data source that reads what a resource manages.
+// Whatever only applies to configuration -- defaults, validation, conflicts,
+// diff suppression -- is dropped. Set functions are kept, so that a set is
+// keyed the way the resource keys it.
+func ComputedSchema(s map[string]*schema.Schema) map[string]*schema.Schema {
+	out := make(map[string]*schema.Schema, len(s))
+	for k, v := range s {
+		c := &schema.Schema{
+			Type:        v.Type,
+			Computed:    true,
+			Sensitive:   v.Sensitive,
+			Description: v.Description,
+			Set:         v.Set,
+		}
+		switch elem := v.Elem.(type) {
+		case *schema.Resource:
+			c.Elem = &schema.Resource{Schema: ComputedSchema(elem.Schema)}
+		case *schema.Schema:
+			c.Elem = &schema.Schema{Type: elem.Type}
+		}
+		out[k] = c
+	}
+	return out
+}
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputedSchema(t *testing.T) {
+	hash := func(v interface{}) int { return 1 }
+	in := map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Required: true,
+			ValidateFunc: func(interface{}, string) ([]string, []error) {
+				return nil, nil
+			},
+		},
+		"visible": {Type: schema.TypeBool, Optional: true, Default: true},
+		"secret":  {Type: schema.TypeString, Optional: true, Sensitive: true},
+		"parameters": {
+			Type:     schema.TypeSet,
+			Optional: true,
+			Set:      hash,
+			Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+				"param_key_name": {Type: schema.TypeString, Required: true},
+				"values":         {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			}},
+		},
+	}
+
+	out := ComputedSchema(in)
+
+	assert.NoError(t, schema.InternalMap(out).InternalValidate(nil))
+	for _, k := range []string{"name", "visible", "secret", "parameters"} {
+		assert.True(t, out[k].Computed, k)
+		assert.False(t, out[k].Optional || out[k].Required, k)
+	}
+	assert.Nil(t, out["name"].ValidateFunc)
+	assert.Nil(t, out["visible"].Default)
+	assert.True(t, out["secret"].Sensitive)
+	assert.NotNil(t, out["parameters"].Set)
+
+	nested := out["parameters"].Elem.(*schema.Resource).Schema
+	assert.True(t, nested["param_key_name"].Computed)
+	assert.False(t, nested["param_key_name"].Required)
+	assert.Equal(t, schema.TypeString, nested["values"].Elem.(*schema.Schema).Type)
+
+	// The resource's own schema is left as it was.
+	assert.True(t, in["name"].Required)
+	assert.True(t, in["parameters"].Elem.(*schema.Resource).Schema["param_key_name"].Required)
+}
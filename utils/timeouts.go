@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// CallWithContext runs call, an SDK method that takes no context, and stops
+// waiting for it once ctx is done.
+//
+// Most SDK methods send their requests with a context of their own, so the
+// deadline the SDK gives a resource's create, read, update or delete never
+// reaches them. Without this a timeouts block would bound only the handful of
+// calls that have a WithContext variant, which should be used instead where
+// they exist. The abandoned request runs on in the background until it
+// finishes or the provider's own timeout ends it; its result is discarded.
+// For a create that means OneLogin may still make the object, which Terraform
+// then never records, so TimeoutDiagnostics says as much for a create.
+func CallWithContext(ctx context.Context, call func() (interface{}, error)) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		value interface{}
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		v, err := call()
+		done <- result{v, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// IsTimeout reports whether err, returned by a call made under ctx, means the
+// operation's deadline ran out. ctx is checked as well as err because the SDK
+// does not always wrap the error it was given.
+func IsTimeout(ctx context.Context, err error) bool {
+	if err == nil {
+		return false
+	}
+	return errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded)
+}
+
+// timeoutKeys maps an error category to the key of the timeouts block that
+// bounds it. Import has none.
+var timeoutKeys = map[ErrorCategory]string{
+	ErrorCategoryCreate: "create",
+	ErrorCategoryRead:   "read",
+	ErrorCategoryUpdate: "update",
+	ErrorCategoryDelete: "delete",
+}
+
+// TimeoutDiagnostics reports an operation that ran out of time, naming the
+// operation and the timeouts block key that would give it more.
+//
+// A bare "context deadline exceeded" says nothing about which of a run's
+// hundreds of calls it came from, nor that the limit is configurable.
+func TimeoutDiagnostics(category ErrorCategory, resourceType string, id string, err error) diag.Diagnostics {
+	key, ok := timeoutKeys[category]
+	verb := strings.ToLower(string(category))
+	if ok {
+		verb = strings.TrimSuffix(key, "e") + "ing"
+	}
+
+	summary := fmt.Sprintf("Timed out %s %s", verb, resourceType)
+	if id != "" {
+		summary += fmt.Sprintf(" (ID: %s)", id)
+	}
+
+	detail := fmt.Sprintf("OneLogin had not finished when the %s timeout ran out: %v", verb, err)
+	if ok {
+		detail = fmt.Sprintf("OneLogin had not finished when the %s timeout ran out. It can be raised with a timeouts block:\n\n  timeouts {\n    %s = \"30m\"\n  }\n\nError: %v", key, key, err)
+	}
+	// A request Terraform stopped waiting for may still have been carried
+	// out, and a create carried out but never recorded turns into a
+	// duplicate, or a name conflict, on the next apply.
+	if category == ErrorCategoryCreate {
+		detail += fmt.Sprintf("\n\nThe request may still have reached OneLogin and created the %s, which Terraform has not recorded in state. Check OneLogin before applying again, and if it exists, import it with terraform import rather than letting the next apply create another.", resourceType)
+	}
+
+	return diag.Diagnostics{{Severity: diag.Error, Summary: summary, Detail: detail}}
+}
+
+// ContextOperation adapts a create, read, update or delete function written
+// for the plugin SDK's plain signature, which returns an error, to the
+// context-aware one. Only the context-aware signatures are given a deadline
+// from the timeouts block. Errors other than a timeout are reported as the
+// SDK reports them for the plain signature.
+func ContextOperation(category ErrorCategory, resourceType string, op func(context.Context, *schema.ResourceData, interface{}) error) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		if err := op(ctx, d, m); err != nil {
+			if IsTimeout(ctx, err) {
+				return TimeoutDiagnostics(category, resourceType, d.Id(), err)
+			}
+			return diag.FromErr(err)
+		}
+		return nil
+	}
+}
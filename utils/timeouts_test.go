@@ -0,0 +1,194 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// TestCallWithContext covers a call that takes no context: it has to stop
+// being waited on when the deadline the SDK gave the operation runs out, or a
+// timeouts block bounds nothing.
+func TestCallWithContext(t *testing.T) {
+	t.Run("returns the call's result", func(t *testing.T) {
+		v, err := CallWithContext(context.Background(), func() (interface{}, error) {
+			return "done", nil
+		})
+		if err != nil || v != "done" {
+			t.Fatalf("expected the call's result, got %v, %v", v, err)
+		}
+	})
+
+	t.Run("returns the call's error", func(t *testing.T) {
+		want := errors.New("request failed with status: 500")
+		_, err := CallWithContext(context.Background(), func() (interface{}, error) {
+			return nil, want
+		})
+		if !errors.Is(err, want) {
+			t.Fatalf("expected %v, got %v", want, err)
+		}
+	})
+
+	t.Run("stops waiting at the deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		release := make(chan struct{})
+		defer close(release)
+
+		start := time.Now()
+		_, err := CallWithContext(ctx, func() (interface{}, error) {
+			<-release
+			return "too late", nil
+		})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected the deadline to be reported, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Fatalf("waited %s for a call past its deadline", elapsed)
+		}
+	})
+
+	t.Run("does not start once the deadline has passed", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		called := false
+		if _, err := CallWithContext(ctx, func() (interface{}, error) {
+			called = true
+			return nil, nil
+		}); err == nil {
+			t.Fatal("expected an error from a cancelled context")
+		}
+		if called {
+			t.Fatal("expected the call not to be made")
+		}
+	})
+}
+
+func TestIsTimeout(t *testing.T) {
+	expired, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	<-expired.Done()
+
+	cases := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want bool
+	}{
+		{"no error", expired, nil, false},
+		{"deadline exceeded", context.Background(), context.DeadlineExceeded, true},
+		{"wrapped deadline", context.Background(), errors.Join(errors.New("get"), context.DeadlineExceeded), true},
+		// The SDK flattens the errors it is given into strings, so an expired
+		// context is what gives such an error away.
+		{"flattened under an expired context", expired, errors.New("Get \"https://api\": context deadline exceeded"), true},
+		{"api error", context.Background(), errors.New("request failed with status: 500"), false},
+		{"cancelled rather than timed out", context.Background(), context.Canceled, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := IsTimeout(tc.ctx, tc.err); got != tc.want {
+				t.Fatalf("IsTimeout = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestTimeoutDiagnostics covers the message a practitioner sees: it has to say
+// which operation ran out of time and which timeouts key gives it more.
+func TestTimeoutDiagnostics(t *testing.T) {
+	cases := []struct {
+		category ErrorCategory
+		summary  string
+		key      string
+	}{
+		{ErrorCategoryCreate, "Timed out creating Role", "create = "},
+		{ErrorCategoryRead, "Timed out reading Role (ID: 42)", "read = "},
+		{ErrorCategoryUpdate, "Timed out updating Role (ID: 42)", "update = "},
+		{ErrorCategoryDelete, "Timed out deleting Role (ID: 42)", "delete = "},
+	}
+	for _, tc := range cases {
+		t.Run(string(tc.category), func(t *testing.T) {
+			id := "42"
+			if tc.category == ErrorCategoryCreate {
+				id = ""
+			}
+			diags := TimeoutDiagnostics(tc.category, "Role", id, context.DeadlineExceeded)
+			if !diags.HasError() || len(diags) != 1 {
+				t.Fatalf("expected a single error, got %v", diags)
+			}
+			if diags[0].Summary != tc.summary {
+				t.Fatalf("summary = %q, want %q", diags[0].Summary, tc.summary)
+			}
+			if !strings.Contains(diags[0].Detail, "timeouts {") || !strings.Contains(diags[0].Detail, tc.key) {
+				t.Fatalf("expected the detail to show the %q timeouts key, got %q", tc.key, diags[0].Detail)
+			}
+			if created := strings.Contains(diags[0].Detail, "terraform import"); created != (tc.category == ErrorCategoryCreate) {
+				t.Fatalf("expected only a create to warn that the object may exist and should be imported, got %q", diags[0].Detail)
+			}
+		})
+	}
+}
+
+func TestLogAndReturnErrorTimeout(t *testing.T) {
+	diags := HandleAPIError(context.Background(), context.DeadlineExceeded, ErrorCategoryUpdate, "Role", "42")
+	if len(diags) != 1 || diags[0].Summary != "Timed out updating Role (ID: 42)" {
+		t.Fatalf("expected timeout diagnostics, got %v", diags)
+	}
+
+	diags = HandleAPIError(context.Background(), errors.New("request failed with status: 500"), ErrorCategoryUpdate, "Role", "42")
+	if len(diags) != 1 || strings.HasPrefix(diags[0].Summary, "Timed out") {
+		t.Fatalf("expected an API error to be reported as it was, got %v", diags)
+	}
+}
+
+func TestContextOperation(t *testing.T) {
+	d := deletableResource(t, "42")
+
+	op := ContextOperation(ErrorCategoryRead, "User", func(ctx context.Context, d *schema.ResourceData, m interface{}) error {
+		<-ctx.Done()
+		return errors.New("Get \"https://api\": context deadline exceeded")
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if diags := op(ctx, d, nil); len(diags) != 1 || diags[0].Summary != "Timed out reading User (ID: 42)" {
+		t.Fatalf("expected timeout diagnostics, got %v", diags)
+	}
+
+	op = ContextOperation(ErrorCategoryRead, "User", func(context.Context, *schema.ResourceData, interface{}) error {
+		return errors.New("boom")
+	})
+	if diags := op(context.Background(), d, nil); len(diags) != 1 || diags[0].Summary != "boom" {
+		t.Fatalf("expected the error as it was, got %v", diags)
+	}
+
+	op = ContextOperation(ErrorCategoryRead, "User", func(context.Context, *schema.ResourceData, interface{}) error {
+		return nil
+	})
+	if diags := op(context.Background(), d, nil); diags != nil {
+		t.Fatalf("expected no diagnostics, got %v", diags)
+	}
+}
+
+func TestStandardDeleteFuncTimeout(t *testing.T) {
+	d := deletableResource(t, "12345")
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	release := make(chan struct{})
+	defer close(release)
+
+	diags := StandardDeleteFunc(ctx, d, func(string) (interface{}, error) {
+		<-release
+		return nil, nil
+	}, "Thing")
+
+	if len(diags) != 1 || diags[0].Summary != "Timed out deleting Thing (ID: 12345)" {
+		t.Fatalf("expected timeout diagnostics, got %v", diags)
+	}
+	if d.Id() == "" {
+		t.Fatal("expected the id to survive a delete that did not finish")
+	}
+}